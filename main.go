@@ -17,6 +17,9 @@ func main() {
 	const databaseProvider = "postgres"
 	database.Initialize(databaseProvider, config.GetDatabasePath())
 	user.CreateRoot()
+	manager.StartPerformerDuplicateJob()
+	manager.StartAPICallFlushJob()
+	manager.StartNotificationDigestJob()
 	api.Start()
 	blockForever()
 }
@@ -0,0 +1,276 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+// auditUser is the snapshot of a user in the audit log. Credentials are
+// left out.
+type auditUser struct {
+	ID           uuid.UUID         `json:"id"`
+	Name         string            `json:"name"`
+	Email        string            `json:"email"`
+	Roles        []models.RoleEnum `json:"roles"`
+	InviteTokens int               `json:"invite_tokens"`
+	TOTPEnabled  bool              `json:"totp_enabled"`
+}
+
+func newAuditUser(u *models.User, roles []models.RoleEnum) *auditUser {
+	return &auditUser{
+		ID:           u.ID,
+		Name:         u.Name,
+		Email:        u.Email,
+		Roles:        roles,
+		InviteTokens: u.InviteTokens,
+		TOTPEnabled:  u.TOTPEnabled,
+	}
+}
+
+// getAuditUser returns the snapshot of the user with the given id, or nil if
+// there is no such user.
+func getAuditUser(tx *sqlx.Tx, id uuid.UUID) (*auditUser, error) {
+	qb := models.NewUserQueryBuilder(tx)
+	u, err := qb.Find(id)
+	if err != nil || u == nil {
+		return nil, err
+	}
+
+	roles, err := qb.GetRoles(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return newAuditUser(u, roles.ToRoles()), nil
+}
+
+// auditPerformer is the snapshot of a performer in the audit log, including
+// the joined aliases, URLs, body modifications and images.
+type auditPerformer struct {
+	*models.Performer
+	Aliases   []string                   `json:"aliases"`
+	URLs      []*models.URL              `json:"urls"`
+	Tattoos   []*models.BodyModification `json:"tattoos"`
+	Piercings []*models.BodyModification `json:"piercings"`
+	ImageIDs  []uuid.UUID                `json:"image_ids"`
+}
+
+// getAuditPerformer returns the snapshot of the performer with the given id,
+// or nil if there is no such performer.
+func getAuditPerformer(tx *sqlx.Tx, id uuid.UUID) (*auditPerformer, error) {
+	qb := models.NewPerformerQueryBuilder(tx)
+	performer, err := qb.Find(id)
+	if err != nil || performer == nil {
+		return nil, err
+	}
+
+	ret := &auditPerformer{Performer: performer}
+
+	aliases, err := qb.GetAliases(id)
+	if err != nil {
+		return nil, err
+	}
+	ret.Aliases = aliases.ToAliases()
+
+	if ret.URLs, err = qb.GetUrls(id); err != nil {
+		return nil, err
+	}
+
+	tattoos, err := qb.GetTattoos(id)
+	if err != nil {
+		return nil, err
+	}
+	ret.Tattoos = tattoos.ToBodyModifications()
+
+	piercings, err := qb.GetPiercings(id)
+	if err != nil {
+		return nil, err
+	}
+	ret.Piercings = piercings.ToBodyModifications()
+
+	images, err := qb.GetImages(id)
+	if err != nil {
+		return nil, err
+	}
+	for _, image := range images {
+		ret.ImageIDs = append(ret.ImageIDs, image.ImageID)
+	}
+
+	return ret, nil
+}
+
+// auditScene is the snapshot of a scene in the audit log, including the
+// joined URLs, performers, tags, images and fingerprints.
+type auditScene struct {
+	*models.Scene
+	URLs         models.SceneUrls        `json:"urls"`
+	Performers   models.PerformersScenes `json:"performers"`
+	TagIDs       []uuid.UUID             `json:"tag_ids"`
+	ImageIDs     []uuid.UUID             `json:"image_ids"`
+	Fingerprints []*models.Fingerprint   `json:"fingerprints"`
+}
+
+// getAuditScene returns the snapshot of the scene with the given id, or nil
+// if there is no such scene.
+func getAuditScene(tx *sqlx.Tx, id uuid.UUID) (*auditScene, error) {
+	qb := models.NewSceneQueryBuilder(tx)
+	scene, err := qb.Find(id)
+	if err != nil || scene == nil {
+		return nil, err
+	}
+
+	ret := &auditScene{Scene: scene}
+
+	if ret.URLs, err = qb.GetUrls(id); err != nil {
+		return nil, err
+	}
+
+	if ret.Performers, err = qb.GetPerformers(id); err != nil {
+		return nil, err
+	}
+
+	tqb := models.NewTagQueryBuilder(tx)
+	tags, err := tqb.FindBySceneID(id)
+	if err != nil {
+		return nil, err
+	}
+	for _, tag := range tags {
+		ret.TagIDs = append(ret.TagIDs, tag.ID)
+	}
+
+	iqb := models.NewImageQueryBuilder(tx)
+	images, err := iqb.FindBySceneID(id)
+	if err != nil {
+		return nil, err
+	}
+	for _, image := range images {
+		ret.ImageIDs = append(ret.ImageIDs, image.ID)
+	}
+
+	if ret.Fingerprints, err = qb.GetFingerprints(id); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// auditStudio is the snapshot of a studio in the audit log, including the
+// joined aliases, URLs and images.
+type auditStudio struct {
+	*models.Studio
+	Aliases  []string      `json:"aliases"`
+	URLs     []*models.URL `json:"urls"`
+	ImageIDs []uuid.UUID   `json:"image_ids"`
+}
+
+// getAuditStudio returns the snapshot of the studio with the given id, or nil
+// if there is no such studio.
+func getAuditStudio(tx *sqlx.Tx, id uuid.UUID) (*auditStudio, error) {
+	qb := models.NewStudioQueryBuilder(tx)
+	studio, err := qb.Find(id)
+	if err != nil || studio == nil {
+		return nil, err
+	}
+
+	ret := &auditStudio{Studio: studio}
+
+	aliases, err := qb.GetAliases(id)
+	if err != nil {
+		return nil, err
+	}
+	ret.Aliases = aliases.ToAliases()
+
+	urls, err := qb.GetUrls(id)
+	if err != nil {
+		return nil, err
+	}
+	ret.URLs = urls.ToURLs()
+
+	images, err := qb.GetImages(id)
+	if err != nil {
+		return nil, err
+	}
+	for _, image := range images {
+		ret.ImageIDs = append(ret.ImageIDs, image.ImageID)
+	}
+
+	return ret, nil
+}
+
+// getAuditTarget returns the snapshot of the target of an edit, or nil if
+// there is no such target.
+func getAuditTarget(tx *sqlx.Tx, targetType models.AuditTargetTypeEnum, id uuid.UUID) (interface{}, error) {
+	switch targetType {
+	case models.AuditTargetTypeEnumPerformer:
+		return getAuditPerformer(tx, id)
+	case models.AuditTargetTypeEnumStudio:
+		return getAuditStudio(tx, id)
+	case models.AuditTargetTypeEnumTag:
+		qb := models.NewTagQueryBuilder(tx)
+		return qb.Find(id)
+	case models.AuditTargetTypeEnumGroup:
+		qb := models.NewGroupQueryBuilder(tx)
+		return qb.Find(id)
+	default:
+		return nil, errors.New("unsupported audit target type: " + targetType.String())
+	}
+}
+
+func toAuditJSON(v interface{}) (sql.NullString, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+
+	// nil values, including nil pointers, are stored as null
+	if string(data) == "null" {
+		return sql.NullString{}, nil
+	}
+
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
+
+// logAudit records a privileged action of the current user in the audit log.
+// The entry is written in the transaction making the change, so it is only
+// kept if the change is. before and after are snapshots of the target, stored
+// as JSON, and are nil if the target did not exist.
+func logAudit(ctx context.Context, tx *sqlx.Tx, action string, targetType models.AuditTargetTypeEnum, targetID uuid.UUID, before interface{}, after interface{}) error {
+	beforeJSON, err := toAuditJSON(before)
+	if err != nil {
+		return err
+	}
+
+	afterJSON, err := toAuditJSON(after)
+	if err != nil {
+		return err
+	}
+
+	entry := models.AuditLogEntry{
+		ID:         uuid.Must(uuid.NewV4()),
+		Action:     action,
+		TargetType: targetType.String(),
+		TargetID:   targetID,
+		Before:     beforeJSON,
+		After:      afterJSON,
+		CreatedAt:  models.SQLiteTimestamp{Timestamp: time.Now()},
+	}
+
+	if currentUser := getCurrentUser(ctx); currentUser != nil {
+		entry.UserID = uuid.NullUUID{UUID: currentUser.ID, Valid: true}
+		entry.UserName = currentUser.Name
+	}
+
+	entry.IP, _ = ctx.Value(ContextIP).(string)
+
+	qb := models.NewAuditLogQueryBuilder(tx)
+	_, err = qb.Create(entry)
+	return err
+}
@@ -0,0 +1,198 @@
+// +build integration
+
+package api_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stashapp/stash-box/pkg/api"
+	"github.com/stashapp/stash-box/pkg/database"
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+type auditLogTestRunner struct {
+	testRunner
+}
+
+func createAuditLogTestRunner(t *testing.T) *auditLogTestRunner {
+	return &auditLogTestRunner{
+		testRunner: *asAdmin(t),
+	}
+}
+
+func (s *auditLogTestRunner) testAuditLog() {
+	createdTag, err := s.createTestTag(nil)
+	if err != nil {
+		return
+	}
+
+	tagID := createdTag.ID.String()
+	newName := s.generateTagName()
+	_, err = s.resolver.Mutation().TagUpdate(s.ctx, models.TagUpdateInput{
+		ID:   tagID,
+		Name: &newName,
+	})
+	if err != nil {
+		s.t.Errorf("Error updating tag: %s", err.Error())
+		return
+	}
+
+	result, err := s.resolver.Query().QueryAuditLog(s.ctx, &models.AuditLogFilterType{
+		TargetID: &tagID,
+	}, nil)
+	if err != nil {
+		s.t.Errorf("Error querying audit log: %s", err.Error())
+		return
+	}
+
+	if result.Count != 2 || len(result.Entries) != 2 {
+		s.t.Errorf("Incorrect audit log count: got %d, want 2", result.Count)
+		return
+	}
+
+	// most recent first
+	update, create := result.Entries[0], result.Entries[1]
+	if update.Action != "tagUpdate" || create.Action != "tagCreate" {
+		s.t.Errorf("Incorrect audit log actions: %s, %s", update.Action, create.Action)
+	}
+
+	if update.UserName != userDB.admin.Name || !update.UserID.Valid || update.UserID.UUID != userDB.admin.ID {
+		s.t.Errorf("Incorrect audit log actor: %s", update.UserName)
+	}
+
+	if targetType, _ := s.resolver.AuditLogEntry().TargetType(s.ctx, update); targetType != models.AuditTargetTypeEnumTag {
+		s.t.Errorf("Incorrect audit log target type: %s", targetType)
+	}
+
+	if create.Before.Valid || !create.After.Valid {
+		s.t.Error("Expected create entry to only have an after snapshot")
+	}
+
+	var before, after models.Tag
+	if err := json.Unmarshal([]byte(update.Before.String), &before); err != nil {
+		s.t.Errorf("Error decoding before snapshot: %s", err.Error())
+		return
+	}
+	if err := json.Unmarshal([]byte(update.After.String), &after); err != nil {
+		s.t.Errorf("Error decoding after snapshot: %s", err.Error())
+		return
+	}
+	if before.Name != createdTag.Name || after.Name != newName {
+		s.t.Errorf("Incorrect snapshots: before %s, after %s", before.Name, after.Name)
+	}
+
+	action := "tagCreate"
+	result, err = s.resolver.Query().QueryAuditLog(s.ctx, &models.AuditLogFilterType{
+		TargetID: &tagID,
+		Action:   &action,
+	}, nil)
+	if err != nil || result.Count != 1 {
+		s.t.Errorf("Expected one entry filtered by action, got %v, %v", result, err)
+	}
+
+	// entries cannot be changed
+	if _, err := database.DB.Exec("DELETE FROM audit_log WHERE id = $1", update.ID); err == nil {
+		s.t.Error("Expected error deleting audit log entry")
+	}
+}
+
+// auditPerformerSnapshot holds the performer snapshot fields checked by the
+// tests.
+type auditPerformerSnapshot struct {
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases"`
+}
+
+func (s *auditLogTestRunner) testAuditLogSnapshots() {
+	alias := "audit alias"
+	performer, err := s.createTestPerformer(&models.PerformerCreateInput{
+		Name:    s.generatePerformerName(),
+		Aliases: []string{alias},
+	})
+	if err != nil {
+		return
+	}
+
+	performerID := performer.ID.String()
+	newName := s.generatePerformerName()
+	edit, err := s.createTestPerformerEdit(models.OperationEnumModify, &models.PerformerEditDetailsInput{
+		Name: &newName,
+	}, &models.EditInput{
+		Operation: models.OperationEnumModify,
+		ID:        &performerID,
+	}, nil)
+	if err != nil {
+		return
+	}
+	if _, err := s.applyEdit(edit.ID.String()); err != nil {
+		return
+	}
+
+	result, err := s.resolver.Query().QueryAuditLog(s.ctx, &models.AuditLogFilterType{
+		TargetID: &performerID,
+	}, nil)
+	if err != nil {
+		s.t.Errorf("Error querying audit log: %s", err.Error())
+		return
+	}
+
+	entries := make(map[string]*models.AuditLogEntry)
+	for _, entry := range result.Entries {
+		entries[entry.Action] = entry
+	}
+
+	// snapshots include the joined data of the performer
+	if create := entries["performerCreate"]; create == nil {
+		s.t.Error("Missing performerCreate audit log entry")
+	} else {
+		var after auditPerformerSnapshot
+		if err := json.Unmarshal([]byte(create.After.String), &after); err != nil {
+			s.t.Errorf("Error decoding after snapshot: %s", err.Error())
+		} else if len(after.Aliases) != 1 || after.Aliases[0] != alias {
+			s.t.Errorf("Incorrect snapshot aliases: got %v, want [%s]", after.Aliases, alias)
+		}
+	}
+
+	// applying the edit records the target performer before and after
+	if apply := entries["applyEdit"]; apply == nil {
+		s.t.Error("Missing applyEdit audit log entry for the target performer")
+	} else {
+		var before, after auditPerformerSnapshot
+		if err := json.Unmarshal([]byte(apply.Before.String), &before); err != nil {
+			s.t.Errorf("Error decoding before snapshot: %s", err.Error())
+			return
+		}
+		if err := json.Unmarshal([]byte(apply.After.String), &after); err != nil {
+			s.t.Errorf("Error decoding after snapshot: %s", err.Error())
+			return
+		}
+		if before.Name != performer.Name || after.Name != newName {
+			s.t.Errorf("Incorrect snapshots: before %s, after %s", before.Name, after.Name)
+		}
+	}
+}
+
+func (s *auditLogTestRunner) testUnauthorisedAuditLogQuery() {
+	_, err := s.resolver.Query().QueryAuditLog(s.ctx, nil, nil)
+	if err != api.ErrUnauthorized {
+		s.t.Errorf("QueryAuditLog: got %v want %v", err, api.ErrUnauthorized)
+	}
+}
+
+func TestAuditLog(t *testing.T) {
+	pt := createAuditLogTestRunner(t)
+	pt.testAuditLog()
+}
+
+func TestAuditLogSnapshots(t *testing.T) {
+	pt := createAuditLogTestRunner(t)
+	pt.testAuditLogSnapshots()
+}
+
+func TestUnauthorisedAuditLogQuery(t *testing.T) {
+	pt := &auditLogTestRunner{
+		testRunner: *asModify(t),
+	}
+	pt.testUnauthorisedAuditLogQuery()
+}
@@ -7,9 +7,11 @@ import (
 	"github.com/gofrs/uuid"
 
 	"github.com/stashapp/stash-box/pkg/models"
+	"github.com/stashapp/stash-box/pkg/user"
 )
 
 var ErrUnauthorized = errors.New("Not authorized")
+var ErrNamedAPIKeyManagement = errors.New("API keys cannot be managed with a named API key")
 
 func getCurrentUser(ctx context.Context) *models.User {
 	userCtxVal := ctx.Value(ContextUser)
@@ -21,14 +23,34 @@ func getCurrentUser(ctx context.Context) *models.User {
 	return nil
 }
 
-func validateRole(ctx context.Context, requiredRole models.RoleEnum) error {
-	var roles []models.RoleEnum
+// getCurrentSession returns the login session of the request, or nil if the
+// request was not authenticated by a session.
+func getCurrentSession(ctx context.Context) *models.UserSession {
+	session, _ := ctx.Value(ContextSession).(*models.UserSession)
+	return session
+}
+
+// getCurrentRoles returns the roles granted to the request, which are
+// restricted to the roles of the key for named API keys.
+func getCurrentRoles(ctx context.Context) []models.RoleEnum {
+	roles, _ := ctx.Value(ContextRoles).([]models.RoleEnum)
+	return roles
+}
 
-	roleCtxVal := ctx.Value(ContextRoles)
-	if roleCtxVal != nil {
-		roles = roleCtxVal.([]models.RoleEnum)
+// validateNotNamedAPIKey returns an error if the request was authenticated by
+// a named API key. Named keys may not manage API keys, so that a key cannot
+// be used to obtain a key with more roles than it has.
+func validateNotNamedAPIKey(ctx context.Context) error {
+	if namedKey, _ := ctx.Value(ContextNamedAPIKey).(bool); namedKey {
+		return ErrNamedAPIKeyManagement
 	}
 
+	return nil
+}
+
+func validateRole(ctx context.Context, requiredRole models.RoleEnum) error {
+	roles := getCurrentRoles(ctx)
+
 	valid := false
 
 	for _, role := range roles {
@@ -42,6 +64,13 @@ func validateRole(ctx context.Context, requiredRole models.RoleEnum) error {
 		return ErrUnauthorized
 	}
 
+	// suspended users keep read access only
+	if requiredRole != models.RoleEnumRead {
+		if currentUser := getCurrentUser(ctx); currentUser != nil {
+			return user.ValidateNotSuspended(nil, currentUser)
+		}
+	}
+
 	return nil
 }
 
@@ -49,6 +78,10 @@ func validateRead(ctx context.Context) error {
 	return validateRole(ctx, models.RoleEnumRead)
 }
 
+func validateVote(ctx context.Context) error {
+	return validateRole(ctx, models.RoleEnumVote)
+}
+
 func validateModify(ctx context.Context) error {
 	return validateRole(ctx, models.RoleEnumModify)
 }
@@ -7,4 +7,7 @@ type key int
 const (
 	ContextUser key = iota
 	ContextRoles
+	ContextSession
+	ContextIP
+	ContextNamedAPIKey
 )
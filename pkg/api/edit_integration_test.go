@@ -99,6 +99,89 @@ func (s *editTestRunner) verifyEditComment(edit *models.Edit, comment string) {
 	}
 }
 
+func (s *editTestRunner) testEditVoteStatistics() {
+	editor := asEdit(s.t)
+	createdEdit, err := editor.createTestTagEdit(models.OperationEnumCreate, nil, nil)
+	if err != nil {
+		return
+	}
+
+	editorID := userDB.edit.ID.String()
+	voterID := userDB.admin.ID.String()
+	editorBefore, _ := s.resolver.Query().FindUser(s.ctx, &editorID, nil)
+	voterBefore, _ := s.resolver.Query().FindUser(s.ctx, &voterID, nil)
+
+	comment := "looks good"
+	votedEdit, err := s.resolver.Mutation().EditVote(s.ctx, models.EditVoteInput{
+		ID:      createdEdit.ID.String(),
+		Comment: &comment,
+		Type:    models.VoteTypeEnumAccept,
+	})
+	if err != nil {
+		s.t.Errorf("Error voting on edit: %s", err.Error())
+		return
+	}
+	if votedEdit.VoteCount != 1 {
+		s.fieldMismatch(1, votedEdit.VoteCount, "VoteCount")
+	}
+	votes, _ := s.resolver.Edit().Votes(s.ctx, votedEdit)
+	if len(votes) != 1 || votes[0].Type != models.VoteTypeEnumAccept.String() {
+		s.fieldMismatch(models.VoteTypeEnumAccept, votes, "Votes")
+	}
+
+	// voting on your own edit is not allowed
+	_, err = editor.resolver.Mutation().EditVote(editor.ctx, models.EditVoteInput{
+		ID:   createdEdit.ID.String(),
+		Type: models.VoteTypeEnumAccept,
+	})
+	if err == nil {
+		s.t.Error("Expected error voting on own edit")
+	}
+
+	// a comment vote would replace the accept vote
+	_, err = s.resolver.Mutation().EditVote(s.ctx, models.EditVoteInput{
+		ID:      createdEdit.ID.String(),
+		Comment: &comment,
+		Type:    models.VoteTypeEnumComment,
+	})
+	if err == nil {
+		s.t.Error("Expected error for comment vote")
+	}
+
+	if _, err := s.applyEdit(createdEdit.ID.String()); err != nil {
+		return
+	}
+
+	editorAfter, _ := s.resolver.Query().FindUser(s.ctx, &editorID, nil)
+	voterAfter, _ := s.resolver.Query().FindUser(s.ctx, &voterID, nil)
+	if editorAfter.SuccessfulEdits != editorBefore.SuccessfulEdits+1 {
+		s.fieldMismatch(editorBefore.SuccessfulEdits+1, editorAfter.SuccessfulEdits, "SuccessfulEdits")
+	}
+	if voterAfter.SuccessfulVotes != voterBefore.SuccessfulVotes+1 {
+		s.fieldMismatch(voterBefore.SuccessfulVotes+1, voterAfter.SuccessfulVotes, "SuccessfulVotes")
+	}
+
+	result, err := s.resolver.Query().QueryUsers(s.ctx, &models.UserFilterType{
+		SuccessfulEdits: &models.IntCriterionInput{
+			Value:    editorAfter.SuccessfulEdits - 1,
+			Modifier: models.CriterionModifierGreaterThan,
+		},
+	}, nil)
+	if err != nil {
+		s.t.Errorf("Error querying users: %s", err.Error())
+		return
+	}
+	found := false
+	for _, u := range result.Users {
+		if u.ID == userDB.edit.ID {
+			found = true
+		}
+	}
+	if !found {
+		s.t.Errorf("Expected user %s in users filtered by successful edits", userDB.edit.Name)
+	}
+}
+
 func TestUnauthorisedEditEdit(t *testing.T) {
 	pt := &editTestRunner{
 		testRunner: *asRead(t),
@@ -129,3 +212,8 @@ func TestEditComment(t *testing.T) {
 	pt := createEditTestRunner(t)
 	pt.testEditComment()
 }
+
+func TestEditVoteStatistics(t *testing.T) {
+	pt := createEditTestRunner(t)
+	pt.testEditVoteStatistics()
+}
@@ -0,0 +1,373 @@
+// +build integration
+
+package api_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stashapp/stash-box/pkg/api"
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+type groupTestRunner struct {
+	testRunner
+	groupSuffix int
+}
+
+func createGroupTestRunner(t *testing.T) *groupTestRunner {
+	return &groupTestRunner{
+		testRunner: *asModify(t),
+	}
+}
+
+func (s *groupTestRunner) generateGroupTitle() string {
+	s.groupSuffix += 1
+	return "groupTestRunner-" + strconv.Itoa(s.groupSuffix)
+}
+
+func (s *groupTestRunner) testCreateGroup() {
+	studio, err := s.createTestStudio(nil)
+	if err != nil {
+		return
+	}
+
+	scene1, err := s.createTestScene(nil)
+	if err != nil {
+		return
+	}
+
+	scene2, err := s.createTestScene(nil)
+	if err != nil {
+		return
+	}
+
+	studioID := studio.ID.String()
+	date := "2003-02-01"
+	sceneNumber1 := 2
+	sceneNumber2 := 1
+
+	input := models.GroupCreateInput{
+		Title:    s.generateGroupTitle(),
+		StudioID: &studioID,
+		Date:     &date,
+		Urls: []*models.URLInput{
+			&models.URLInput{
+				URL:  "URL",
+				Type: "Type",
+			},
+		},
+		Scenes: []*models.GroupSceneInput{
+			&models.GroupSceneInput{
+				SceneID:     scene1.ID.String(),
+				SceneNumber: &sceneNumber1,
+			},
+			&models.GroupSceneInput{
+				SceneID:     scene2.ID.String(),
+				SceneNumber: &sceneNumber2,
+			},
+		},
+	}
+
+	group, err := s.resolver.Mutation().GroupCreate(s.ctx, input)
+	if err != nil {
+		s.t.Errorf("Error creating group: %s", err.Error())
+		return
+	}
+
+	s.verifyCreatedGroup(input, group)
+}
+
+func (s *groupTestRunner) verifyCreatedGroup(input models.GroupCreateInput, group *models.Group) {
+	// ensure basic attributes are set correctly
+	if input.Title != group.Title {
+		s.fieldMismatch(input.Title, group.Title, "Title")
+	}
+
+	r := s.resolver.Group()
+
+	id, _ := r.ID(s.ctx, group)
+	if id == "" {
+		s.t.Errorf("Expected created group id to be non-zero")
+	}
+
+	date, _ := r.Date(s.ctx, group)
+	if input.Date != nil && (date == nil || *input.Date != *date) {
+		s.fieldMismatch(input.Date, date, "Date")
+	}
+
+	urls, _ := r.Urls(s.ctx, group)
+	if !compareUrls(input.Urls, urls) {
+		s.fieldMismatch(input.Urls, urls, "Urls")
+	}
+
+	scenes, _ := r.Scenes(s.ctx, group)
+	if len(scenes) != len(input.Scenes) {
+		s.fieldMismatch(len(input.Scenes), len(scenes), "Scenes")
+		return
+	}
+
+	// scenes are returned in scene number order
+	for i := 1; i < len(scenes); i++ {
+		if scenes[i-1].SceneNumber.Int64 > scenes[i].SceneNumber.Int64 {
+			s.t.Errorf("Group scenes not ordered by scene number")
+		}
+	}
+}
+
+func (s *groupTestRunner) createTestGroup(input *models.GroupCreateInput) (*models.Group, error) {
+	s.t.Helper()
+	if input == nil {
+		input = &models.GroupCreateInput{
+			Title: s.generateGroupTitle(),
+		}
+	}
+
+	createdGroup, err := s.resolver.Mutation().GroupCreate(s.ctx, *input)
+
+	if err != nil {
+		s.t.Errorf("Error creating group: %s", err.Error())
+		return nil, err
+	}
+
+	return createdGroup, nil
+}
+
+func (s *groupTestRunner) testFindGroupById() {
+	createdGroup, err := s.createTestGroup(nil)
+	if err != nil {
+		return
+	}
+
+	group, err := s.resolver.Query().FindGroup(s.ctx, createdGroup.ID.String())
+	if err != nil {
+		s.t.Errorf("Error finding group: %s", err.Error())
+		return
+	}
+
+	// ensure returned group is not nil
+	if group == nil {
+		s.t.Error("Did not find group by id")
+		return
+	}
+
+	// ensure values were set
+	if createdGroup.Title != group.Title {
+		s.fieldMismatch(createdGroup.Title, group.Title, "Title")
+	}
+}
+
+func (s *groupTestRunner) testQueryGroupsBySceneID() {
+	scene, err := s.createTestScene(nil)
+	if err != nil {
+		return
+	}
+
+	createdGroup, err := s.createTestGroup(&models.GroupCreateInput{
+		Title: s.generateGroupTitle(),
+		Scenes: []*models.GroupSceneInput{
+			&models.GroupSceneInput{
+				SceneID: scene.ID.String(),
+			},
+		},
+	})
+	if err != nil {
+		return
+	}
+
+	// add a group that should not be returned
+	if _, err := s.createTestGroup(nil); err != nil {
+		return
+	}
+
+	sceneID := scene.ID.String()
+	result, err := s.resolver.Query().QueryGroups(s.ctx, &models.GroupFilterType{
+		SceneID: &sceneID,
+	}, nil)
+	if err != nil {
+		s.t.Errorf("Error querying groups: %s", err.Error())
+		return
+	}
+
+	if result.Count != 1 || result.Groups[0].ID != createdGroup.ID {
+		s.t.Errorf("Expected only group %s to be returned", createdGroup.ID.String())
+	}
+
+	sceneGroups, err := s.resolver.Scene().Groups(s.ctx, scene)
+	if err != nil {
+		s.t.Errorf("Error resolving scene groups: %s", err.Error())
+		return
+	}
+
+	if len(sceneGroups) != 1 || sceneGroups[0].Group.ID != createdGroup.ID {
+		s.t.Errorf("Expected scene to belong to group %s", createdGroup.ID.String())
+	}
+}
+
+func (s *groupTestRunner) testUpdateGroupTitle() {
+	createdGroup, err := s.createTestGroup(nil)
+	if err != nil {
+		return
+	}
+
+	groupID := createdGroup.ID.String()
+
+	updatedTitle := s.generateGroupTitle()
+	updateInput := models.GroupUpdateInput{
+		ID:    groupID,
+		Title: &updatedTitle,
+	}
+
+	// need some mocking of the context to make the field ignore behaviour work
+	ctx := s.updateContext([]string{
+		"title",
+	})
+	updatedGroup, err := s.resolver.Mutation().GroupUpdate(ctx, updateInput)
+	if err != nil {
+		s.t.Errorf("Error updating group: %s", err.Error())
+		return
+	}
+
+	if updatedGroup.Title != updatedTitle {
+		s.fieldMismatch(updatedTitle, updatedGroup.Title, "Title")
+	}
+}
+
+func (s *groupTestRunner) testDestroyGroup() {
+	createdGroup, err := s.createTestGroup(nil)
+	if err != nil {
+		return
+	}
+
+	groupID := createdGroup.ID.String()
+
+	destroyed, err := s.resolver.Mutation().GroupDestroy(s.ctx, models.GroupDestroyInput{
+		ID: groupID,
+	})
+	if err != nil {
+		s.t.Errorf("Error destroying group: %s", err.Error())
+		return
+	}
+
+	if !destroyed {
+		s.t.Error("Group was not destroyed")
+		return
+	}
+
+	// ensure cannot find group
+	foundGroup, err := s.resolver.Query().FindGroup(s.ctx, groupID)
+	if err != nil {
+		s.t.Errorf("Error finding group after destroying: %s", err.Error())
+		return
+	}
+
+	if foundGroup == nil || !foundGroup.Deleted {
+		s.t.Error("Group not deleted after destruction")
+	}
+}
+
+func (s *groupTestRunner) testDestroyGroupWithEdits() {
+	createdGroup, err := s.createTestGroup(nil)
+	if err != nil {
+		return
+	}
+
+	groupID := createdGroup.ID.String()
+	title := s.generateGroupTitle()
+	_, err = s.resolver.Mutation().GroupEdit(s.ctx, models.GroupEditInput{
+		Edit: &models.EditInput{
+			Operation: models.OperationEnumModify,
+			ID:        &groupID,
+		},
+		Details: &models.GroupEditDetailsInput{
+			Title: &title,
+		},
+	})
+	if err != nil {
+		s.t.Errorf("Error creating group edit: %s", err.Error())
+		return
+	}
+
+	// the edit still references the group after it is destroyed
+	_, err = s.resolver.Mutation().GroupDestroy(s.ctx, models.GroupDestroyInput{
+		ID: groupID,
+	})
+	if err != nil {
+		s.t.Errorf("Error destroying group with edits: %s", err.Error())
+	}
+}
+
+func (s *groupTestRunner) testUnauthorisedGroupModify() {
+	// test each api interface - all require modify so all should fail
+	_, err := s.resolver.Mutation().GroupCreate(s.ctx, models.GroupCreateInput{})
+	if err != api.ErrUnauthorized {
+		s.t.Errorf("GroupCreate: got %v want %v", err, api.ErrUnauthorized)
+	}
+
+	_, err = s.resolver.Mutation().GroupUpdate(s.ctx, models.GroupUpdateInput{})
+	if err != api.ErrUnauthorized {
+		s.t.Errorf("GroupUpdate: got %v want %v", err, api.ErrUnauthorized)
+	}
+
+	_, err = s.resolver.Mutation().GroupDestroy(s.ctx, models.GroupDestroyInput{})
+	if err != api.ErrUnauthorized {
+		s.t.Errorf("GroupDestroy: got %v want %v", err, api.ErrUnauthorized)
+	}
+}
+
+func (s *groupTestRunner) testUnauthorisedGroupQuery() {
+	// test each api interface - all require read so all should fail
+	_, err := s.resolver.Query().FindGroup(s.ctx, "")
+	if err != api.ErrUnauthorized {
+		s.t.Errorf("FindGroup: got %v want %v", err, api.ErrUnauthorized)
+	}
+
+	_, err = s.resolver.Query().QueryGroups(s.ctx, nil, nil)
+	if err != api.ErrUnauthorized {
+		s.t.Errorf("QueryGroups: got %v want %v", err, api.ErrUnauthorized)
+	}
+}
+
+func TestCreateGroup(t *testing.T) {
+	pt := createGroupTestRunner(t)
+	pt.testCreateGroup()
+}
+
+func TestFindGroupById(t *testing.T) {
+	pt := createGroupTestRunner(t)
+	pt.testFindGroupById()
+}
+
+func TestQueryGroupsBySceneID(t *testing.T) {
+	pt := createGroupTestRunner(t)
+	pt.testQueryGroupsBySceneID()
+}
+
+func TestUpdateGroupTitle(t *testing.T) {
+	pt := createGroupTestRunner(t)
+	pt.testUpdateGroupTitle()
+}
+
+func TestDestroyGroup(t *testing.T) {
+	pt := createGroupTestRunner(t)
+	pt.testDestroyGroup()
+}
+
+func TestDestroyGroupWithEdits(t *testing.T) {
+	pt := createGroupTestRunner(t)
+	pt.testDestroyGroupWithEdits()
+}
+
+func TestUnauthorisedGroupModify(t *testing.T) {
+	pt := &groupTestRunner{
+		testRunner: *asRead(t),
+	}
+	pt.testUnauthorisedGroupModify()
+}
+
+func TestUnauthorisedGroupQuery(t *testing.T) {
+	pt := &groupTestRunner{
+		testRunner: *asNone(t),
+	}
+	pt.testUnauthorisedGroupQuery()
+}
@@ -0,0 +1,167 @@
+// +build integration
+
+package api_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+type historyTestRunner struct {
+	testRunner
+}
+
+func createHistoryTestRunner(t *testing.T) *historyTestRunner {
+	return &historyTestRunner{
+		testRunner: *asAdmin(t),
+	}
+}
+
+// waitForNextSecond ensures subsequent changes are timestamped after the
+// returned time, since timestamps are stored to the second.
+func (s *historyTestRunner) waitForNextSecond() time.Time {
+	now := time.Now()
+	time.Sleep(time.Until(now.Truncate(time.Second).Add(time.Second)))
+	return now
+}
+
+func (s *historyTestRunner) testPerformerAsOf() {
+	oldName := s.generatePerformerName()
+	createdPerformer, err := s.createTestPerformer(&models.PerformerCreateInput{
+		Name:    oldName,
+		Aliases: []string{"old alias"},
+	})
+	if err != nil {
+		return
+	}
+
+	beforeEdit := s.waitForNextSecond()
+
+	id := createdPerformer.ID.String()
+	newName := s.generatePerformerName()
+	edit, err := s.createTestPerformerEdit(models.OperationEnumModify, &models.PerformerEditDetailsInput{
+		Name:    &newName,
+		Aliases: []string{"new alias"},
+	}, &models.EditInput{
+		Operation: models.OperationEnumModify,
+		ID:        &id,
+	}, nil)
+	if err != nil {
+		return
+	}
+
+	if _, err := s.applyEdit(edit.ID.String()); err != nil {
+		return
+	}
+
+	performer, err := s.resolver.Query().FindPerformer(s.ctx, id, &beforeEdit)
+	if err != nil {
+		s.t.Errorf("Error finding performer as of %s: %s", beforeEdit, err.Error())
+		return
+	}
+
+	if performer == nil || performer.Name != oldName {
+		s.t.Errorf("Expected performer name %s as of %s", oldName, beforeEdit)
+		return
+	}
+
+	aliases, _ := s.resolver.Performer().Aliases(s.ctx, performer)
+	if len(aliases) != 1 || aliases[0] != "old alias" {
+		s.fieldMismatch([]string{"old alias"}, aliases, "Aliases")
+	}
+
+	now := time.Now()
+	performer, err = s.resolver.Query().FindPerformer(s.ctx, id, &now)
+	if err != nil {
+		s.t.Errorf("Error finding performer: %s", err.Error())
+		return
+	}
+
+	if performer == nil || performer.Name != newName {
+		s.t.Errorf("Expected current performer name %s", newName)
+	}
+
+	beforeCreate := createdPerformer.CreatedAt.Timestamp.Add(-time.Second)
+	performer, err = s.resolver.Query().FindPerformer(s.ctx, id, &beforeCreate)
+	if err != nil {
+		s.t.Errorf("Error finding performer: %s", err.Error())
+		return
+	}
+
+	if performer != nil {
+		s.t.Error("Expected no performer before creation")
+	}
+
+	history, err := s.resolver.Performer().History(s.ctx, createdPerformer)
+	if err != nil {
+		s.t.Errorf("Error getting performer history: %s", err.Error())
+		return
+	}
+
+	var nameChange *models.EntityFieldChange
+	for _, change := range history {
+		if change.Field == "name" {
+			nameChange = change
+		}
+		if change.Edit.ID != edit.ID {
+			s.t.Errorf("Expected change from edit %s, got %s", edit.ID, change.Edit.ID)
+		}
+	}
+
+	if nameChange == nil || nameChange.OldValue == nil || *nameChange.OldValue != oldName || nameChange.NewValue == nil || *nameChange.NewValue != newName {
+		s.t.Errorf("Expected name change from %s to %s", oldName, newName)
+	}
+
+	user, err := s.resolver.EntityFieldChange().User(s.ctx, history[0])
+	if err != nil || user == nil || user.ID != edit.UserID {
+		s.t.Error("Expected history user to be the edit user")
+	}
+}
+
+func (s *historyTestRunner) testTagAsOf() {
+	createdTag, err := s.createTestTag(nil)
+	if err != nil {
+		return
+	}
+	oldName := createdTag.Name
+
+	beforeEdit := s.waitForNextSecond()
+
+	id := createdTag.ID.String()
+	newName := s.generateTagName()
+	edit, err := s.createTestTagEdit(models.OperationEnumModify, &models.TagEditDetailsInput{
+		Name: &newName,
+	}, &models.EditInput{
+		Operation: models.OperationEnumModify,
+		ID:        &id,
+	})
+	if err != nil {
+		return
+	}
+
+	if _, err := s.applyEdit(edit.ID.String()); err != nil {
+		return
+	}
+
+	tag, err := s.resolver.Query().FindTag(s.ctx, &id, nil, &beforeEdit)
+	if err != nil {
+		s.t.Errorf("Error finding tag as of %s: %s", beforeEdit, err.Error())
+		return
+	}
+
+	if tag == nil || tag.Name != oldName {
+		s.t.Errorf("Expected tag name %s as of %s", oldName, beforeEdit)
+	}
+}
+
+func TestPerformerAsOf(t *testing.T) {
+	pt := createHistoryTestRunner(t)
+	pt.testPerformerAsOf()
+}
+
+func TestTagAsOf(t *testing.T) {
+	pt := createHistoryTestRunner(t)
+	pt.testTagAsOf()
+}
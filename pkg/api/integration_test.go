@@ -150,6 +150,7 @@ var tagSuffix int
 var sceneChecksumSuffix int
 var userSuffix int
 var categorySuffix int
+var siteSuffix int
 
 func createTestRunner(t *testing.T, user *models.User, roles []models.RoleEnum) *testRunner {
 	resolver := api.Resolver{}
@@ -431,6 +432,31 @@ func (s *testRunner) createTestTagCategory(input *models.TagCategoryCreateInput)
 	return createdCategory, nil
 }
 
+func (s *testRunner) generateSiteName() string {
+	siteSuffix += 1
+	return "site-" + strconv.Itoa(siteSuffix)
+}
+
+func (s *testRunner) createTestSite(input *models.SiteCreateInput) (*models.Site, error) {
+	s.t.Helper()
+
+	if input == nil {
+		input = &models.SiteCreateInput{
+			Name:       s.generateSiteName(),
+			ValidTypes: []models.ValidSiteTypeEnum{models.ValidSiteTypeEnumPerformer},
+		}
+	}
+
+	createdSite, err := s.resolver.Mutation().SiteCreate(s.ctx, *input)
+
+	if err != nil {
+		s.t.Errorf("Error creating site: %s", err.Error())
+		return nil, err
+	}
+
+	return createdSite, nil
+}
+
 func (s *testRunner) createTestTagEdit(operation models.OperationEnum, detailsInput *models.TagEditDetailsInput, editInput *models.EditInput) (*models.Edit, error) {
 	s.t.Helper()
 
@@ -464,6 +490,39 @@ func (s *testRunner) createTestTagEdit(operation models.OperationEnum, detailsIn
 	return createdEdit, nil
 }
 
+func (s *testRunner) createTestStudioEdit(operation models.OperationEnum, detailsInput *models.StudioEditDetailsInput, editInput *models.EditInput) (*models.Edit, error) {
+	s.t.Helper()
+
+	if editInput == nil {
+		input := models.EditInput{
+			Operation: operation,
+		}
+		editInput = &input
+	}
+
+	if detailsInput == nil {
+		name := s.generateStudioName()
+		input := models.StudioEditDetailsInput{
+			Name: &name,
+		}
+		detailsInput = &input
+	}
+
+	studioEditInput := models.StudioEditInput{
+		Edit:    editInput,
+		Details: detailsInput,
+	}
+
+	createdEdit, err := s.resolver.Mutation().StudioEdit(s.ctx, studioEditInput)
+
+	if err != nil {
+		s.t.Errorf("Error creating edit: %s", err.Error())
+		return nil, err
+	}
+
+	return createdEdit, nil
+}
+
 func (s *testRunner) applyEdit(id string) (*models.Edit, error) {
 	s.t.Helper()
 
@@ -0,0 +1,297 @@
+// +build integration
+
+package api_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stashapp/stash-box/pkg/models"
+	"github.com/stashapp/stash-box/pkg/user"
+)
+
+type notificationTestRunner struct {
+	testRunner
+}
+
+func createNotificationTestRunner(t *testing.T) *notificationTestRunner {
+	return &notificationTestRunner{
+		testRunner: *asAdmin(t),
+	}
+}
+
+func (s *notificationTestRunner) testNotifications() {
+	author, err := s.createTestUser(nil)
+	if err != nil {
+		return
+	}
+	otherEditor, err := s.createTestUser(nil)
+	if err != nil {
+		return
+	}
+	asAuthor := createTestRunner(s.t, author, userDB.adminRoles)
+	asOtherEditor := createTestRunner(s.t, otherEditor, userDB.adminRoles)
+
+	// the author does not want to be notified of votes
+	_, err = s.resolver.Mutation().UpdateNotificationPreferences(asAuthor.ctx, []*models.NotificationPreferenceInput{
+		{
+			Type:    models.NotificationTypeEnumEditVote,
+			Enabled: false,
+			Email:   false,
+		},
+	})
+	if err != nil {
+		s.t.Errorf("Error updating notification preferences: %s", err.Error())
+		return
+	}
+
+	prefs, err := s.resolver.Query().NotificationPreferences(asAuthor.ctx)
+	if err != nil {
+		s.t.Errorf("Error getting notification preferences: %s", err.Error())
+		return
+	}
+	if len(prefs) != len(models.AllNotificationTypeEnum) {
+		s.t.Errorf("Incorrect notification preference count: got %d, want %d", len(prefs), len(models.AllNotificationTypeEnum))
+	}
+	for _, p := range prefs {
+		if p.Enabled != (p.Type != models.NotificationTypeEnumEditVote) {
+			s.t.Errorf("Incorrect notification preference for %s: %v", p.Type, p.Enabled)
+		}
+	}
+
+	createEdit, err := asAuthor.createTestPerformerEdit(models.OperationEnumCreate, nil, nil, nil)
+	if err != nil {
+		return
+	}
+	editID := createEdit.ID.String()
+
+	// commenting on your own edit does not notify you
+	if _, err := s.resolver.Mutation().EditComment(asAuthor.ctx, models.EditCommentInput{
+		ID:      editID,
+		Comment: "own comment",
+	}); err != nil {
+		s.t.Errorf("Error commenting on edit: %s", err.Error())
+		return
+	}
+
+	if _, err := s.resolver.Mutation().EditComment(s.ctx, models.EditCommentInput{
+		ID:      editID,
+		Comment: "comment",
+	}); err != nil {
+		s.t.Errorf("Error commenting on edit: %s", err.Error())
+		return
+	}
+
+	if _, err := s.resolver.Mutation().EditVote(s.ctx, models.EditVoteInput{
+		ID:   editID,
+		Type: models.VoteTypeEnumAccept,
+	}); err != nil {
+		s.t.Errorf("Error voting on edit: %s", err.Error())
+		return
+	}
+
+	appliedEdit, err := s.applyEdit(editID)
+	if err != nil {
+		return
+	}
+
+	// an edit to the same performer by another user notifies the author
+	performerID := s.getEditPerformerTarget(appliedEdit).ID.String()
+	modifyEdit, err := asOtherEditor.createTestPerformerEdit(models.OperationEnumModify, nil, &models.EditInput{
+		Operation: models.OperationEnumModify,
+		ID:        &performerID,
+	}, nil)
+	if err != nil {
+		return
+	}
+	if _, err := s.applyEdit(modifyEdit.ID.String()); err != nil {
+		return
+	}
+
+	result, err := s.resolver.Query().Notifications(asAuthor.ctx, nil, nil)
+	if err != nil {
+		s.t.Errorf("Error querying notifications: %s", err.Error())
+		return
+	}
+
+	if result.Count != 3 || result.UnreadCount != 3 || len(result.Notifications) != 3 {
+		s.t.Errorf("Incorrect notification count: got %d (%d unread), want 3", result.Count, result.UnreadCount)
+		return
+	}
+
+	r := s.resolver.Notification()
+	byType := make(map[models.NotificationTypeEnum]*models.Notification)
+	for _, n := range result.Notifications {
+		t, _ := r.Type(s.ctx, n)
+		byType[t] = n
+
+		if actor, _ := r.Actor(s.ctx, n); actor == nil || actor.ID != userDB.admin.ID {
+			s.t.Error("Incorrect notification actor")
+		}
+	}
+
+	expectedEdits := map[models.NotificationTypeEnum]*models.Edit{
+		models.NotificationTypeEnumEditComment:          createEdit,
+		models.NotificationTypeEnumEditApplied:          createEdit,
+		models.NotificationTypeEnumPerformerEditApplied: modifyEdit,
+	}
+	for t, expected := range expectedEdits {
+		n := byType[t]
+		if n == nil {
+			s.t.Errorf("Missing %s notification", t)
+			continue
+		}
+		if edit, _ := r.Edit(s.ctx, n); edit == nil || edit.ID != expected.ID {
+			s.t.Errorf("Incorrect edit for %s notification", t)
+		}
+	}
+
+	// other users cannot mark the notification as read
+	firstID := result.Notifications[0].ID.String()
+	marked, err := s.resolver.Mutation().MarkNotificationsRead(asOtherEditor.ctx, []string{firstID})
+	if err != nil {
+		s.t.Errorf("Error marking notifications read: %s", err.Error())
+		return
+	}
+	if marked != 0 {
+		s.t.Errorf("Incorrect marked count for other user: got %d, want 0", marked)
+	}
+
+	marked, err = s.resolver.Mutation().MarkNotificationsRead(asAuthor.ctx, []string{firstID})
+	if err != nil {
+		s.t.Errorf("Error marking notifications read: %s", err.Error())
+		return
+	}
+	if marked != 1 {
+		s.t.Errorf("Incorrect marked count: got %d, want 1", marked)
+	}
+
+	unreadOnly := true
+	result, err = s.resolver.Query().Notifications(asAuthor.ctx, &unreadOnly, nil)
+	if err != nil {
+		s.t.Errorf("Error querying notifications: %s", err.Error())
+		return
+	}
+	if result.Count != 2 || result.UnreadCount != 2 {
+		s.t.Errorf("Incorrect unread notification count: got %d, want 2", result.Count)
+	}
+
+	// marking all notifications skips those already read
+	marked, err = s.resolver.Mutation().MarkNotificationsRead(asAuthor.ctx, nil)
+	if err != nil {
+		s.t.Errorf("Error marking notifications read: %s", err.Error())
+		return
+	}
+	if marked != 2 {
+		s.t.Errorf("Incorrect marked count: got %d, want 2", marked)
+	}
+
+	result, err = s.resolver.Query().Notifications(asAuthor.ctx, nil, nil)
+	if err != nil {
+		s.t.Errorf("Error querying notifications: %s", err.Error())
+		return
+	}
+	if result.Count != 3 || result.UnreadCount != 0 {
+		s.t.Errorf("Incorrect notification count after marking read: got %d (%d unread), want 3 (0 unread)", result.Count, result.UnreadCount)
+	}
+}
+
+// testNotificationSender records the digests sent to each address, and
+// fails to send to the rejected address.
+type testNotificationSender struct {
+	rejected string
+	sent     map[string]int
+}
+
+func (s *testNotificationSender) SendNotification(email, subject, body string) error {
+	if email == s.rejected {
+		return errors.New("address rejected")
+	}
+
+	s.sent[email]++
+	return nil
+}
+
+// createCommentedEdit creates an edit by a new user who is emailed about
+// comments, and comments on it.
+func (s *notificationTestRunner) createCommentedEdit() *models.User {
+	author, err := s.createTestUser(nil)
+	if err != nil {
+		return nil
+	}
+	asAuthor := createTestRunner(s.t, author, userDB.adminRoles)
+
+	_, err = s.resolver.Mutation().UpdateNotificationPreferences(asAuthor.ctx, []*models.NotificationPreferenceInput{
+		{
+			Type:    models.NotificationTypeEnumEditComment,
+			Enabled: true,
+			Email:   true,
+		},
+	})
+	if err != nil {
+		s.t.Errorf("Error updating notification preferences: %s", err.Error())
+		return nil
+	}
+
+	edit, err := asAuthor.createTestPerformerEdit(models.OperationEnumCreate, nil, nil, nil)
+	if err != nil {
+		return nil
+	}
+
+	if _, err := s.resolver.Mutation().EditComment(s.ctx, models.EditCommentInput{
+		ID:      edit.ID.String(),
+		Comment: "comment",
+	}); err != nil {
+		s.t.Errorf("Error commenting on edit: %s", err.Error())
+		return nil
+	}
+
+	return author
+}
+
+func (s *notificationTestRunner) testNotificationDigest() {
+	rejectedUser := s.createCommentedEdit()
+	otherUser := s.createCommentedEdit()
+	if rejectedUser == nil || otherUser == nil {
+		return
+	}
+
+	// a digest that cannot be sent does not stop the digests of other users
+	sender := &testNotificationSender{
+		rejected: rejectedUser.Email,
+		sent:     make(map[string]int),
+	}
+	if err := user.SendNotificationDigests(s.ctx, sender); err != nil {
+		s.t.Errorf("Error sending notification digests: %s", err.Error())
+		return
+	}
+	if sender.sent[otherUser.Email] != 1 {
+		s.t.Errorf("Incorrect digests sent to other user: got %d, want 1", sender.sent[otherUser.Email])
+	}
+
+	// the failed digest is sent on the next run, and the sent one is not
+	// repeated
+	sender = &testNotificationSender{
+		sent: make(map[string]int),
+	}
+	if err := user.SendNotificationDigests(s.ctx, sender); err != nil {
+		s.t.Errorf("Error sending notification digests: %s", err.Error())
+		return
+	}
+	if sender.sent[rejectedUser.Email] != 1 {
+		s.t.Errorf("Incorrect digests sent to previously rejected user: got %d, want 1", sender.sent[rejectedUser.Email])
+	}
+	if sender.sent[otherUser.Email] != 0 {
+		s.t.Errorf("Incorrect repeated digests sent to other user: got %d, want 0", sender.sent[otherUser.Email])
+	}
+}
+
+func TestNotifications(t *testing.T) {
+	pt := createNotificationTestRunner(t)
+	pt.testNotifications()
+}
+
+func TestNotificationDigest(t *testing.T) {
+	pt := createNotificationTestRunner(t)
+	pt.testNotificationDigest()
+}
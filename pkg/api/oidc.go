@@ -0,0 +1,228 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/stashapp/stash-box/pkg/database"
+	"github.com/stashapp/stash-box/pkg/logger"
+	"github.com/stashapp/stash-box/pkg/manager/config"
+	"github.com/stashapp/stash-box/pkg/oidc"
+	"github.com/stashapp/stash-box/pkg/user"
+)
+
+const oidcCookieName = "oidc"
+const oidcStateKey = "state"
+const oidcNonceKey = "nonce"
+const oidcVerifierKey = "codeVerifier"
+const oidcLinkUserKey = "linkUserID"
+
+// time allowed for the user to log in with the identity provider
+const oidcCookieMaxAge = 10 * 60
+
+var oidcProvider struct {
+	mutex    sync.Mutex
+	provider *oidc.Provider
+}
+
+// getOIDCProvider returns the configured identity provider, discovering it
+// on first use. Returns nil if OpenID Connect login is not configured.
+func getOIDCProvider(ctx context.Context) (*oidc.Provider, *config.OIDCConfig, error) {
+	c := config.GetOIDCConfig()
+	if c == nil {
+		return nil, nil, nil
+	}
+
+	oidcProvider.mutex.Lock()
+	defer oidcProvider.mutex.Unlock()
+
+	if oidcProvider.provider == nil || oidcProvider.provider.Issuer() != c.Issuer {
+		p, err := oidc.NewProvider(ctx, oidc.Config{
+			Issuer:       c.Issuer,
+			ClientID:     c.ClientID,
+			ClientSecret: c.ClientSecret,
+			RedirectURL:  c.RedirectURL,
+			Scopes:       c.Scopes,
+		}, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		oidcProvider.provider = p
+	}
+
+	return oidcProvider.provider, c, nil
+}
+
+// handleOIDCLogin redirects the user to the identity provider to log in.
+func handleOIDCLogin(w http.ResponseWriter, r *http.Request) {
+	startOIDCAuth(w, r, "")
+}
+
+// handleOIDCLink redirects the logged in user to the identity provider, to
+// link their identity to the user's account. The user must be logged in with
+// a session, so that any two-factor authentication has been passed.
+func handleOIDCLink(w http.ResponseWriter, r *http.Request) {
+	session := getCurrentSession(r.Context())
+	if session == nil {
+		http.Error(w, ErrUnauthorized.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	startOIDCAuth(w, r, session.UserID.String())
+}
+
+// startOIDCAuth redirects to the identity provider. If linkUserID is set, the
+// identity is linked to that user on return rather than logged in.
+func startOIDCAuth(w http.ResponseWriter, r *http.Request, linkUserID string) {
+	p, _, err := getOIDCProvider(r.Context())
+	if err != nil {
+		logger.Errorf("Error configuring OIDC provider: %s", err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if p == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	// an invalid cookie is replaced
+	cookie, _ := sessionStore.Get(r, oidcCookieName)
+
+	req := oidc.NewAuthRequest()
+	cookie.Values[oidcStateKey] = req.State
+	cookie.Values[oidcNonceKey] = req.Nonce
+	cookie.Values[oidcVerifierKey] = req.CodeVerifier
+	cookie.Values[oidcLinkUserKey] = linkUserID
+	cookie.Options.MaxAge = oidcCookieMaxAge
+
+	if err := cookie.Save(r, w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, p.AuthURL(req), http.StatusFound)
+}
+
+// handleOIDCCallback logs in the user returned to the redirect URL by the
+// identity provider, or links the identity to the user that started the
+// request with handleOIDCLink. Two-factor authentication is left to the
+// provider for identities that are already linked.
+func handleOIDCCallback(w http.ResponseWriter, r *http.Request) {
+	p, c, err := getOIDCProvider(r.Context())
+	if err != nil {
+		logger.Errorf("Error configuring OIDC provider: %s", err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if p == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	cookie, err := sessionStore.Get(r, oidcCookieName)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var req oidc.AuthRequest
+	req.State, _ = cookie.Values[oidcStateKey].(string)
+	req.Nonce, _ = cookie.Values[oidcNonceKey].(string)
+	req.CodeVerifier, _ = cookie.Values[oidcVerifierKey].(string)
+	linkUserID, _ := cookie.Values[oidcLinkUserKey].(string)
+
+	// the authorization request may only be used once
+	cookie.Options.MaxAge = -1
+	if err := cookie.Save(r, w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if errCode := r.FormValue("error"); errCode != "" {
+		http.Error(w, errCode+": "+r.FormValue("error_description"), http.StatusUnauthorized)
+		return
+	}
+
+	if req.State == "" || r.FormValue("state") != req.State {
+		http.Error(w, "invalid state", http.StatusBadRequest)
+		return
+	}
+
+	claims, err := p.Exchange(r.Context(), r.FormValue("code"), req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	if linkUserID != "" {
+		linkOIDCIdentity(w, r, linkUserID, claims)
+		return
+	}
+
+	loginCookie, err := sessionStore.Get(r, cookieName)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var token string
+	err = database.WithTransaction(r.Context(), func(txn database.Transaction) error {
+		u, err := user.LoginOIDC(txn.GetTx(), claims, c.AutoProvision)
+		if err != nil {
+			return err
+		}
+
+		if err := user.ValidateLoginSuspension(txn.GetTx(), u.ID); err != nil {
+			return err
+		}
+
+		token, err = createLoginSession(txn.GetTx(), r, loginCookie, u.ID)
+		return err
+	})
+
+	if err == user.ErrOIDCNoAccount || err == user.ErrOIDCEmailInUse || err == user.ErrOIDCEmailUnverified || err == user.ErrOIDCLinkRequired {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	} else if user.IsSuspendedError(err) {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if err := saveLoginSession(w, r, loginCookie, token); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/", http.StatusFound)
+}
+
+// linkOIDCIdentity links the identity to the user, who must still be logged
+// in with a session.
+func linkOIDCIdentity(w http.ResponseWriter, r *http.Request, linkUserID string, claims *oidc.Claims) {
+	session := getCurrentSession(r.Context())
+	if session == nil || session.UserID.String() != linkUserID {
+		http.Error(w, ErrUnauthorized.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	err := database.WithTransaction(r.Context(), func(txn database.Transaction) error {
+		return user.LinkOIDCIdentity(txn.GetTx(), session.UserID, claims)
+	})
+
+	if err == user.ErrOIDCIdentityInUse {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/", http.StatusFound)
+}
@@ -0,0 +1,198 @@
+// +build integration
+
+package api_test
+
+import (
+	"testing"
+
+	"github.com/stashapp/stash-box/pkg/api"
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+type performerDuplicateTestRunner struct {
+	testRunner
+}
+
+func createPerformerDuplicateTestRunner(t *testing.T) *performerDuplicateTestRunner {
+	return &performerDuplicateTestRunner{
+		testRunner: *asAdmin(t),
+	}
+}
+
+func (s *performerDuplicateTestRunner) createDuplicatePair(name string) (*models.Performer, *models.Performer, *models.PerformerDuplicate) {
+	birthdate := &models.FuzzyDateInput{
+		Date:     "1990-05-12",
+		Accuracy: models.DateAccuracyEnumDay,
+	}
+
+	performer, err := s.createTestPerformer(&models.PerformerCreateInput{
+		Name:      name,
+		Birthdate: birthdate,
+	})
+	if err != nil {
+		return nil, nil, nil
+	}
+
+	yearBirthdate := &models.FuzzyDateInput{
+		Date:     "1990",
+		Accuracy: models.DateAccuracyEnumYear,
+	}
+	duplicate, err := s.createTestPerformer(&models.PerformerCreateInput{
+		Name:      name,
+		Birthdate: yearBirthdate,
+	})
+	if err != nil {
+		return nil, nil, nil
+	}
+
+	if _, err := s.resolver.Mutation().PerformerDuplicatesRefresh(s.ctx); err != nil {
+		s.t.Errorf("Error refreshing duplicates: %s", err.Error())
+		return nil, nil, nil
+	}
+
+	performerID := performer.ID.String()
+	status := models.PerformerDuplicateStatusEnumPending
+	result, err := s.resolver.Query().QueryPerformerDuplicates(s.ctx, &models.PerformerDuplicateFilterType{
+		PerformerID: &performerID,
+		Status:      &status,
+	}, nil)
+	if err != nil {
+		s.t.Errorf("Error querying duplicates: %s", err.Error())
+		return nil, nil, nil
+	}
+
+	for _, d := range result.Duplicates {
+		if (d.PerformerID == performer.ID && d.DuplicateID == duplicate.ID) || (d.PerformerID == duplicate.ID && d.DuplicateID == performer.ID) {
+			return performer, duplicate, d
+		}
+	}
+
+	s.t.Errorf("Expected duplicate pair for %s and %s", performer.ID, duplicate.ID)
+	return nil, nil, nil
+}
+
+func (s *performerDuplicateTestRunner) testFindPerformerDuplicates() {
+	_, _, duplicate := s.createDuplicatePair("testFindPerformerDuplicates")
+	if duplicate == nil {
+		return
+	}
+
+	if !duplicate.BirthdateMatch.Valid || !duplicate.BirthdateMatch.Bool {
+		s.fieldMismatch(true, duplicate.BirthdateMatch, "BirthdateMatch")
+	}
+
+	if duplicate.NameSimilarity != 1 {
+		s.fieldMismatch(1, duplicate.NameSimilarity, "NameSimilarity")
+	}
+
+	if duplicate.Score < models.MinPerformerDuplicateScore {
+		s.t.Errorf("Expected score of at least %v, got %v", models.MinPerformerDuplicateScore, duplicate.Score)
+	}
+}
+
+func (s *performerDuplicateTestRunner) testDismissPerformerDuplicate() {
+	_, _, duplicate := s.createDuplicatePair("testDismissPerformerDuplicate")
+	if duplicate == nil {
+		return
+	}
+
+	dismissed, err := s.resolver.Mutation().PerformerDuplicateDismiss(s.ctx, duplicate.ID.String())
+	if err != nil {
+		s.t.Errorf("Error dismissing duplicate: %s", err.Error())
+		return
+	}
+
+	if dismissed.Status != models.PerformerDuplicateStatusEnumDismissed.String() {
+		s.fieldMismatch(models.PerformerDuplicateStatusEnumDismissed, dismissed.Status, "Status")
+	}
+
+	// dismissed pairs are not reset by a refresh
+	s.resolver.Mutation().PerformerDuplicatesRefresh(s.ctx)
+	if _, err := s.resolver.Mutation().PerformerDuplicateDismiss(s.ctx, duplicate.ID.String()); err == nil {
+		s.t.Errorf("Expected error dismissing reviewed duplicate")
+	}
+}
+
+func (s *performerDuplicateTestRunner) testMergePerformerDuplicate() {
+	performer, duplicate, pair := s.createDuplicatePair("testMergePerformerDuplicate")
+	if pair == nil {
+		return
+	}
+
+	targetID := duplicate.ID.String()
+	edit, err := s.resolver.Mutation().PerformerDuplicateMerge(s.ctx, models.PerformerDuplicateMergeInput{
+		ID:       pair.ID.String(),
+		TargetID: &targetID,
+	})
+	if err != nil {
+		s.t.Errorf("Error merging duplicate: %s", err.Error())
+		return
+	}
+
+	if edit.Operation != models.OperationEnumMerge.String() {
+		s.fieldMismatch(models.OperationEnumMerge, edit.Operation, "Operation")
+	}
+
+	data, err := edit.GetPerformerData()
+	if err != nil {
+		s.t.Errorf("Error getting edit data: %s", err.Error())
+		return
+	}
+
+	if len(data.MergeSources) != 1 || data.MergeSources[0] != performer.ID.String() {
+		s.fieldMismatch([]string{performer.ID.String()}, data.MergeSources, "MergeSources")
+	}
+
+	target, _ := s.resolver.Edit().Target(s.ctx, edit)
+	if p, ok := target.(*models.Performer); !ok || p.ID != duplicate.ID {
+		s.fieldMismatch(duplicate.ID, target, "Target")
+	}
+
+	performerID := performer.ID.String()
+	status := models.PerformerDuplicateStatusEnumMerged
+	result, _ := s.resolver.Query().QueryPerformerDuplicates(s.ctx, &models.PerformerDuplicateFilterType{
+		PerformerID: &performerID,
+		Status:      &status,
+	}, nil)
+	if result == nil || result.Count != 1 || !result.Duplicates[0].EditID.Valid || result.Duplicates[0].EditID.UUID != edit.ID {
+		s.t.Errorf("Expected merged pair to reference edit %s", edit.ID)
+	}
+
+	if _, err := s.resolver.Mutation().PerformerDuplicateMerge(s.ctx, models.PerformerDuplicateMergeInput{
+		ID: pair.ID.String(),
+	}); err == nil {
+		s.t.Errorf("Expected error merging reviewed duplicate")
+	}
+}
+
+func (s *performerDuplicateTestRunner) testUnauthorisedPerformerDuplicates() {
+	if _, err := s.resolver.Query().QueryPerformerDuplicates(s.ctx, nil, nil); err != api.ErrUnauthorized {
+		s.t.Errorf("QueryPerformerDuplicates: got %v want %v", err, api.ErrUnauthorized)
+	}
+
+	if _, err := s.resolver.Mutation().PerformerDuplicatesRefresh(s.ctx); err != api.ErrUnauthorized {
+		s.t.Errorf("PerformerDuplicatesRefresh: got %v want %v", err, api.ErrUnauthorized)
+	}
+}
+
+func TestFindPerformerDuplicates(t *testing.T) {
+	pt := createPerformerDuplicateTestRunner(t)
+	pt.testFindPerformerDuplicates()
+}
+
+func TestDismissPerformerDuplicate(t *testing.T) {
+	pt := createPerformerDuplicateTestRunner(t)
+	pt.testDismissPerformerDuplicate()
+}
+
+func TestMergePerformerDuplicate(t *testing.T) {
+	pt := createPerformerDuplicateTestRunner(t)
+	pt.testMergePerformerDuplicate()
+}
+
+func TestUnauthorisedPerformerDuplicates(t *testing.T) {
+	pt := &performerDuplicateTestRunner{
+		testRunner: *asModify(t),
+	}
+	pt.testUnauthorisedPerformerDuplicates()
+}
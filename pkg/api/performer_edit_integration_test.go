@@ -519,7 +519,7 @@ func (s *performerEditTestRunner) testApplyModifyPerformerEdit() {
 		return
 	}
 
-	modifiedPerformer, _ := s.resolver.Query().FindPerformer(s.ctx, id)
+	modifiedPerformer, _ := s.resolver.Query().FindPerformer(s.ctx, id, nil)
 	s.verifyApplyModifyPerformerEdit(*performerEditDetailsInput, modifiedPerformer, appliedEdit)
 }
 
@@ -668,7 +668,7 @@ func (s *performerEditTestRunner) testApplyModifyUnsetPerformerEdit() {
 		return
 	}
 
-	modifiedPerformer, _ := s.resolver.Query().FindPerformer(s.ctx, id)
+	modifiedPerformer, _ := s.resolver.Query().FindPerformer(s.ctx, id, nil)
 	s.verifyApplyModifyPerformerEdit(performerUnsetInput, modifiedPerformer, appliedEdit)
 }
 
@@ -698,7 +698,7 @@ func (s *performerEditTestRunner) testApplyDestroyPerformerEdit() {
 	}
 	appliedEdit, err := s.applyEdit(destroyEdit.ID.String())
 
-	destroyedPerformer, _ := s.resolver.Query().FindPerformer(s.ctx, performerID)
+	destroyedPerformer, _ := s.resolver.Query().FindPerformer(s.ctx, performerID, nil)
 	s.verifyApplyDestroyPerformerEdit(destroyedPerformer, appliedEdit, scene)
 }
 
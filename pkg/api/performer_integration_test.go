@@ -6,7 +6,10 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/gofrs/uuid"
+
 	"github.com/stashapp/stash-box/pkg/api"
+	"github.com/stashapp/stash-box/pkg/database"
 	"github.com/stashapp/stash-box/pkg/models"
 )
 
@@ -205,7 +208,7 @@ func (s *performerTestRunner) testFindPerformer() {
 		return
 	}
 
-	performer, err := s.resolver.Query().FindPerformer(s.ctx, createdPerformer.ID.String())
+	performer, err := s.resolver.Query().FindPerformer(s.ctx, createdPerformer.ID.String(), nil)
 	if err != nil {
 		s.t.Errorf("Error finding performer: %s", err.Error())
 		return
@@ -384,7 +387,7 @@ func (s *performerTestRunner) testDestroyPerformer() {
 	}
 
 	// ensure cannot find performer
-	foundPerformer, err := s.resolver.Query().FindPerformer(s.ctx, performerID)
+	foundPerformer, err := s.resolver.Query().FindPerformer(s.ctx, performerID, nil)
 	if err != nil {
 		s.t.Errorf("Error finding performer after destroying: %s", err.Error())
 		return
@@ -397,6 +400,198 @@ func (s *performerTestRunner) testDestroyPerformer() {
 	// TODO - ensure scene was not removed
 }
 
+func (s *performerTestRunner) createCostarScene(performers ...*models.Performer) (*models.Scene, error) {
+	var appearances []*models.PerformerAppearanceInput
+	for _, p := range performers {
+		appearances = append(appearances, &models.PerformerAppearanceInput{
+			PerformerID: p.ID.String(),
+		})
+	}
+
+	return s.createTestScene(&models.SceneCreateInput{
+		Performers: appearances,
+	})
+}
+
+func (s *performerTestRunner) testFrequentCostars() {
+	performer, _ := s.createTestPerformer(nil)
+	frequent, _ := s.createTestPerformer(nil)
+	occasional, _ := s.createTestPerformer(nil)
+
+	s.createCostarScene(performer, frequent, occasional)
+	s.createCostarScene(performer, frequent)
+
+	limit := 10
+	costars, err := s.resolver.Performer().FrequentCostars(s.ctx, performer, &limit)
+	if err != nil {
+		s.t.Errorf("Error finding costars: %s", err.Error())
+		return
+	}
+
+	if len(costars) != 2 {
+		s.t.Errorf("Expected 2 costars, got %d", len(costars))
+		return
+	}
+
+	if costars[0].PerformerID != frequent.ID || costars[0].SceneCount != 2 {
+		s.t.Errorf("Expected %s with 2 scenes, got %s with %d", frequent.ID, costars[0].PerformerID, costars[0].SceneCount)
+	}
+
+	if costars[1].PerformerID != occasional.ID || costars[1].SceneCount != 1 {
+		s.t.Errorf("Expected %s with 1 scene, got %s with %d", occasional.ID, costars[1].PerformerID, costars[1].SceneCount)
+	}
+
+	limit = 1
+	costars, _ = s.resolver.Performer().FrequentCostars(s.ctx, performer, &limit)
+	if len(costars) != 1 {
+		s.t.Errorf("Expected 1 costar, got %d", len(costars))
+	}
+}
+
+func (s *performerTestRunner) testPerformerConnections() {
+	a, _ := s.createTestPerformer(nil)
+	middle, _ := s.createTestPerformer(nil)
+	b, _ := s.createTestPerformer(nil)
+	unconnected, _ := s.createTestPerformer(nil)
+
+	first, _ := s.createCostarScene(a, middle)
+	second, _ := s.createCostarScene(middle, b)
+
+	maxDepth := 3
+	connection, err := s.resolver.Query().PerformerConnections(s.ctx, a.ID.String(), b.ID.String(), &maxDepth)
+	if err != nil {
+		s.t.Errorf("Error finding connection: %s", err.Error())
+		return
+	}
+
+	if connection == nil {
+		s.t.Errorf("Expected connection between %s and %s", a.ID, b.ID)
+		return
+	}
+
+	expectedPerformers := []uuid.UUID{a.ID, middle.ID, b.ID}
+	if !reflect.DeepEqual(connection.PerformerIDs, expectedPerformers) {
+		s.fieldMismatch(expectedPerformers, connection.PerformerIDs, "PerformerIDs")
+	}
+
+	expectedScenes := []uuid.UUID{first.ID, second.ID}
+	if !reflect.DeepEqual(connection.SceneIDs, expectedScenes) {
+		s.fieldMismatch(expectedScenes, connection.SceneIDs, "SceneIDs")
+	}
+
+	// depth too shallow to reach b
+	maxDepth = 1
+	connection, _ = s.resolver.Query().PerformerConnections(s.ctx, a.ID.String(), b.ID.String(), &maxDepth)
+	if connection != nil {
+		s.t.Errorf("Expected no connection within depth 1")
+	}
+
+	maxDepth = 3
+	connection, _ = s.resolver.Query().PerformerConnections(s.ctx, a.ID.String(), unconnected.ID.String(), &maxDepth)
+	if connection != nil {
+		s.t.Errorf("Expected no connection to unconnected performer")
+	}
+
+	// destroyed scenes no longer link the performers
+	s.resolver.Mutation().SceneDestroy(s.ctx, models.SceneDestroyInput{
+		ID: second.ID.String(),
+	})
+	connection, _ = s.resolver.Query().PerformerConnections(s.ctx, a.ID.String(), b.ID.String(), &maxDepth)
+	if connection != nil {
+		s.t.Errorf("Expected no connection through destroyed scene")
+	}
+
+	// nor do deleted performers
+	other, _ := s.createTestPerformer(nil)
+	s.createCostarScene(middle, other)
+	connection, _ = s.resolver.Query().PerformerConnections(s.ctx, a.ID.String(), other.ID.String(), &maxDepth)
+	if connection == nil {
+		s.t.Errorf("Expected connection between %s and %s", a.ID, other.ID)
+		return
+	}
+
+	err = database.WithTransaction(s.ctx, func(txn database.Transaction) error {
+		qb := models.NewPerformerQueryBuilder(txn.GetTx())
+		_, err := qb.SoftDelete(*middle)
+		return err
+	})
+	if err != nil {
+		s.t.Errorf("Error deleting performer: %s", err.Error())
+		return
+	}
+
+	connection, _ = s.resolver.Query().PerformerConnections(s.ctx, a.ID.String(), other.ID.String(), &maxDepth)
+	if connection != nil {
+		s.t.Errorf("Expected no connection through deleted performer")
+	}
+}
+
+func (s *performerTestRunner) testPerformerStats() {
+	startYear := 2005
+	performer, err := s.createTestPerformer(&models.PerformerCreateInput{
+		Name:            s.generatePerformerName(),
+		CareerStartYear: &startYear,
+	})
+	if err != nil {
+		return
+	}
+
+	studio, _ := s.createTestStudio(nil)
+	tag, _ := s.createTestTag(nil)
+	studioID := studio.ID.String()
+	appearances := []*models.PerformerAppearanceInput{
+		&models.PerformerAppearanceInput{
+			PerformerID: performer.ID.String(),
+		},
+	}
+
+	firstDate := "2004-06-01"
+	lastDate := "2006-02-01"
+	s.createTestScene(&models.SceneCreateInput{
+		Date:       &firstDate,
+		StudioID:   &studioID,
+		Performers: appearances,
+		TagIds:     []string{tag.ID.String()},
+	})
+	s.createTestScene(&models.SceneCreateInput{
+		Date:       &lastDate,
+		StudioID:   &studioID,
+		Performers: appearances,
+		TagIds:     []string{tag.ID.String()},
+	})
+
+	stats, err := s.resolver.Performer().Stats(s.ctx, performer)
+	if err != nil {
+		s.t.Errorf("Error getting performer stats: %s", err.Error())
+		return
+	}
+
+	if len(stats.ScenesPerYear) != 2 || stats.ScenesPerYear[0].Year != 2004 || stats.ScenesPerYear[1].Year != 2006 {
+		s.t.Errorf("Unexpected scenes per year: %v", stats.ScenesPerYear)
+	}
+
+	if len(stats.Studios) != 1 || stats.Studios[0].StudioID != studio.ID || stats.Studios[0].SceneCount != 2 {
+		s.t.Errorf("Unexpected studios: %v", stats.Studios)
+	}
+
+	if len(stats.CommonTags) != 1 || stats.CommonTags[0].TagID != tag.ID || stats.CommonTags[0].SceneCount != 2 {
+		s.t.Errorf("Unexpected common tags: %v", stats.CommonTags)
+	}
+
+	if stats.FirstSceneDate.String != firstDate {
+		s.fieldMismatch(firstDate, stats.FirstSceneDate.String, "FirstSceneDate")
+	}
+
+	if stats.LastSceneDate.String != lastDate {
+		s.fieldMismatch(lastDate, stats.LastSceneDate.String, "LastSceneDate")
+	}
+
+	expected := []models.CareerInconsistencyEnum{models.CareerInconsistencyEnumSceneBeforeCareerStart}
+	if !reflect.DeepEqual(stats.CareerInconsistencies, expected) {
+		s.fieldMismatch(expected, stats.CareerInconsistencies, "CareerInconsistencies")
+	}
+}
+
 func (s *performerTestRunner) testUnauthorisedPerformerModify() {
 	// test each api interface - all require modify so all should fail
 	_, err := s.resolver.Mutation().PerformerCreate(s.ctx, models.PerformerCreateInput{})
@@ -417,7 +612,7 @@ func (s *performerTestRunner) testUnauthorisedPerformerModify() {
 
 func (s *performerTestRunner) testUnauthorisedPerformerQuery() {
 	// test each api interface - all require read so all should fail
-	_, err := s.resolver.Query().FindPerformer(s.ctx, "")
+	_, err := s.resolver.Query().FindPerformer(s.ctx, "", nil)
 	if err != api.ErrUnauthorized {
 		s.t.Errorf("FindPerformer: got %v want %v", err, api.ErrUnauthorized)
 	}
@@ -451,6 +646,21 @@ func TestDestroyPerformer(t *testing.T) {
 	pt.testDestroyPerformer()
 }
 
+func TestFrequentCostars(t *testing.T) {
+	pt := createPerformerTestRunner(t)
+	pt.testFrequentCostars()
+}
+
+func TestPerformerConnections(t *testing.T) {
+	pt := createPerformerTestRunner(t)
+	pt.testPerformerConnections()
+}
+
+func TestPerformerStats(t *testing.T) {
+	pt := createPerformerTestRunner(t)
+	pt.testPerformerStats()
+}
+
 func TestUnauthorisedPerformerModify(t *testing.T) {
 	pt := &performerTestRunner{
 		testRunner: *asRead(t),
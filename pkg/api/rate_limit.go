@@ -0,0 +1,137 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/gqlerror"
+
+	"github.com/stashapp/stash-box/pkg/manager/config"
+	"github.com/stashapp/stash-box/pkg/models"
+	"github.com/stashapp/stash-box/pkg/utils"
+)
+
+var ErrRateLimited = errors.New("Rate limit exceeded")
+
+var rateLimiter = utils.NewRateLimiter()
+
+// getRateLimit returns the rate limit applying to a request from a user with
+// the provided roles, and false if the request is not limited. The most
+// generous limit of the roles is used, and roles with a rate of zero are not
+// limited. Anonymous requests, users without roles and roles without a
+// configured limit use the default limit.
+func getRateLimit(limits map[string]config.RateLimit, currentUser *models.User, roles []models.RoleEnum) (config.RateLimit, bool) {
+	if currentUser == nil || len(roles) == 0 {
+		return getDefaultRateLimit(limits)
+	}
+
+	var ret config.RateLimit
+	for _, role := range roles {
+		limit, found := limits[role.String()]
+		if !found {
+			var limited bool
+			limit, limited = getDefaultRateLimit(limits)
+			if !limited {
+				return config.RateLimit{}, false
+			}
+		}
+		if limit.Rate <= 0 {
+			return config.RateLimit{}, false
+		}
+
+		if limit.Rate > ret.Rate || (limit.Rate == ret.Rate && limit.Burst > ret.Burst) {
+			ret = limit
+		}
+	}
+
+	return ret, true
+}
+
+// getDefaultRateLimit returns the anonymous rate limit, or the strictest
+// configured limit if there is no anonymous limit, and false if the request
+// is not limited.
+func getDefaultRateLimit(limits map[string]config.RateLimit) (config.RateLimit, bool) {
+	if limit, found := limits[config.AnonymousRateLimit]; found {
+		return limit, limit.Rate > 0
+	}
+
+	var ret config.RateLimit
+	limited := false
+	for _, limit := range limits {
+		if limit.Rate <= 0 {
+			continue
+		}
+		if !limited || limit.Rate < ret.Rate || (limit.Rate == ret.Rate && limit.Burst < ret.Burst) {
+			ret = limit
+			limited = true
+		}
+	}
+
+	return ret, limited
+}
+
+func getRateLimitKey(r *http.Request) string {
+	currentUser := getCurrentUser(r.Context())
+	if currentUser != nil {
+		return "user:" + currentUser.ID.String()
+	}
+
+	return "ip:" + getRequestIP(r)
+}
+
+func durationSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
+
+// rateLimitHandler limits the requests of each user, or IP address for
+// anonymous requests, according to the rate limits configured for their
+// roles. It must run after authenticateHandler.
+func rateLimitHandler() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			limits := config.GetRateLimits()
+			if limits == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx := r.Context()
+			var roles []models.RoleEnum
+			if roleCtxVal := ctx.Value(ContextRoles); roleCtxVal != nil {
+				roles = roleCtxVal.([]models.RoleEnum)
+			}
+
+			limit, limited := getRateLimit(limits, getCurrentUser(ctx), roles)
+			if !limited {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			result := rateLimiter.Allow(getRateLimitKey(r), limit.Rate, limit.Burst)
+
+			header := w.Header()
+			header.Set("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+			header.Set("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			header.Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(result.Reset).Unix(), 10))
+
+			if !result.Allowed {
+				header.Set("Retry-After", durationSeconds(result.RetryAfter))
+				header.Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusTooManyRequests)
+
+				response := graphql.Response{
+					Errors: gqlerror.List{{Message: ErrRateLimited.Error()}},
+				}
+				_ = json.NewEncoder(w).Encode(response)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -17,12 +17,42 @@ func (r *Resolver) Mutation() models.MutationResolver {
 func (r *Resolver) Edit() models.EditResolver {
 	return &editResolver{r}
 }
+func (r *Resolver) EntityFieldChange() models.EntityFieldChangeResolver {
+	return &entityFieldChangeResolver{r}
+}
 func (r *Resolver) EditComment() models.EditCommentResolver {
 	return &editCommentResolver{r}
 }
+func (r *Resolver) Group() models.GroupResolver {
+	return &groupResolver{r}
+}
+func (r *Resolver) GroupEdit() models.GroupEditResolver {
+	return &groupEditResolver{r}
+}
+func (r *Resolver) GroupScene() models.GroupSceneResolver {
+	return &groupSceneResolver{r}
+}
 func (r *Resolver) Performer() models.PerformerResolver {
 	return &performerResolver{r}
 }
+func (r *Resolver) PerformerCostar() models.PerformerCostarResolver {
+	return &performerCostarResolver{r}
+}
+func (r *Resolver) PerformerConnection() models.PerformerConnectionResolver {
+	return &performerConnectionResolver{r}
+}
+func (r *Resolver) PerformerStats() models.PerformerStatsResolver {
+	return &performerStatsResolver{r}
+}
+func (r *Resolver) PerformerStudioCount() models.PerformerStudioCountResolver {
+	return &performerStudioCountResolver{r}
+}
+func (r *Resolver) PerformerTagCount() models.PerformerTagCountResolver {
+	return &performerTagCountResolver{r}
+}
+func (r *Resolver) PerformerDuplicate() models.PerformerDuplicateResolver {
+	return &performerDuplicateResolver{r}
+}
 func (r *Resolver) PerformerEdit() models.PerformerEditResolver {
 	return &performerEditResolver{r}
 }
@@ -32,18 +62,54 @@ func (r *Resolver) Tag() models.TagResolver {
 func (r *Resolver) TagCategory() models.TagCategoryResolver {
 	return &tagCategoryResolver{r}
 }
+func (r *Resolver) Site() models.SiteResolver {
+	return &siteResolver{r}
+}
+func (r *Resolver) URL() models.URLResolver {
+	return &urlResolver{r}
+}
 func (r *Resolver) Image() models.ImageResolver {
 	return &imageResolver{r}
 }
 func (r *Resolver) Studio() models.StudioResolver {
 	return &studioResolver{r}
 }
+func (r *Resolver) StudioEdit() models.StudioEditResolver {
+	return &studioEditResolver{r}
+}
+func (r *Resolver) TagCategoryUsage() models.TagCategoryUsageResolver {
+	return &tagCategoryUsageResolver{r}
+}
+func (r *Resolver) TagEdit() models.TagEditResolver {
+	return &tagEditResolver{r}
+}
+func (r *Resolver) VoteComment() models.VoteCommentResolver {
+	return &voteCommentResolver{r}
+}
 func (r *Resolver) Scene() models.SceneResolver {
 	return &sceneResolver{r}
 }
 func (r *Resolver) User() models.UserResolver {
 	return &userResolver{r}
 }
+func (r *Resolver) UserAPICallCount() models.UserAPICallCountResolver {
+	return &userAPICallCountResolver{r}
+}
+func (r *Resolver) APIKey() models.APIKeyResolver {
+	return &apiKeyResolver{r}
+}
+func (r *Resolver) UserSession() models.UserSessionResolver {
+	return &userSessionResolver{r}
+}
+func (r *Resolver) UserModerationAction() models.UserModerationActionResolver {
+	return &userModerationActionResolver{r}
+}
+func (r *Resolver) AuditLogEntry() models.AuditLogEntryResolver {
+	return &auditLogEntryResolver{r}
+}
+func (r *Resolver) Notification() models.NotificationResolver {
+	return &notificationResolver{r}
+}
 func (r *Resolver) Query() models.QueryResolver {
 	return &queryResolver{r}
 }
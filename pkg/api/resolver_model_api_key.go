@@ -0,0 +1,36 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+type apiKeyResolver struct{ *Resolver }
+
+func (r *apiKeyResolver) ID(ctx context.Context, obj *models.APIKey) (string, error) {
+	return obj.ID.String(), nil
+}
+
+func (r *apiKeyResolver) Roles(ctx context.Context, obj *models.APIKey) ([]models.RoleEnum, error) {
+	qb := models.NewAPIKeyQueryBuilder(nil)
+	roles, err := qb.GetRoles(obj.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return roles.ToRoles(), nil
+}
+
+func (r *apiKeyResolver) ExpiresAt(ctx context.Context, obj *models.APIKey) (*time.Time, error) {
+	return resolveNullTimestamp(obj.ExpiresAt), nil
+}
+
+func (r *apiKeyResolver) LastUsedAt(ctx context.Context, obj *models.APIKey) (*time.Time, error) {
+	return resolveNullTimestamp(obj.LastUsedAt), nil
+}
+
+func (r *apiKeyResolver) CreatedAt(ctx context.Context, obj *models.APIKey) (*time.Time, error) {
+	return &obj.CreatedAt.Timestamp, nil
+}
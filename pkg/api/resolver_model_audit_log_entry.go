@@ -0,0 +1,48 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+type auditLogEntryResolver struct{ *Resolver }
+
+func (r *auditLogEntryResolver) ID(ctx context.Context, obj *models.AuditLogEntry) (string, error) {
+	return obj.ID.String(), nil
+}
+
+func (r *auditLogEntryResolver) User(ctx context.Context, obj *models.AuditLogEntry) (*models.User, error) {
+	if !obj.UserID.Valid {
+		return nil, nil
+	}
+
+	qb := models.NewUserQueryBuilder(nil)
+	return qb.Find(obj.UserID.UUID)
+}
+
+func (r *auditLogEntryResolver) TargetType(ctx context.Context, obj *models.AuditLogEntry) (models.AuditTargetTypeEnum, error) {
+	var ret models.AuditTargetTypeEnum
+	if !resolveEnumString(obj.TargetType, &ret) {
+		return "", nil
+	}
+
+	return ret, nil
+}
+
+func (r *auditLogEntryResolver) TargetID(ctx context.Context, obj *models.AuditLogEntry) (string, error) {
+	return obj.TargetID.String(), nil
+}
+
+func (r *auditLogEntryResolver) Before(ctx context.Context, obj *models.AuditLogEntry) (*string, error) {
+	return resolveNullString(obj.Before), nil
+}
+
+func (r *auditLogEntryResolver) After(ctx context.Context, obj *models.AuditLogEntry) (*string, error) {
+	return resolveNullString(obj.After), nil
+}
+
+func (r *auditLogEntryResolver) CreatedAt(ctx context.Context, obj *models.AuditLogEntry) (*time.Time, error) {
+	return &obj.CreatedAt.Timestamp, nil
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/gofrs/uuid"
+	"github.com/stashapp/stash-box/pkg/dataloader"
 	"github.com/stashapp/stash-box/pkg/models"
 )
 
@@ -73,6 +74,34 @@ func (r *editResolver) Target(ctx context.Context, obj *models.Edit) (models.Edi
 			return nil, err
 		}
 
+		return target, nil
+	} else if targetType == "STUDIO" {
+		eqb := models.NewEditQueryBuilder(nil)
+		studioID, err := eqb.FindStudioID(obj.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		sqb := models.NewStudioQueryBuilder(nil)
+		target, err := sqb.Find(*studioID)
+		if err != nil {
+			return nil, err
+		}
+
+		return target, nil
+	} else if targetType == "GROUP" {
+		eqb := models.NewEditQueryBuilder(nil)
+		groupID, err := eqb.FindGroupID(obj.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		gqb := models.NewGroupQueryBuilder(nil)
+		target, err := gqb.Find(*groupID)
+		if err != nil {
+			return nil, err
+		}
+
 		return target, nil
 	} else {
 		return nil, errors.New("not implemented")
@@ -148,6 +177,18 @@ func (r *editResolver) Details(ctx context.Context, obj *models.Edit) (models.Ed
 			return nil, err
 		}
 		ret = performerData.New
+	} else if targetType == "STUDIO" {
+		studioData, err := obj.GetStudioData()
+		if err != nil {
+			return nil, err
+		}
+		ret = studioData.New
+	} else if targetType == "GROUP" {
+		groupData, err := obj.GetGroupData()
+		if err != nil {
+			return nil, err
+		}
+		ret = groupData.New
 	}
 
 	return ret, nil
@@ -169,6 +210,18 @@ func (r *editResolver) OldDetails(ctx context.Context, obj *models.Edit) (models
 			return nil, err
 		}
 		ret = performerData.Old
+	} else if targetType == "STUDIO" {
+		studioData, err := obj.GetStudioData()
+		if err != nil {
+			return nil, err
+		}
+		ret = studioData.Old
+	} else if targetType == "GROUP" {
+		groupData, err := obj.GetGroupData()
+		if err != nil {
+			return nil, err
+		}
+		ret = groupData.Old
 	}
 
 	return ret, nil
@@ -195,8 +248,17 @@ func (r *editResolver) Comments(ctx context.Context, obj *models.Edit) ([]*model
 }
 
 func (r *editResolver) Votes(ctx context.Context, obj *models.Edit) ([]*models.VoteComment, error) {
-	// TODO
-	return nil, nil
+	qb := models.NewEditQueryBuilder(nil)
+	votes, err := qb.GetVotes(obj.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(votes, func(i, j int) bool {
+		return votes[i].CreatedAt.Timestamp.Before(votes[j].CreatedAt.Timestamp)
+	})
+
+	return votes, nil
 }
 
 func (r *editResolver) Status(ctx context.Context, obj *models.Edit) (models.VoteStatusEnum, error) {
@@ -223,3 +285,7 @@ func (r *editResolver) Options(ctx context.Context, obj *models.Edit) (*models.P
 	}
 	return nil, nil
 }
+
+func (r *editResolver) Warnings(ctx context.Context, obj *models.Edit) ([]*models.EditWarning, error) {
+	return dataloader.For(ctx).EditWarningsById.Load(obj.ID)
+}
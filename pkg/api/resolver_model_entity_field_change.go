@@ -0,0 +1,19 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+type entityFieldChangeResolver struct{ *Resolver }
+
+func (r *entityFieldChangeResolver) User(ctx context.Context, obj *models.EntityFieldChange) (*models.User, error) {
+	qb := models.NewUserQueryBuilder(nil)
+	return qb.Find(obj.Edit.UserID)
+}
+
+func (r *entityFieldChangeResolver) ChangedAt(ctx context.Context, obj *models.EntityFieldChange) (*time.Time, error) {
+	return &obj.Edit.UpdatedAt.Timestamp, nil
+}
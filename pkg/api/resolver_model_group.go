@@ -0,0 +1,66 @@
+package api
+
+import (
+	"context"
+
+	"github.com/stashapp/stash-box/pkg/dataloader"
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+type groupResolver struct{ *Resolver }
+
+func (r *groupResolver) ID(ctx context.Context, obj *models.Group) (string, error) {
+	return obj.ID.String(), nil
+}
+
+func (r *groupResolver) Date(ctx context.Context, obj *models.Group) (*string, error) {
+	return resolveSQLiteDate(obj.Date)
+}
+
+func (r *groupResolver) Studio(ctx context.Context, obj *models.Group) (*models.Studio, error) {
+	if !obj.StudioID.Valid {
+		return nil, nil
+	}
+
+	qb := models.NewStudioQueryBuilder(nil)
+	return qb.Find(obj.StudioID.UUID)
+}
+
+func (r *groupResolver) FrontImage(ctx context.Context, obj *models.Group) (*models.Image, error) {
+	if !obj.FrontImageID.Valid {
+		return nil, nil
+	}
+
+	return dataloader.For(ctx).ImageById.Load(obj.FrontImageID.UUID)
+}
+
+func (r *groupResolver) BackImage(ctx context.Context, obj *models.Group) (*models.Image, error) {
+	if !obj.BackImageID.Valid {
+		return nil, nil
+	}
+
+	return dataloader.For(ctx).ImageById.Load(obj.BackImageID.UUID)
+}
+
+func (r *groupResolver) Urls(ctx context.Context, obj *models.Group) ([]*models.URL, error) {
+	return dataloader.For(ctx).GroupUrlsById.Load(obj.ID)
+}
+
+func (r *groupResolver) Scenes(ctx context.Context, obj *models.Group) ([]*models.GroupScene, error) {
+	return dataloader.For(ctx).GroupScenesById.Load(obj.ID)
+}
+
+func (r *groupResolver) Edits(ctx context.Context, obj *models.Group) ([]*models.Edit, error) {
+	eqb := models.NewEditQueryBuilder(nil)
+	return eqb.FindByGroupID(obj.ID)
+}
+
+type groupSceneResolver struct{ *Resolver }
+
+func (r *groupSceneResolver) Scene(ctx context.Context, obj *models.GroupScene) (*models.Scene, error) {
+	return dataloader.For(ctx).SceneById.Load(obj.SceneID)
+}
+
+func (r *groupSceneResolver) SceneNumber(ctx context.Context, obj *models.GroupScene) (*int, error) {
+	return resolveNullInt64(obj.SceneNumber)
+}
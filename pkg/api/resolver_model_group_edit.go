@@ -0,0 +1,28 @@
+package api
+
+import (
+	"context"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+type groupEditResolver struct{ *Resolver }
+
+func (r *groupEditResolver) AddedScenes(ctx context.Context, obj *models.GroupEdit) ([]*models.GroupScene, error) {
+	return resolveGroupSceneInputs(obj.AddedScenes), nil
+}
+
+func (r *groupEditResolver) RemovedScenes(ctx context.Context, obj *models.GroupEdit) ([]*models.GroupScene, error) {
+	return resolveGroupSceneInputs(obj.RemovedScenes), nil
+}
+
+func resolveGroupSceneInputs(scenes []*models.GroupSceneInput) []*models.GroupScene {
+	if len(scenes) == 0 {
+		return nil
+	}
+
+	// group id is not known for edits that create a group
+	return models.CreateGroupScenes(uuid.Nil, scenes)
+}
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+type notificationResolver struct{ *Resolver }
+
+func (r *notificationResolver) ID(ctx context.Context, obj *models.Notification) (string, error) {
+	return obj.ID.String(), nil
+}
+
+func (r *notificationResolver) Type(ctx context.Context, obj *models.Notification) (models.NotificationTypeEnum, error) {
+	var ret models.NotificationTypeEnum
+	if !resolveEnumString(obj.Type, &ret) {
+		return "", nil
+	}
+
+	return ret, nil
+}
+
+func (r *notificationResolver) Edit(ctx context.Context, obj *models.Notification) (*models.Edit, error) {
+	if !obj.EditID.Valid {
+		return nil, nil
+	}
+
+	qb := models.NewEditQueryBuilder(nil)
+	return qb.Find(obj.EditID.UUID)
+}
+
+func (r *notificationResolver) Actor(ctx context.Context, obj *models.Notification) (*models.User, error) {
+	if !obj.ActorID.Valid {
+		return nil, nil
+	}
+
+	qb := models.NewUserQueryBuilder(nil)
+	return qb.Find(obj.ActorID.UUID)
+}
+
+func (r *notificationResolver) Read(ctx context.Context, obj *models.Notification) (bool, error) {
+	return obj.ReadAt.Valid, nil
+}
+
+func (r *notificationResolver) CreatedAt(ctx context.Context, obj *models.Notification) (*time.Time, error) {
+	return &obj.CreatedAt.Timestamp, nil
+}
@@ -5,6 +5,8 @@ import (
 	"sort"
 	"time"
 
+	"github.com/gofrs/uuid"
+
 	"github.com/stashapp/stash-box/pkg/dataloader"
 	"github.com/stashapp/stash-box/pkg/models"
 	"github.com/stashapp/stash-box/pkg/utils"
@@ -21,6 +23,12 @@ func (r *performerResolver) Disambiguation(ctx context.Context, obj *models.Perf
 }
 
 func (r *performerResolver) Aliases(ctx context.Context, obj *models.Performer) ([]string, error) {
+	if obj.Snapshot != nil {
+		aliases := append([]string{}, obj.Snapshot.Aliases...)
+		sort.Strings(aliases)
+		return aliases, nil
+	}
+
 	aliases, err := dataloader.For(ctx).PerformerAliasesById.Load(obj.ID)
 	if err != nil {
 		return nil, err
@@ -41,6 +49,9 @@ func (r *performerResolver) Gender(ctx context.Context, obj *models.Performer) (
 }
 
 func (r *performerResolver) Urls(ctx context.Context, obj *models.Performer) ([]*models.URL, error) {
+	if obj.Snapshot != nil {
+		return obj.Snapshot.Urls, nil
+	}
 	return dataloader.For(ctx).PerformerUrlsById.Load(obj.ID)
 }
 
@@ -128,17 +139,35 @@ func (r *performerResolver) CareerEndYear(ctx context.Context, obj *models.Perfo
 }
 
 func (r *performerResolver) Tattoos(ctx context.Context, obj *models.Performer) ([]*models.BodyModification, error) {
+	if obj.Snapshot != nil {
+		return obj.Snapshot.Tattoos, nil
+	}
 	return dataloader.For(ctx).PerformerTattoosById.Load(obj.ID)
 }
 
 func (r *performerResolver) Piercings(ctx context.Context, obj *models.Performer) ([]*models.BodyModification, error) {
+	if obj.Snapshot != nil {
+		return obj.Snapshot.Piercings, nil
+	}
 	return dataloader.For(ctx).PerformerPiercingsById.Load(obj.ID)
 }
 
 func (r *performerResolver) Images(ctx context.Context, obj *models.Performer) ([]*models.Image, error) {
-	imageIDs, err := dataloader.For(ctx).PerformerImageIDsById.Load(obj.ID)
-	if err != nil {
-		return nil, err
+	var imageIDs []uuid.UUID
+	if obj.Snapshot != nil {
+		for _, id := range obj.Snapshot.ImageIDs {
+			imageID, err := uuid.FromString(id)
+			if err != nil {
+				return nil, err
+			}
+			imageIDs = append(imageIDs, imageID)
+		}
+	} else {
+		var err error
+		imageIDs, err = dataloader.For(ctx).PerformerImageIDsById.Load(obj.ID)
+		if err != nil {
+			return nil, err
+		}
 	}
 	images, errors := dataloader.For(ctx).ImageById.LoadAll(imageIDs)
 	for _, err := range errors {
@@ -159,3 +188,29 @@ func (r *performerResolver) SceneCount(ctx context.Context, obj *models.Performe
 	sqb := models.NewSceneQueryBuilder(nil)
 	return sqb.CountByPerformer(obj.ID)
 }
+
+func (r *performerResolver) FrequentCostars(ctx context.Context, obj *models.Performer, limit *int) ([]*models.PerformerCostar, error) {
+	qb := models.NewPerformerQueryBuilder(nil)
+
+	l := 10
+	if limit != nil && *limit > 0 {
+		l = *limit
+	}
+	return qb.FindCostars(obj.ID, l)
+}
+
+func (r *performerResolver) Stats(ctx context.Context, obj *models.Performer) (*models.PerformerStats, error) {
+	stats, err := dataloader.For(ctx).PerformerStatsById.Load(obj.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// copy so that the cached stats are not modified
+	ret := *stats
+	ret.CareerInconsistencies = stats.CheckCareer(*obj)
+	return &ret, nil
+}
+
+func (r *performerResolver) History(ctx context.Context, obj *models.Performer) ([]*models.EntityFieldChange, error) {
+	return models.GetPerformerHistory(nil, obj.ID)
+}
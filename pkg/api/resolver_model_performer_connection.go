@@ -0,0 +1,40 @@
+package api
+
+import (
+	"context"
+
+	"github.com/stashapp/stash-box/pkg/dataloader"
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+type performerCostarResolver struct{ *Resolver }
+
+func (r *performerCostarResolver) Performer(ctx context.Context, obj *models.PerformerCostar) (*models.Performer, error) {
+	return dataloader.For(ctx).PerformerById.Load(obj.PerformerID)
+}
+
+type performerConnectionResolver struct{ *Resolver }
+
+func (r *performerConnectionResolver) Depth(ctx context.Context, obj *models.PerformerConnection) (int, error) {
+	return len(obj.SceneIDs), nil
+}
+
+func (r *performerConnectionResolver) Performers(ctx context.Context, obj *models.PerformerConnection) ([]*models.Performer, error) {
+	performers, errs := dataloader.For(ctx).PerformerById.LoadAll(obj.PerformerIDs)
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return performers, nil
+}
+
+func (r *performerConnectionResolver) Scenes(ctx context.Context, obj *models.PerformerConnection) ([]*models.Scene, error) {
+	scenes, errs := dataloader.For(ctx).SceneById.LoadAll(obj.SceneIDs)
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return scenes, nil
+}
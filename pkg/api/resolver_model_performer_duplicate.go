@@ -0,0 +1,53 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/stashapp/stash-box/pkg/dataloader"
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+type performerDuplicateResolver struct{ *Resolver }
+
+func (r *performerDuplicateResolver) ID(ctx context.Context, obj *models.PerformerDuplicate) (string, error) {
+	return obj.ID.String(), nil
+}
+
+func (r *performerDuplicateResolver) Performer(ctx context.Context, obj *models.PerformerDuplicate) (*models.Performer, error) {
+	return dataloader.For(ctx).PerformerById.Load(obj.PerformerID)
+}
+
+func (r *performerDuplicateResolver) Duplicate(ctx context.Context, obj *models.PerformerDuplicate) (*models.Performer, error) {
+	return dataloader.For(ctx).PerformerById.Load(obj.DuplicateID)
+}
+
+func (r *performerDuplicateResolver) BirthdateMatch(ctx context.Context, obj *models.PerformerDuplicate) (*bool, error) {
+	if !obj.BirthdateMatch.Valid {
+		return nil, nil
+	}
+	return &obj.BirthdateMatch.Bool, nil
+}
+
+func (r *performerDuplicateResolver) Status(ctx context.Context, obj *models.PerformerDuplicate) (models.PerformerDuplicateStatusEnum, error) {
+	var ret models.PerformerDuplicateStatusEnum
+	resolveEnumString(obj.Status, &ret)
+	return ret, nil
+}
+
+func (r *performerDuplicateResolver) Edit(ctx context.Context, obj *models.PerformerDuplicate) (*models.Edit, error) {
+	if !obj.EditID.Valid {
+		return nil, nil
+	}
+
+	qb := models.NewEditQueryBuilder(nil)
+	return qb.Find(obj.EditID.UUID)
+}
+
+func (r *performerDuplicateResolver) Created(ctx context.Context, obj *models.PerformerDuplicate) (*time.Time, error) {
+	return &obj.CreatedAt.Timestamp, nil
+}
+
+func (r *performerDuplicateResolver) Updated(ctx context.Context, obj *models.PerformerDuplicate) (*time.Time, error) {
+	return &obj.UpdatedAt.Timestamp, nil
+}
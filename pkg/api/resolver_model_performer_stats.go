@@ -0,0 +1,30 @@
+package api
+
+import (
+	"context"
+
+	"github.com/stashapp/stash-box/pkg/dataloader"
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+type performerStatsResolver struct{ *Resolver }
+
+func (r *performerStatsResolver) FirstSceneDate(ctx context.Context, obj *models.PerformerStats) (*string, error) {
+	return resolveSQLiteDate(obj.FirstSceneDate)
+}
+
+func (r *performerStatsResolver) LastSceneDate(ctx context.Context, obj *models.PerformerStats) (*string, error) {
+	return resolveSQLiteDate(obj.LastSceneDate)
+}
+
+type performerStudioCountResolver struct{ *Resolver }
+
+func (r *performerStudioCountResolver) Studio(ctx context.Context, obj *models.PerformerStudioCount) (*models.Studio, error) {
+	return dataloader.For(ctx).StudioById.Load(obj.StudioID)
+}
+
+type performerTagCountResolver struct{ *Resolver }
+
+func (r *performerTagCountResolver) Tag(ctx context.Context, obj *models.PerformerTagCount) (*models.Tag, error) {
+	return dataloader.For(ctx).TagById.Load(obj.TagID)
+}
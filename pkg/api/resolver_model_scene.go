@@ -3,6 +3,8 @@ package api
 import (
 	"context"
 
+	"github.com/gofrs/uuid"
+
 	"github.com/stashapp/stash-box/pkg/dataloader"
 	"github.com/stashapp/stash-box/pkg/models"
 )
@@ -48,7 +50,7 @@ func (r *sceneResolver) Studio(ctx context.Context, obj *models.Scene) (*models.
 	return parent, nil
 }
 
-func (r *sceneResolver) Tags(ctx context.Context, obj *models.Scene) ([]*models.Tag, error) {
+func (r *sceneResolver) Tags(ctx context.Context, obj *models.Scene, categoryID *string, group *models.TagGroupEnum) ([]*models.Tag, error) {
 	tagIDs, err := dataloader.For(ctx).SceneTagIDsById.Load(obj.ID)
 	if err != nil {
 		return nil, err
@@ -59,7 +61,56 @@ func (r *sceneResolver) Tags(ctx context.Context, obj *models.Scene) ([]*models.
 			return nil, err
 		}
 	}
-	return tags, nil
+
+	if categoryID == nil && group == nil {
+		return tags, nil
+	}
+	return filterTagsByCategory(ctx, tags, categoryID, group)
+}
+
+func filterTagsByCategory(ctx context.Context, tags []*models.Tag, categoryID *string, group *models.TagGroupEnum) ([]*models.Tag, error) {
+	var categoryIDs []uuid.UUID
+	for _, tag := range tags {
+		if tag.CategoryID.Valid {
+			categoryIDs = append(categoryIDs, tag.CategoryID.UUID)
+		}
+	}
+	categories, errors := dataloader.For(ctx).TagCategoryById.LoadAll(categoryIDs)
+	for _, err := range errors {
+		if err != nil {
+			return nil, err
+		}
+	}
+	categoryMap := make(map[uuid.UUID]*models.TagCategory)
+	for _, category := range categories {
+		if category != nil {
+			categoryMap[category.ID] = category
+		}
+	}
+
+	ret := []*models.Tag{}
+	for _, tag := range tags {
+		category := categoryMap[tag.CategoryID.UUID]
+		if !tag.CategoryID.Valid || category == nil {
+			continue
+		}
+		if categoryID != nil && category.ID.String() != *categoryID {
+			continue
+		}
+		if group != nil && category.Group != group.String() {
+			continue
+		}
+		ret = append(ret, tag)
+	}
+	return ret, nil
+}
+
+func (r *sceneResolver) ImpliedTags(ctx context.Context, obj *models.Scene) ([]*models.Tag, error) {
+	tagIDs, err := dataloader.For(ctx).SceneImpliedTagIDsById.Load(obj.ID)
+	if err != nil {
+		return nil, err
+	}
+	return loadTags(ctx, tagIDs)
 }
 
 func (r *sceneResolver) Images(ctx context.Context, obj *models.Scene) ([]*models.Image, error) {
@@ -92,9 +143,13 @@ func (r *sceneResolver) Performers(ctx context.Context, obj *models.Scene) ([]*m
 			return nil, err
 		}
 
+		position, _ := resolveNullInt64(appearance.Position)
 		retApp := models.PerformerAppearance{
 			Performer: performer,
 			As:        resolveNullString(appearance.As),
+			Role:      resolveNullString(appearance.Role),
+			Position:  position,
+			Credited:  appearance.Credited,
 		}
 		ret = append(ret, &retApp)
 	}
@@ -108,3 +163,33 @@ func (r *sceneResolver) Fingerprints(ctx context.Context, obj *models.Scene) ([]
 func (r *sceneResolver) Urls(ctx context.Context, obj *models.Scene) ([]*models.URL, error) {
 	return dataloader.For(ctx).SceneUrlsById.Load(obj.ID)
 }
+
+func (r *sceneResolver) Groups(ctx context.Context, obj *models.Scene) ([]*models.SceneGroup, error) {
+	joins, err := dataloader.For(ctx).SceneGroupsById.Load(obj.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []*models.SceneGroup
+	for _, join := range joins {
+		group, err := dataloader.For(ctx).GroupById.Load(join.GroupID)
+		if err != nil {
+			return nil, err
+		}
+		if group == nil || group.Deleted {
+			continue
+		}
+
+		sceneNumber, _ := resolveNullInt64(join.SceneNumber)
+		ret = append(ret, &models.SceneGroup{
+			Group:       group,
+			SceneNumber: sceneNumber,
+		})
+	}
+
+	return ret, nil
+}
+
+func (r *sceneResolver) DateAccuracy(ctx context.Context, obj *models.Scene) (*models.DateAccuracyEnum, error) {
+	return obj.ResolveDateAccuracy(), nil
+}
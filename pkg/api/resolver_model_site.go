@@ -0,0 +1,59 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/dataloader"
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+type siteResolver struct{ *Resolver }
+
+func (r *siteResolver) ID(ctx context.Context, obj *models.Site) (string, error) {
+	return obj.ID.String(), nil
+}
+func (r *siteResolver) Description(ctx context.Context, obj *models.Site) (*string, error) {
+	return resolveNullString(obj.Description), nil
+}
+func (r *siteResolver) URL(ctx context.Context, obj *models.Site) (*string, error) {
+	return resolveNullString(obj.URL), nil
+}
+func (r *siteResolver) Regex(ctx context.Context, obj *models.Site) (*string, error) {
+	return resolveNullString(obj.Regex), nil
+}
+func (r *siteResolver) ValidTypes(ctx context.Context, obj *models.Site) ([]models.ValidSiteTypeEnum, error) {
+	ret := []models.ValidSiteTypeEnum{}
+	for _, t := range obj.ValidTypes {
+		var validType models.ValidSiteTypeEnum
+		if resolveEnumString(t, &validType) {
+			ret = append(ret, validType)
+		}
+	}
+	return ret, nil
+}
+func (r *siteResolver) Icon(ctx context.Context, obj *models.Site) (*string, error) {
+	return resolveNullString(obj.Icon), nil
+}
+func (r *siteResolver) Created(ctx context.Context, obj *models.Site) (*time.Time, error) {
+	return &obj.CreatedAt.Timestamp, nil
+}
+func (r *siteResolver) Updated(ctx context.Context, obj *models.Site) (*time.Time, error) {
+	return &obj.UpdatedAt.Timestamp, nil
+}
+
+type urlResolver struct{ *Resolver }
+
+func (r *urlResolver) Site(ctx context.Context, obj *models.URL) (*models.Site, error) {
+	if obj.SiteID == nil {
+		return nil, nil
+	}
+
+	siteID, err := uuid.FromString(*obj.SiteID)
+	if err != nil {
+		return nil, err
+	}
+	return dataloader.For(ctx).SiteById.Load(siteID)
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"sort"
 
 	"github.com/stashapp/stash-box/pkg/dataloader"
 	"github.com/stashapp/stash-box/pkg/models"
@@ -13,6 +14,17 @@ func (r *studioResolver) ID(ctx context.Context, obj *models.Studio) (string, er
 	return obj.ID.String(), nil
 }
 
+func (r *studioResolver) Aliases(ctx context.Context, obj *models.Studio) ([]string, error) {
+	aliases, err := dataloader.For(ctx).StudioAliasesById.Load(obj.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(aliases)
+
+	return aliases, nil
+}
+
 func (r *studioResolver) Urls(ctx context.Context, obj *models.Studio) ([]*models.URL, error) {
 	return dataloader.For(ctx).StudioUrlsById.Load(obj.ID)
 }
@@ -55,3 +67,21 @@ func (r *studioResolver) Images(ctx context.Context, obj *models.Studio) ([]*mod
 	}
 	return images, nil
 }
+
+func (r *studioResolver) Descendants(ctx context.Context, obj *models.Studio) ([]*models.Studio, error) {
+	qb := models.NewStudioQueryBuilder(nil)
+	return qb.FindDescendants(obj.ID)
+}
+
+func (r *studioResolver) Ancestors(ctx context.Context, obj *models.Studio) ([]*models.Studio, error) {
+	qb := models.NewStudioQueryBuilder(nil)
+	return qb.FindAncestors(obj.ID)
+}
+
+func (r *studioResolver) SceneCount(ctx context.Context, obj *models.Studio, includeDescendants *bool) (int, error) {
+	if includeDescendants == nil || !*includeDescendants {
+		return dataloader.For(ctx).StudioSceneCountById.Load(obj.ID)
+	}
+
+	return dataloader.For(ctx).NetworkSceneCountById.Load(obj.ID)
+}
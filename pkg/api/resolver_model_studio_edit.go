@@ -0,0 +1,78 @@
+package api
+
+import (
+	"context"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/dataloader"
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+type studioEditResolver struct{ *Resolver }
+
+func (r *studioEditResolver) Parent(ctx context.Context, obj *models.StudioEdit) (*models.Studio, error) {
+	if obj.ParentID == nil {
+		return nil, nil
+	}
+
+	parentID, err := uuid.FromString(*obj.ParentID)
+	if err != nil {
+		return nil, err
+	}
+	return dataloader.For(ctx).StudioById.Load(parentID)
+}
+
+func (r *studioEditResolver) AddedChildStudios(ctx context.Context, obj *models.StudioEdit) ([]*models.Studio, error) {
+	return resolveStudioIDs(ctx, obj.AddedChildStudios)
+}
+
+func (r *studioEditResolver) RemovedChildStudios(ctx context.Context, obj *models.StudioEdit) ([]*models.Studio, error) {
+	return resolveStudioIDs(ctx, obj.RemovedChildStudios)
+}
+
+func (r *studioEditResolver) AddedImages(ctx context.Context, obj *models.StudioEdit) ([]*models.Image, error) {
+	return resolveImageIDs(ctx, obj.AddedImages)
+}
+
+func (r *studioEditResolver) RemovedImages(ctx context.Context, obj *models.StudioEdit) ([]*models.Image, error) {
+	return resolveImageIDs(ctx, obj.RemovedImages)
+}
+
+func resolveStudioIDs(ctx context.Context, ids []string) ([]*models.Studio, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	var uuids []uuid.UUID
+	for _, id := range ids {
+		studioID, _ := uuid.FromString(id)
+		uuids = append(uuids, studioID)
+	}
+	studios, errors := dataloader.For(ctx).StudioById.LoadAll(uuids)
+	for _, err := range errors {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return studios, nil
+}
+
+func resolveImageIDs(ctx context.Context, ids []string) ([]*models.Image, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	var uuids []uuid.UUID
+	for _, id := range ids {
+		imageID, _ := uuid.FromString(id)
+		uuids = append(uuids, imageID)
+	}
+	images, errors := dataloader.For(ctx).ImageById.LoadAll(uuids)
+	for _, err := range errors {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return images, nil
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"sort"
 
+	"github.com/gofrs/uuid"
+
 	"github.com/stashapp/stash-box/pkg/dataloader"
 	"github.com/stashapp/stash-box/pkg/models"
 )
@@ -17,6 +19,12 @@ func (r *tagResolver) Description(ctx context.Context, obj *models.Tag) (*string
 	return resolveNullString(obj.Description), nil
 }
 func (r *tagResolver) Aliases(ctx context.Context, obj *models.Tag) ([]string, error) {
+	if obj.Snapshot != nil {
+		aliases := append([]string{}, obj.Snapshot.Aliases...)
+		sort.Strings(aliases)
+		return aliases, nil
+	}
+
 	qb := models.NewTagQueryBuilder(nil)
 	aliases, err := qb.GetAliases(obj.ID)
 
@@ -41,3 +49,33 @@ func (r *tagResolver) Category(ctx context.Context, obj *models.Tag) (*models.Ta
 		return nil, nil
 	}
 }
+
+func (r *tagResolver) Parents(ctx context.Context, obj *models.Tag) ([]*models.Tag, error) {
+	parentIDs, err := dataloader.For(ctx).TagParentIDsById.Load(obj.ID)
+	if err != nil {
+		return nil, err
+	}
+	return loadTags(ctx, parentIDs)
+}
+
+func (r *tagResolver) Children(ctx context.Context, obj *models.Tag) ([]*models.Tag, error) {
+	childIDs, err := dataloader.For(ctx).TagChildIDsById.Load(obj.ID)
+	if err != nil {
+		return nil, err
+	}
+	return loadTags(ctx, childIDs)
+}
+
+func loadTags(ctx context.Context, ids []uuid.UUID) ([]*models.Tag, error) {
+	tags, errors := dataloader.For(ctx).TagById.LoadAll(ids)
+	for _, err := range errors {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return tags, nil
+}
+
+func (r *tagResolver) SceneCount(ctx context.Context, obj *models.Tag) (int, error) {
+	return dataloader.For(ctx).TagSceneCountById.Load(obj.ID)
+}
@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 
+	"github.com/stashapp/stash-box/pkg/dataloader"
 	"github.com/stashapp/stash-box/pkg/models"
 )
 
@@ -25,3 +26,9 @@ func (r *tagCategoryResolver) Group(ctx context.Context, obj *models.TagCategory
 
 	return ret, nil
 }
+
+type tagCategoryUsageResolver struct{ *Resolver }
+
+func (r *tagCategoryUsageResolver) Category(ctx context.Context, obj *models.TagCategoryUsage) (*models.TagCategory, error) {
+	return dataloader.For(ctx).TagCategoryById.Load(obj.CategoryID)
+}
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"context"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/dataloader"
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+type tagEditResolver struct{ *Resolver }
+
+func (r *tagEditResolver) AddedParents(ctx context.Context, obj *models.TagEdit) ([]*models.Tag, error) {
+	return resolveTagIDs(ctx, obj.AddedParents)
+}
+
+func (r *tagEditResolver) RemovedParents(ctx context.Context, obj *models.TagEdit) ([]*models.Tag, error) {
+	return resolveTagIDs(ctx, obj.RemovedParents)
+}
+
+func resolveTagIDs(ctx context.Context, ids []string) ([]*models.Tag, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	var uuids []uuid.UUID
+	for _, id := range ids {
+		tagID, err := uuid.FromString(id)
+		if err != nil {
+			return nil, err
+		}
+		uuids = append(uuids, tagID)
+	}
+
+	tags, errs := dataloader.For(ctx).TagById.LoadAll(uuids)
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return tags, nil
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/stashapp/stash-box/pkg/manager/config"
 	"github.com/stashapp/stash-box/pkg/models"
+	"github.com/stashapp/stash-box/pkg/user"
 )
 
 type userResolver struct{ *Resolver }
@@ -24,26 +25,6 @@ func (r *userResolver) Roles(ctx context.Context, obj *models.User) ([]models.Ro
 	return roles.ToRoles(), nil
 }
 
-func (r *userResolver) SuccessfulEdits(ctx context.Context, obj *models.User) (int, error) {
-	// TODO
-	return 0, nil
-}
-
-func (r *userResolver) UnsuccessfulEdits(ctx context.Context, obj *models.User) (int, error) {
-	// TODO
-	return 0, nil
-}
-
-func (r *userResolver) SuccessfulVotes(ctx context.Context, obj *models.User) (int, error) {
-	// TODO
-	return 0, nil
-}
-
-func (r *userResolver) UnsuccessfulVotes(ctx context.Context, obj *models.User) (int, error) {
-	// TODO
-	return 0, nil
-}
-
 func (r *userResolver) InvitedBy(ctx context.Context, obj *models.User) (*models.User, error) {
 	invitedBy := obj.InvitedByID
 	if invitedBy.Valid {
@@ -76,3 +57,31 @@ func (r *userResolver) ActiveInviteCodes(ctx context.Context, obj *models.User)
 	}
 	return ret, nil
 }
+
+func (r *userResolver) TotpEnabled(ctx context.Context, obj *models.User) (bool, error) {
+	return obj.TOTPEnabled, nil
+}
+
+func (r *userResolver) Suspension(ctx context.Context, obj *models.User) (*models.UserModerationAction, error) {
+	// only show if current user or admin
+	if err := validateOwner(ctx, obj.ID); err != nil {
+		return nil, nil
+	}
+
+	return user.GetSuspension(nil, obj)
+}
+
+func (r *userResolver) ModerationHistory(ctx context.Context, obj *models.User) ([]*models.UserModerationAction, error) {
+	if err := validateAdmin(ctx); err != nil {
+		return nil, nil
+	}
+
+	return user.GetModerationHistory(obj.ID)
+}
+
+type userAPICallCountResolver struct{ *Resolver }
+
+func (r *userAPICallCountResolver) User(ctx context.Context, obj *models.UserAPICallCount) (*models.User, error) {
+	qb := models.NewUserQueryBuilder(nil)
+	return qb.Find(obj.UserID)
+}
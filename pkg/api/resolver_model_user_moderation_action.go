@@ -0,0 +1,45 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+type userModerationActionResolver struct{ *Resolver }
+
+func (r *userModerationActionResolver) ID(ctx context.Context, obj *models.UserModerationAction) (string, error) {
+	return obj.ID.String(), nil
+}
+
+func (r *userModerationActionResolver) User(ctx context.Context, obj *models.UserModerationAction) (*models.User, error) {
+	qb := models.NewUserQueryBuilder(nil)
+	return qb.Find(obj.UserID)
+}
+
+func (r *userModerationActionResolver) Moderator(ctx context.Context, obj *models.UserModerationAction) (*models.User, error) {
+	if !obj.ModeratorID.Valid {
+		return nil, nil
+	}
+
+	qb := models.NewUserQueryBuilder(nil)
+	return qb.Find(obj.ModeratorID.UUID)
+}
+
+func (r *userModerationActionResolver) Action(ctx context.Context, obj *models.UserModerationAction) (models.UserModerationActionEnum, error) {
+	var ret models.UserModerationActionEnum
+	if !resolveEnumString(obj.Action, &ret) {
+		return "", nil
+	}
+
+	return ret, nil
+}
+
+func (r *userModerationActionResolver) Until(ctx context.Context, obj *models.UserModerationAction) (*time.Time, error) {
+	return resolveNullTimestamp(obj.Until), nil
+}
+
+func (r *userModerationActionResolver) CreatedAt(ctx context.Context, obj *models.UserModerationAction) (*time.Time, error) {
+	return &obj.CreatedAt.Timestamp, nil
+}
@@ -0,0 +1,31 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+type userSessionResolver struct{ *Resolver }
+
+func (r *userSessionResolver) ID(ctx context.Context, obj *models.UserSession) (string, error) {
+	return obj.ID.String(), nil
+}
+
+func (r *userSessionResolver) CreatedAt(ctx context.Context, obj *models.UserSession) (*time.Time, error) {
+	return &obj.CreatedAt.Timestamp, nil
+}
+
+func (r *userSessionResolver) LastActiveAt(ctx context.Context, obj *models.UserSession) (*time.Time, error) {
+	return &obj.LastActiveAt.Timestamp, nil
+}
+
+func (r *userSessionResolver) ExpiresAt(ctx context.Context, obj *models.UserSession) (*time.Time, error) {
+	return &obj.ExpiresAt.Timestamp, nil
+}
+
+func (r *userSessionResolver) Current(ctx context.Context, obj *models.UserSession) (bool, error) {
+	session := getCurrentSession(ctx)
+	return session != nil && session.ID == obj.ID, nil
+}
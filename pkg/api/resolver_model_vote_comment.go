@@ -0,0 +1,37 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+type voteCommentResolver struct{ *Resolver }
+
+func (r *voteCommentResolver) User(ctx context.Context, obj *models.VoteComment) (*models.User, error) {
+	if !obj.UserID.Valid {
+		return nil, nil
+	}
+
+	qb := models.NewUserQueryBuilder(nil)
+	return qb.Find(obj.UserID.UUID)
+}
+
+func (r *voteCommentResolver) Date(ctx context.Context, obj *models.VoteComment) (*string, error) {
+	date := obj.CreatedAt.Timestamp.Format(time.RFC3339)
+	return &date, nil
+}
+
+func (r *voteCommentResolver) Comment(ctx context.Context, obj *models.VoteComment) (*string, error) {
+	return resolveNullString(obj.Comment), nil
+}
+
+func (r *voteCommentResolver) Type(ctx context.Context, obj *models.VoteComment) (*models.VoteTypeEnum, error) {
+	var ret models.VoteTypeEnum
+	if !resolveEnumString(obj.Type, &ret) {
+		return nil, nil
+	}
+
+	return &ret, nil
+}
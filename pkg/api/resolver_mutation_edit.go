@@ -9,13 +9,15 @@ import (
 	"github.com/stashapp/stash-box/pkg/database"
 	"github.com/stashapp/stash-box/pkg/manager/edit"
 	"github.com/stashapp/stash-box/pkg/models"
+	"github.com/stashapp/stash-box/pkg/user"
 )
 
+// ErrSceneEditsNotImplemented is returned for scene edits, which cannot be
+// created or applied yet.
+var ErrSceneEditsNotImplemented = errors.New("Scene edits are not implemented")
+
 func (r *mutationResolver) SceneEdit(ctx context.Context, input models.SceneEditInput) (*models.Edit, error) {
-	panic("not implemented")
-}
-func (r *mutationResolver) StudioEdit(ctx context.Context, input models.StudioEditInput) (*models.Edit, error) {
-	panic("not implemented")
+	return nil, ErrSceneEditsNotImplemented
 }
 
 func (r *mutationResolver) TagEdit(ctx context.Context, input models.TagEditInput) (*models.Edit, error) {
@@ -200,8 +202,240 @@ func (r *mutationResolver) PerformerEdit(ctx context.Context, input models.Perfo
 	return newEdit, nil
 }
 
+func (r *mutationResolver) StudioEdit(ctx context.Context, input models.StudioEditInput) (*models.Edit, error) {
+	if err := validateEdit(ctx); err != nil {
+		return nil, err
+	}
+
+	// TODO - handle modification of existing edit
+
+	UUID, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+
+	// create the edit
+	currentUser := getCurrentUser(ctx)
+
+	newEdit := models.NewEdit(UUID, currentUser, models.TargetTypeEnumStudio, input.Edit)
+
+	tx := database.DB.MustBeginTx(ctx, nil)
+
+	if input.Edit.Operation == models.OperationEnumModify {
+		err = edit.ModifyStudioEdit(tx, newEdit, input, wasFieldIncludedFunc(ctx))
+
+		if err != nil {
+			_ = tx.Rollback()
+			return nil, err
+		}
+	} else if input.Edit.Operation == models.OperationEnumDestroy {
+		err = edit.DestroyStudioEdit(tx, newEdit, input, wasFieldIncludedFunc(ctx))
+
+		if err != nil {
+			_ = tx.Rollback()
+			return nil, err
+		}
+	} else if input.Edit.Operation == models.OperationEnumCreate {
+		err = edit.CreateStudioEdit(tx, newEdit, input, wasFieldIncludedFunc(ctx))
+
+		if err != nil {
+			_ = tx.Rollback()
+			return nil, err
+		}
+	} else {
+		_ = tx.Rollback()
+		return nil, errors.New("Unsupported operation for studio edit: " + input.Edit.Operation.String())
+	}
+
+	// save the edit
+	eqb := models.NewEditQueryBuilder(tx)
+
+	created, err := eqb.Create(*newEdit)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	if input.Edit.ID != nil {
+		studioID, _ := uuid.FromString(*input.Edit.ID)
+
+		editStudio := models.EditStudio{
+			EditID:   created.ID,
+			StudioID: studioID,
+		}
+
+		err = eqb.CreateEditStudio(editStudio)
+		if err != nil {
+			_ = tx.Rollback()
+			return nil, err
+		}
+	}
+
+	if input.Edit.Comment != nil && len(*input.Edit.Comment) > 0 {
+		commentID, _ := uuid.NewV4()
+		comment := models.NewEditComment(commentID, currentUser, created, *input.Edit.Comment)
+		if err := eqb.CreateComment(*comment); err != nil {
+			return nil, err
+		}
+	}
+
+	// Commit
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return newEdit, nil
+}
+
+func (r *mutationResolver) GroupEdit(ctx context.Context, input models.GroupEditInput) (*models.Edit, error) {
+	if err := validateEdit(ctx); err != nil {
+		return nil, err
+	}
+
+	// TODO - handle modification of existing edit
+
+	UUID, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+
+	// create the edit
+	currentUser := getCurrentUser(ctx)
+
+	newEdit := models.NewEdit(UUID, currentUser, models.TargetTypeEnumGroup, input.Edit)
+
+	tx := database.DB.MustBeginTx(ctx, nil)
+
+	if input.Edit.Operation == models.OperationEnumModify {
+		err = edit.ModifyGroupEdit(tx, newEdit, input, wasFieldIncludedFunc(ctx))
+
+		if err != nil {
+			_ = tx.Rollback()
+			return nil, err
+		}
+	} else if input.Edit.Operation == models.OperationEnumDestroy {
+		err = edit.DestroyGroupEdit(tx, newEdit, input, wasFieldIncludedFunc(ctx))
+
+		if err != nil {
+			_ = tx.Rollback()
+			return nil, err
+		}
+	} else if input.Edit.Operation == models.OperationEnumCreate {
+		err = edit.CreateGroupEdit(tx, newEdit, input, wasFieldIncludedFunc(ctx))
+
+		if err != nil {
+			_ = tx.Rollback()
+			return nil, err
+		}
+	} else {
+		_ = tx.Rollback()
+		return nil, errors.New("Unsupported operation for group edit: " + input.Edit.Operation.String())
+	}
+
+	// save the edit
+	eqb := models.NewEditQueryBuilder(tx)
+
+	created, err := eqb.Create(*newEdit)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	if input.Edit.ID != nil {
+		groupID, _ := uuid.FromString(*input.Edit.ID)
+
+		editGroup := models.EditGroup{
+			EditID:  created.ID,
+			GroupID: groupID,
+		}
+
+		err = eqb.CreateEditGroup(editGroup)
+		if err != nil {
+			_ = tx.Rollback()
+			return nil, err
+		}
+	}
+
+	if input.Edit.Comment != nil && len(*input.Edit.Comment) > 0 {
+		commentID, _ := uuid.NewV4()
+		comment := models.NewEditComment(commentID, currentUser, created, *input.Edit.Comment)
+		if err := eqb.CreateComment(*comment); err != nil {
+			return nil, err
+		}
+	}
+
+	// Commit
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return newEdit, nil
+}
+
 func (r *mutationResolver) EditVote(ctx context.Context, input models.EditVoteInput) (*models.Edit, error) {
-	panic("not implemented")
+	if err := validateVote(ctx); err != nil {
+		return nil, err
+	}
+
+	if input.Type == models.VoteTypeEnumImmediateAccept || input.Type == models.VoteTypeEnumImmediateReject {
+		return nil, errors.New("Immediate votes are made with applyEdit and cancelEdit")
+	}
+
+	// each user has a single vote on an edit, which a comment would replace
+	if input.Type == models.VoteTypeEnumComment {
+		return nil, errors.New("Comments are made with editComment")
+	}
+
+	currentUser := getCurrentUser(ctx)
+	tx := database.DB.MustBeginTx(ctx, nil)
+	eqb := models.NewEditQueryBuilder(tx)
+
+	editID, err := uuid.FromString(input.ID)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+	edit, err := eqb.Find(editID)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+	if edit == nil {
+		_ = tx.Rollback()
+		return nil, errors.New("Edit not found")
+	}
+
+	var status models.VoteStatusEnum
+	resolveEnumString(edit.Status, &status)
+	if status != models.VoteStatusEnumPending {
+		_ = tx.Rollback()
+		return nil, errors.New("Invalid vote status: " + edit.Status)
+	}
+
+	if edit.UserID == currentUser.ID {
+		_ = tx.Rollback()
+		return nil, errors.New("Users cannot vote on their own edits")
+	}
+
+	voteID, _ := uuid.NewV4()
+	vote := models.NewVoteComment(voteID, currentUser, edit, input)
+	if err := eqb.CreateVote(*vote); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	if err := user.NotifyEditVote(tx, edit, currentUser.ID); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	// Commit
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	qb := models.NewEditQueryBuilder(nil)
+	return qb.Find(editID)
 }
 func (r *mutationResolver) EditComment(ctx context.Context, input models.EditCommentInput) (*models.Edit, error) {
 	if err := validateEdit(ctx); err != nil {
@@ -227,6 +461,11 @@ func (r *mutationResolver) EditComment(ctx context.Context, input models.EditCom
 		return nil, err
 	}
 
+	if err := user.NotifyEditComment(tx, edit, currentUser.ID); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
 	// Commit
 	if err := tx.Commit(); err != nil {
 		return nil, err
@@ -270,6 +509,12 @@ func (r *mutationResolver) CancelEdit(ctx context.Context, input models.CancelEd
 		return nil, err
 	}
 
+	uqb := models.NewUserQueryBuilder(tx)
+	if err := uqb.UpdateClosedEditCounts(*updatedEdit); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
 	if err := tx.Commit(); err != nil {
 		return nil, err
 	}
@@ -308,6 +553,12 @@ func (r *mutationResolver) ApplyEdit(ctx context.Context, input models.ApplyEdit
 	resolveEnumString(edit.Operation, &operation)
 	var targetType models.TargetTypeEnum
 	resolveEnumString(edit.TargetType, &targetType)
+
+	// the target of the edit is audited alongside the edit itself
+	var auditTargetType models.AuditTargetTypeEnum
+	var auditTargetID uuid.UUID
+	var auditTargetBefore interface{}
+
 	switch targetType {
 	case models.TargetTypeEnumTag:
 		tqb := models.NewTagQueryBuilder(tx)
@@ -324,6 +575,12 @@ func (r *mutationResolver) ApplyEdit(ctx context.Context, input models.ApplyEdit
 			if tag == nil {
 				return nil, errors.New("Tag not found: " + tagID.String())
 			}
+
+			auditTargetBefore, err = getAuditTarget(tx, models.AuditTargetTypeEnumTag, *tagID)
+			if err != nil {
+				_ = tx.Rollback()
+				return nil, err
+			}
 		}
 		newTag, err := tqb.ApplyEdit(*edit, operation, tag)
 		if err != nil {
@@ -331,6 +588,9 @@ func (r *mutationResolver) ApplyEdit(ctx context.Context, input models.ApplyEdit
 			return nil, err
 		}
 
+		auditTargetType = models.AuditTargetTypeEnumTag
+		auditTargetID = newTag.ID
+
 		if operation == models.OperationEnumCreate {
 			editTag := models.EditTag{
 				EditID: edit.ID,
@@ -358,6 +618,12 @@ func (r *mutationResolver) ApplyEdit(ctx context.Context, input models.ApplyEdit
 			if performer == nil {
 				return nil, errors.New("Performer not found: " + performerID.String())
 			}
+
+			auditTargetBefore, err = getAuditTarget(tx, models.AuditTargetTypeEnumPerformer, *performerID)
+			if err != nil {
+				_ = tx.Rollback()
+				return nil, err
+			}
 		}
 		newPerformer, err := pqb.ApplyEdit(*edit, operation, performer)
 		if err != nil {
@@ -365,6 +631,9 @@ func (r *mutationResolver) ApplyEdit(ctx context.Context, input models.ApplyEdit
 			return nil, err
 		}
 
+		auditTargetType = models.AuditTargetTypeEnumPerformer
+		auditTargetID = newPerformer.ID
+
 		if operation == models.OperationEnumCreate {
 			editPerformer := models.EditPerformer{
 				EditID:      edit.ID,
@@ -377,6 +646,92 @@ func (r *mutationResolver) ApplyEdit(ctx context.Context, input models.ApplyEdit
 				return nil, err
 			}
 		}
+	case models.TargetTypeEnumStudio:
+		sqb := models.NewStudioQueryBuilder(tx)
+		var studio *models.Studio = nil
+		if operation != models.OperationEnumCreate {
+			studioID, err := eqb.FindStudioID(edit.ID)
+			if err != nil {
+				return nil, err
+			}
+			studio, err = sqb.Find(*studioID)
+			if err != nil {
+				return nil, err
+			}
+			if studio == nil {
+				return nil, errors.New("Studio not found: " + studioID.String())
+			}
+
+			auditTargetBefore, err = getAuditTarget(tx, models.AuditTargetTypeEnumStudio, *studioID)
+			if err != nil {
+				_ = tx.Rollback()
+				return nil, err
+			}
+		}
+		newStudio, err := sqb.ApplyEdit(*edit, operation, studio)
+		if err != nil {
+			_ = tx.Rollback()
+			return nil, err
+		}
+
+		auditTargetType = models.AuditTargetTypeEnumStudio
+		auditTargetID = newStudio.ID
+
+		if operation == models.OperationEnumCreate {
+			editStudio := models.EditStudio{
+				EditID:   edit.ID,
+				StudioID: newStudio.ID,
+			}
+
+			err = eqb.CreateEditStudio(editStudio)
+			if err != nil {
+				_ = tx.Rollback()
+				return nil, err
+			}
+		}
+	case models.TargetTypeEnumGroup:
+		gqb := models.NewGroupQueryBuilder(tx)
+		var group *models.Group = nil
+		if operation != models.OperationEnumCreate {
+			groupID, err := eqb.FindGroupID(edit.ID)
+			if err != nil {
+				return nil, err
+			}
+			group, err = gqb.Find(*groupID)
+			if err != nil {
+				return nil, err
+			}
+			if group == nil {
+				return nil, errors.New("Group not found: " + groupID.String())
+			}
+
+			auditTargetBefore, err = getAuditTarget(tx, models.AuditTargetTypeEnumGroup, *groupID)
+			if err != nil {
+				_ = tx.Rollback()
+				return nil, err
+			}
+		}
+		newGroup, err := gqb.ApplyEdit(*edit, operation, group)
+		if err != nil {
+			_ = tx.Rollback()
+			return nil, err
+		}
+
+		auditTargetType = models.AuditTargetTypeEnumGroup
+		auditTargetID = newGroup.ID
+
+		if operation == models.OperationEnumCreate {
+			editGroup := models.EditGroup{
+				EditID:  edit.ID,
+				GroupID: newGroup.ID,
+			}
+
+			err = eqb.CreateEditGroup(editGroup)
+			if err != nil {
+				_ = tx.Rollback()
+				return nil, err
+			}
+		}
 	default:
 		return nil, errors.New("Not implemented: " + edit.TargetType)
 	}
@@ -386,6 +741,7 @@ func (r *mutationResolver) ApplyEdit(ctx context.Context, input models.ApplyEdit
 		return nil, err
 	}
 
+	before := *edit
 	edit.ImmediateAccept()
 	updatedEdit, err := eqb.Update(*edit)
 
@@ -394,6 +750,33 @@ func (r *mutationResolver) ApplyEdit(ctx context.Context, input models.ApplyEdit
 		return nil, err
 	}
 
+	uqb := models.NewUserQueryBuilder(tx)
+	if err := uqb.UpdateClosedEditCounts(*updatedEdit); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	if err := logAudit(ctx, tx, "applyEdit", models.AuditTargetTypeEnumEdit, updatedEdit.ID, before, updatedEdit); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	auditTargetAfter, err := getAuditTarget(tx, auditTargetType, auditTargetID)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	if err := logAudit(ctx, tx, "applyEdit", auditTargetType, auditTargetID, auditTargetBefore, auditTargetAfter); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	if err := user.NotifyEditApplied(tx, updatedEdit, getCurrentUser(ctx).ID); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
 	if err := tx.Commit(); err != nil {
 		return nil, err
 	}
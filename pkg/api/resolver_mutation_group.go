@@ -0,0 +1,193 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/database"
+	"github.com/stashapp/stash-box/pkg/image"
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+func (r *mutationResolver) GroupCreate(ctx context.Context, input models.GroupCreateInput) (*models.Group, error) {
+	if err := validateModify(ctx); err != nil {
+		return nil, err
+	}
+
+	UUID, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+
+	// Populate a new group from the input
+	currentTime := time.Now()
+	newGroup := models.Group{
+		ID:        UUID,
+		CreatedAt: models.SQLiteTimestamp{Timestamp: currentTime},
+		UpdatedAt: models.SQLiteTimestamp{Timestamp: currentTime},
+	}
+
+	newGroup.CopyFromCreateInput(input)
+
+	var group *models.Group
+	err = database.WithTransaction(ctx, func(txn database.Transaction) error {
+		qb := models.NewGroupQueryBuilder(txn.GetTx())
+
+		var err error
+		group, err = qb.Create(newGroup)
+		if err != nil {
+			return err
+		}
+
+		// Save the URLs
+		sqb := models.NewSiteQueryBuilder(txn.GetTx())
+		if err := sqb.ResolveURLs(input.Urls, models.ValidSiteTypeEnumGroup); err != nil {
+			return err
+		}
+		groupUrls := models.CreateGroupUrls(group.ID, input.Urls)
+		if err := qb.CreateUrls(groupUrls); err != nil {
+			return err
+		}
+
+		// Save the scenes
+		groupScenes := models.CreateGroupScenes(group.ID, input.Scenes)
+		if err := qb.CreateScenes(groupScenes); err != nil {
+			return err
+		}
+
+		return logAudit(ctx, txn.GetTx(), "groupCreate", models.AuditTargetTypeEnumGroup, group.ID, nil, group)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return group, nil
+}
+
+func (r *mutationResolver) GroupUpdate(ctx context.Context, input models.GroupUpdateInput) (*models.Group, error) {
+	if err := validateModify(ctx); err != nil {
+		return nil, err
+	}
+
+	var group *models.Group
+	err := database.WithTransaction(ctx, func(txn database.Transaction) error {
+		qb := models.NewGroupQueryBuilder(txn.GetTx())
+		iqb := models.NewImageQueryBuilder(txn.GetTx())
+
+		// get the existing group and modify it
+		groupID, _ := uuid.FromString(input.ID)
+		updatedGroup, err := qb.Find(groupID)
+
+		if err != nil {
+			return err
+		}
+
+		if updatedGroup == nil {
+			return models.NotFoundError(groupID)
+		}
+
+		before := *updatedGroup
+		existingImages := groupImageIDs(updatedGroup)
+
+		updatedGroup.UpdatedAt = models.SQLiteTimestamp{Timestamp: time.Now()}
+
+		// Populate group from the input
+		updatedGroup.CopyFromUpdateInput(input)
+
+		group, err = qb.Update(*updatedGroup)
+		if err != nil {
+			return err
+		}
+
+		// Save the URLs
+		// TODO - only do this if provided
+		sqb := models.NewSiteQueryBuilder(txn.GetTx())
+		if err := sqb.ResolveURLs(input.Urls, models.ValidSiteTypeEnumGroup); err != nil {
+			return err
+		}
+		groupUrls := models.CreateGroupUrls(group.ID, input.Urls)
+		if err := qb.UpdateUrls(group.ID, groupUrls); err != nil {
+			return err
+		}
+
+		// Save the scenes
+		// TODO - only do this if provided
+		groupScenes := models.CreateGroupScenes(group.ID, input.Scenes)
+		if err := qb.UpdateScenes(group.ID, groupScenes); err != nil {
+			return err
+		}
+
+		// remove images that are no longer used
+		imageService := image.GetService(&iqb)
+
+		for _, id := range existingImages {
+			if err := imageService.DestroyUnusedImage(id); err != nil {
+				return err
+			}
+		}
+
+		return logAudit(ctx, txn.GetTx(), "groupUpdate", models.AuditTargetTypeEnumGroup, group.ID, before, group)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return group, nil
+}
+
+func (r *mutationResolver) GroupDestroy(ctx context.Context, input models.GroupDestroyInput) (bool, error) {
+	if err := validateModify(ctx); err != nil {
+		return false, err
+	}
+
+	groupID, err := uuid.FromString(input.ID)
+	if err != nil {
+		return false, err
+	}
+
+	err = database.WithTransaction(ctx, func(txn database.Transaction) error {
+		qb := models.NewGroupQueryBuilder(txn.GetTx())
+
+		group, err := qb.Find(groupID)
+		if err != nil {
+			return err
+		}
+
+		if group == nil {
+			return models.NotFoundError(groupID)
+		}
+
+		// soft delete like destroy edits, since edits of the group keep
+		// referencing it
+		if _, err = qb.SoftDelete(*group); err != nil {
+			return err
+		}
+
+		return logAudit(ctx, txn.GetTx(), "groupDestroy", models.AuditTargetTypeEnumGroup, groupID, group, nil)
+	})
+
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func groupImageIDs(group *models.Group) []uuid.UUID {
+	var ret []uuid.UUID
+	if group == nil {
+		return ret
+	}
+
+	if group.FrontImageID.Valid {
+		ret = append(ret, group.FrontImageID.UUID)
+	}
+	if group.BackImageID.Valid {
+		ret = append(ret, group.BackImageID.UUID)
+	}
+
+	return ret
+}
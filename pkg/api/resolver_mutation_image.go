@@ -3,6 +3,8 @@ package api
 import (
 	"context"
 
+	"github.com/gofrs/uuid"
+
 	"github.com/stashapp/stash-box/pkg/database"
 	"github.com/stashapp/stash-box/pkg/image"
 	"github.com/stashapp/stash-box/pkg/models"
@@ -35,10 +37,24 @@ func (r *mutationResolver) ImageDestroy(ctx context.Context, input models.ImageD
 		return false, err
 	}
 
-	err := database.WithTransaction(ctx, func(txn database.Transaction) error {
+	imageID, err := uuid.FromString(input.ID)
+	if err != nil {
+		return false, err
+	}
+
+	err = database.WithTransaction(ctx, func(txn database.Transaction) error {
 		qb := models.NewImageQueryBuilder(txn.GetTx())
+		existing, err := qb.Find(imageID)
+		if err != nil {
+			return err
+		}
+
 		imageService := image.GetService(&qb)
-		return imageService.Destroy(input)
+		if err := imageService.Destroy(input); err != nil {
+			return err
+		}
+
+		return logAudit(ctx, txn.GetTx(), "imageDestroy", models.AuditTargetTypeEnumImage, imageID, existing, nil)
 	})
 
 	if err != nil {
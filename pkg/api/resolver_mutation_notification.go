@@ -0,0 +1,61 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/database"
+	"github.com/stashapp/stash-box/pkg/models"
+	"github.com/stashapp/stash-box/pkg/user"
+)
+
+func (r *mutationResolver) MarkNotificationsRead(ctx context.Context, ids []string) (int, error) {
+	currentUser := getCurrentUser(ctx)
+	if currentUser == nil {
+		return 0, ErrUnauthorized
+	}
+
+	var notificationIDs []uuid.UUID
+	if ids != nil {
+		notificationIDs = []uuid.UUID{}
+		for _, id := range ids {
+			notificationID, err := uuid.FromString(id)
+			if err != nil {
+				return 0, err
+			}
+			notificationIDs = append(notificationIDs, notificationID)
+		}
+	}
+
+	var ret int
+	err := database.WithTransaction(ctx, func(txn database.Transaction) error {
+		qb := models.NewNotificationQueryBuilder(txn.GetTx())
+
+		var err error
+		ret, err = qb.MarkRead(currentUser.ID, notificationIDs, models.SQLiteTimestamp{Timestamp: time.Now()})
+		return err
+	})
+
+	return ret, err
+}
+
+func (r *mutationResolver) UpdateNotificationPreferences(ctx context.Context, input []*models.NotificationPreferenceInput) ([]*models.NotificationPreference, error) {
+	currentUser := getCurrentUser(ctx)
+	if currentUser == nil {
+		return nil, ErrUnauthorized
+	}
+
+	var prefs models.UserNotificationPreferences
+	err := database.WithTransaction(ctx, func(txn database.Transaction) error {
+		var err error
+		prefs, err = user.UpdateNotificationPreferences(txn.GetTx(), currentUser.ID, input)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toNotificationPreferences(prefs), nil
+}
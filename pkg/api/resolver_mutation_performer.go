@@ -51,6 +51,10 @@ func (r *mutationResolver) PerformerCreate(ctx context.Context, input models.Per
 		}
 
 		// Save the URLs
+		sqb := models.NewSiteQueryBuilder(txn.GetTx())
+		if err := sqb.ResolveURLs(input.Urls, models.ValidSiteTypeEnumPerformer); err != nil {
+			return err
+		}
 		performerUrls := models.CreatePerformerUrls(performer.ID, input.Urls)
 		if err := qb.CreateUrls(performerUrls); err != nil {
 			return err
@@ -75,7 +79,12 @@ func (r *mutationResolver) PerformerCreate(ctx context.Context, input models.Per
 			return err
 		}
 
-		return nil
+		after, err := getAuditPerformer(txn.GetTx(), performer.ID)
+		if err != nil {
+			return err
+		}
+
+		return logAudit(ctx, txn.GetTx(), "performerCreate", models.AuditTargetTypeEnumPerformer, performer.ID, nil, after)
 	})
 
 	// Commit
@@ -109,6 +118,11 @@ func (r *mutationResolver) PerformerUpdate(ctx context.Context, input models.Per
 			return models.NotFoundError(performerID)
 		}
 
+		before, err := getAuditPerformer(txn.GetTx(), performerID)
+		if err != nil {
+			return err
+		}
+
 		updatedPerformer.UpdatedAt = models.SQLiteTimestamp{Timestamp: time.Now()}
 
 		// Populate performer from the input
@@ -128,6 +142,10 @@ func (r *mutationResolver) PerformerUpdate(ctx context.Context, input models.Per
 		}
 
 		// Save the URLs
+		sqb := models.NewSiteQueryBuilder(txn.GetTx())
+		if err := sqb.ResolveURLs(input.Urls, models.ValidSiteTypeEnumPerformer); err != nil {
+			return err
+		}
 		performerUrls := models.CreatePerformerUrls(performer.ID, input.Urls)
 		if err := qb.UpdateUrls(performer.ID, performerUrls); err != nil {
 			return err
@@ -163,7 +181,12 @@ func (r *mutationResolver) PerformerUpdate(ctx context.Context, input models.Per
 			}
 		}
 
-		return nil
+		after, err := getAuditPerformer(txn.GetTx(), performer.ID)
+		if err != nil {
+			return err
+		}
+
+		return logAudit(ctx, txn.GetTx(), "performerUpdate", models.AuditTargetTypeEnumPerformer, performer.ID, before, after)
 	})
 
 	// Commit
@@ -188,6 +211,11 @@ func (r *mutationResolver) PerformerDestroy(ctx context.Context, input models.Pe
 		qb := models.NewPerformerQueryBuilder(txn.GetTx())
 		iqb := models.NewImageQueryBuilder(txn.GetTx())
 
+		before, err := getAuditPerformer(txn.GetTx(), performerID)
+		if err != nil {
+			return err
+		}
+
 		// references have on delete cascade, so shouldn't be necessary
 		// to remove them explicitly
 
@@ -206,7 +234,7 @@ func (r *mutationResolver) PerformerDestroy(ctx context.Context, input models.Pe
 			}
 		}
 
-		return nil
+		return logAudit(ctx, txn.GetTx(), "performerDestroy", models.AuditTargetTypeEnumPerformer, performerID, before, nil)
 	})
 
 	if err != nil {
@@ -0,0 +1,185 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/database"
+	"github.com/stashapp/stash-box/pkg/manager"
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+func (r *mutationResolver) PerformerDuplicatesRefresh(ctx context.Context) (int, error) {
+	if err := validateAdmin(ctx); err != nil {
+		return 0, err
+	}
+
+	return manager.RefreshPerformerDuplicates(ctx)
+}
+
+func (r *mutationResolver) PerformerDuplicateDismiss(ctx context.Context, id string) (*models.PerformerDuplicate, error) {
+	if err := validateAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	duplicateID, err := uuid.FromString(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret *models.PerformerDuplicate
+	err = database.WithTransaction(ctx, func(txn database.Transaction) error {
+		qb := models.NewPerformerDuplicateQueryBuilder(txn.GetTx())
+
+		duplicate, err := findPendingPerformerDuplicate(qb, duplicateID)
+		if err != nil {
+			return err
+		}
+
+		before := *duplicate
+		duplicate.Status = models.PerformerDuplicateStatusEnumDismissed.String()
+		duplicate.UpdatedAt = models.SQLiteTimestamp{Timestamp: time.Now()}
+		ret, err = qb.Update(*duplicate)
+		if err != nil {
+			return err
+		}
+
+		return logAudit(ctx, txn.GetTx(), "performerDuplicateDismiss", models.AuditTargetTypeEnumPerformerDuplicate, duplicateID, before, ret)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (r *mutationResolver) PerformerDuplicateMerge(ctx context.Context, input models.PerformerDuplicateMergeInput) (*models.Edit, error) {
+	if err := validateAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	duplicateID, err := uuid.FromString(input.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret *models.Edit
+	err = database.WithTransaction(ctx, func(txn database.Transaction) error {
+		qb := models.NewPerformerDuplicateQueryBuilder(txn.GetTx())
+		pqb := models.NewPerformerQueryBuilder(txn.GetTx())
+		eqb := models.NewEditQueryBuilder(txn.GetTx())
+
+		duplicate, err := findPendingPerformerDuplicate(qb, duplicateID)
+		if err != nil {
+			return err
+		}
+
+		performer, err := pqb.Find(duplicate.PerformerID)
+		if err != nil {
+			return err
+		}
+		other, err := pqb.Find(duplicate.DuplicateID)
+		if err != nil {
+			return err
+		}
+		if performer == nil || other == nil || performer.Deleted || other.Deleted {
+			return errors.New("performer of duplicate pair no longer exists")
+		}
+
+		// keep the older performer unless specified otherwise
+		target, source := performer, other
+		if other.CreatedAt.Timestamp.Before(performer.CreatedAt.Timestamp) {
+			target, source = other, performer
+		}
+		if input.TargetID != nil {
+			switch *input.TargetID {
+			case performer.ID.String():
+				target, source = performer, other
+			case other.ID.String():
+				target, source = other, performer
+			default:
+				return errors.New("target must be one of the duplicate pair")
+			}
+		}
+
+		UUID, err := uuid.NewV4()
+		if err != nil {
+			return err
+		}
+
+		targetID := target.ID.String()
+		currentUser := getCurrentUser(ctx)
+		newEdit := models.NewEdit(UUID, currentUser, models.TargetTypeEnumPerformer, &models.EditInput{
+			ID:             &targetID,
+			Operation:      models.OperationEnumMerge,
+			MergeSourceIds: []string{source.ID.String()},
+		})
+
+		// merge without modifying the target
+		if err := newEdit.SetData(models.PerformerEditData{
+			New:             &models.PerformerEdit{},
+			Old:             &models.PerformerEdit{},
+			MergeSources:    []string{source.ID.String()},
+			SetMergeAliases: true,
+		}); err != nil {
+			return err
+		}
+
+		created, err := eqb.Create(*newEdit)
+		if err != nil {
+			return err
+		}
+
+		if err := eqb.CreateEditPerformer(models.EditPerformer{
+			EditID:      created.ID,
+			PerformerID: target.ID,
+		}); err != nil {
+			return err
+		}
+
+		if input.Comment != nil && len(*input.Comment) > 0 {
+			commentID, _ := uuid.NewV4()
+			comment := models.NewEditComment(commentID, currentUser, created, *input.Comment)
+			if err := eqb.CreateComment(*comment); err != nil {
+				return err
+			}
+		}
+
+		before := *duplicate
+		duplicate.Status = models.PerformerDuplicateStatusEnumMerged.String()
+		duplicate.EditID = uuid.NullUUID{UUID: created.ID, Valid: true}
+		duplicate.UpdatedAt = models.SQLiteTimestamp{Timestamp: time.Now()}
+		updated, err := qb.Update(*duplicate)
+		if err != nil {
+			return err
+		}
+
+		ret = created
+		return logAudit(ctx, txn.GetTx(), "performerDuplicateMerge", models.AuditTargetTypeEnumPerformerDuplicate, duplicateID, before, updated)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func findPendingPerformerDuplicate(qb models.PerformerDuplicateQueryBuilder, id uuid.UUID) (*models.PerformerDuplicate, error) {
+	duplicate, err := qb.Find(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if duplicate == nil {
+		return nil, errors.New("performer duplicate with id " + id.String() + " not found")
+	}
+
+	if duplicate.Status != models.PerformerDuplicateStatusEnumPending.String() {
+		return nil, errors.New("performer duplicate has already been reviewed")
+	}
+
+	return duplicate, nil
+}
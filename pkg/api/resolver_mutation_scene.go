@@ -2,13 +2,17 @@ package api
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"time"
 
 	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
 
 	"github.com/stashapp/stash-box/pkg/database"
 	"github.com/stashapp/stash-box/pkg/image"
 	"github.com/stashapp/stash-box/pkg/models"
+	"github.com/stashapp/stash-box/pkg/utils"
 )
 
 func (r *mutationResolver) SceneCreate(ctx context.Context, input models.SceneCreateInput) (*models.Scene, error) {
@@ -37,6 +41,15 @@ func (r *mutationResolver) SceneCreate(ctx context.Context, input models.SceneCr
 		jqb := models.NewJoinsQueryBuilder(txn.GetTx())
 
 		var err error
+		input.TagIds, err = resolveTagNames(txn.GetTx(), input.TagIds, input.TagNames)
+		if err != nil {
+			return err
+		}
+		input.Performers, err = resolvePerformerNames(txn.GetTx(), input.Performers, input.PerformersByName)
+		if err != nil {
+			return err
+		}
+
 		scene, err = qb.Create(newScene)
 		if err != nil {
 			return err
@@ -49,12 +62,19 @@ func (r *mutationResolver) SceneCreate(ctx context.Context, input models.SceneCr
 		}
 
 		// save the performers
-		scenePerformers := models.CreateScenePerformers(scene.ID, input.Performers)
+		scenePerformers, err := models.CreateScenePerformers(scene.ID, input.Performers)
+		if err != nil {
+			return err
+		}
 		if err := jqb.CreatePerformersScenes(scenePerformers); err != nil {
 			return err
 		}
 
 		// Save the URLs
+		sqb := models.NewSiteQueryBuilder(txn.GetTx())
+		if err := sqb.ResolveURLs(input.Urls, models.ValidSiteTypeEnumScene); err != nil {
+			return err
+		}
 		sceneUrls := models.CreateSceneUrls(scene.ID, input.Urls)
 		if err := qb.CreateUrls(sceneUrls); err != nil {
 			return err
@@ -74,7 +94,12 @@ func (r *mutationResolver) SceneCreate(ctx context.Context, input models.SceneCr
 			return err
 		}
 
-		return nil
+		after, err := getAuditScene(txn.GetTx(), scene.ID)
+		if err != nil {
+			return err
+		}
+
+		return logAudit(ctx, txn.GetTx(), "sceneCreate", models.AuditTargetTypeEnumScene, scene.ID, nil, after)
 	})
 
 	if err != nil {
@@ -95,6 +120,16 @@ func (r *mutationResolver) SceneUpdate(ctx context.Context, input models.SceneUp
 		jqb := models.NewJoinsQueryBuilder(txn.GetTx())
 		iqb := models.NewImageQueryBuilder(txn.GetTx())
 
+		var err error
+		input.TagIds, err = resolveTagNames(txn.GetTx(), input.TagIds, input.TagNames)
+		if err != nil {
+			return err
+		}
+		input.Performers, err = resolvePerformerNames(txn.GetTx(), input.Performers, input.PerformersByName)
+		if err != nil {
+			return err
+		}
+
 		// get the existing scene and modify it
 		sceneID, _ := uuid.FromString(input.ID)
 		updatedScene, err := qb.Find(sceneID)
@@ -103,6 +138,15 @@ func (r *mutationResolver) SceneUpdate(ctx context.Context, input models.SceneUp
 			return err
 		}
 
+		if updatedScene == nil {
+			return models.NotFoundError(sceneID)
+		}
+
+		before, err := getAuditScene(txn.GetTx(), sceneID)
+		if err != nil {
+			return err
+		}
+
 		updatedScene.UpdatedAt = models.SQLiteTimestamp{Timestamp: time.Now()}
 
 		// Populate scene from the input
@@ -119,7 +163,10 @@ func (r *mutationResolver) SceneUpdate(ctx context.Context, input models.SceneUp
 			return err
 		}
 
-		scenePerformers := models.CreateScenePerformers(scene.ID, input.Performers)
+		scenePerformers, err := models.CreateScenePerformers(scene.ID, input.Performers)
+		if err != nil {
+			return err
+		}
 		if err := jqb.UpdatePerformersScenes(scene.ID, scenePerformers); err != nil {
 			return err
 		}
@@ -131,6 +178,10 @@ func (r *mutationResolver) SceneUpdate(ctx context.Context, input models.SceneUp
 		}
 
 		// Save the URLs
+		sqb := models.NewSiteQueryBuilder(txn.GetTx())
+		if err := sqb.ResolveURLs(input.Urls, models.ValidSiteTypeEnumScene); err != nil {
+			return err
+		}
 		sceneUrls := models.CreateSceneUrls(scene.ID, input.Urls)
 		if err := qb.UpdateUrls(scene.ID, sceneUrls); err != nil {
 			return err
@@ -154,7 +205,12 @@ func (r *mutationResolver) SceneUpdate(ctx context.Context, input models.SceneUp
 			}
 		}
 
-		return nil
+		after, err := getAuditScene(txn.GetTx(), scene.ID)
+		if err != nil {
+			return err
+		}
+
+		return logAudit(ctx, txn.GetTx(), "sceneUpdate", models.AuditTargetTypeEnumScene, scene.ID, before, after)
 	})
 
 	if err != nil {
@@ -178,6 +234,11 @@ func (r *mutationResolver) SceneDestroy(ctx context.Context, input models.SceneD
 		qb := models.NewSceneQueryBuilder(txn.GetTx())
 		iqb := models.NewImageQueryBuilder(txn.GetTx())
 
+		before, err := getAuditScene(txn.GetTx(), sceneID)
+		if err != nil {
+			return err
+		}
+
 		existingImages, err := iqb.FindBySceneID(sceneID)
 
 		// references have on delete cascade, so shouldn't be necessary
@@ -195,7 +256,7 @@ func (r *mutationResolver) SceneDestroy(ctx context.Context, input models.SceneD
 			}
 		}
 
-		return nil
+		return logAudit(ctx, txn.GetTx(), "sceneDestroy", models.AuditTargetTypeEnumScene, sceneID, before, nil)
 	})
 
 	if err != nil {
@@ -228,3 +289,83 @@ func (r *mutationResolver) SubmitFingerprint(ctx context.Context, input models.F
 
 	return true, nil
 }
+
+// resolveTagNames returns the tag ids with the ids of the named tags appended.
+// Returns an error listing the names that do not match any tag.
+func resolveTagNames(tx *sqlx.Tx, tagIDs []string, tagNames []string) ([]string, error) {
+	if len(tagNames) == 0 {
+		return tagIDs, nil
+	}
+
+	tqb := models.NewTagQueryBuilder(tx)
+	ret := append([]string{}, tagIDs...)
+	var unknown []string
+	for _, name := range tagNames {
+		tag, err := tqb.ResolveName(name)
+		if err != nil {
+			return nil, err
+		}
+		if tag == nil {
+			unknown = append(unknown, name)
+			continue
+		}
+
+		tagID := tag.ID.String()
+		if !utils.StrInclude(ret, tagID) {
+			ret = append(ret, tagID)
+		}
+	}
+
+	if len(unknown) > 0 {
+		return nil, errors.New("unknown tags: " + strings.Join(unknown, ", "))
+	}
+
+	return ret, nil
+}
+
+// resolvePerformerNames returns the appearances with the appearances of the
+// named performers appended. Returns an error listing the names that do not
+// match exactly one performer.
+func resolvePerformerNames(tx *sqlx.Tx, appearances []*models.PerformerAppearanceInput, named []*models.PerformerNameAppearanceInput) ([]*models.PerformerAppearanceInput, error) {
+	if len(named) == 0 {
+		return appearances, nil
+	}
+
+	pqb := models.NewPerformerQueryBuilder(tx)
+	ret := append([]*models.PerformerAppearanceInput{}, appearances...)
+	var unknown []string
+	var ambiguous []string
+	for _, appearance := range named {
+		performers, err := pqb.ResolveName(appearance.PerformerName)
+		if err != nil {
+			return nil, err
+		}
+		switch len(performers) {
+		case 0:
+			unknown = append(unknown, appearance.PerformerName)
+		case 1:
+			ret = append(ret, &models.PerformerAppearanceInput{
+				PerformerID: performers[0].ID.String(),
+				As:          appearance.As,
+				Role:        appearance.Role,
+				Position:    appearance.Position,
+				Credited:    appearance.Credited,
+			})
+		default:
+			ambiguous = append(ambiguous, appearance.PerformerName)
+		}
+	}
+
+	var messages []string
+	if len(unknown) > 0 {
+		messages = append(messages, "unknown performers: "+strings.Join(unknown, ", "))
+	}
+	if len(ambiguous) > 0 {
+		messages = append(messages, "ambiguous performers: "+strings.Join(ambiguous, ", "))
+	}
+	if len(messages) > 0 {
+		return nil, errors.New(strings.Join(messages, "; "))
+	}
+
+	return ret, nil
+}
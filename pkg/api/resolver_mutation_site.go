@@ -0,0 +1,146 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/database"
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+func (r *mutationResolver) SiteCreate(ctx context.Context, input models.SiteCreateInput) (*models.Site, error) {
+	if err := validateAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	UUID, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+
+	// Populate a new site from the input
+	currentTime := time.Now()
+	newSite := models.Site{
+		ID:        UUID,
+		CreatedAt: models.SQLiteTimestamp{Timestamp: currentTime},
+		UpdatedAt: models.SQLiteTimestamp{Timestamp: currentTime},
+	}
+
+	newSite.CopyFromCreateInput(input)
+
+	if err := newSite.ValidateRegex(); err != nil {
+		return nil, err
+	}
+
+	// Start the transaction and save the site
+	tx := database.DB.MustBeginTx(ctx, nil)
+	qb := models.NewSiteQueryBuilder(tx)
+	site, err := qb.Create(newSite)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	if err := logAudit(ctx, tx, "siteCreate", models.AuditTargetTypeEnumSite, site.ID, nil, site); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	// Commit
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return site, nil
+}
+
+func (r *mutationResolver) SiteUpdate(ctx context.Context, input models.SiteUpdateInput) (*models.Site, error) {
+	if err := validateAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	tx := database.DB.MustBeginTx(ctx, nil)
+	qb := models.NewSiteQueryBuilder(tx)
+
+	// get the existing site and modify it
+	siteID, _ := uuid.FromString(input.ID)
+	updatedSite, err := qb.Find(siteID)
+
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	if updatedSite == nil {
+		_ = tx.Rollback()
+		return nil, errors.New("site with id " + siteID.String() + " not found")
+	}
+
+	before := *updatedSite
+	updatedSite.UpdatedAt = models.SQLiteTimestamp{Timestamp: time.Now()}
+
+	// Populate site from the input
+	updatedSite.CopyFromUpdateInput(input)
+
+	if err := updatedSite.ValidateRegex(); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	site, err := qb.Update(*updatedSite)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	if err := logAudit(ctx, tx, "siteUpdate", models.AuditTargetTypeEnumSite, site.ID, before, site); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	// Commit
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return site, nil
+}
+
+func (r *mutationResolver) SiteDestroy(ctx context.Context, input models.SiteDestroyInput) (bool, error) {
+	if err := validateAdmin(ctx); err != nil {
+		return false, err
+	}
+
+	tx := database.DB.MustBeginTx(ctx, nil)
+	qb := models.NewSiteQueryBuilder(tx)
+
+	siteID, err := uuid.FromString(input.ID)
+	if err != nil {
+		_ = tx.Rollback()
+		return false, err
+	}
+
+	site, err := qb.Find(siteID)
+	if err != nil {
+		_ = tx.Rollback()
+		return false, err
+	}
+
+	if err = qb.Destroy(siteID); err != nil {
+		_ = tx.Rollback()
+		return false, err
+	}
+
+	if err := logAudit(ctx, tx, "siteDestroy", models.AuditTargetTypeEnumSite, siteID, site, nil); err != nil {
+		_ = tx.Rollback()
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
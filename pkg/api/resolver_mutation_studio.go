@@ -42,15 +42,29 @@ func (r *mutationResolver) StudioCreate(ctx context.Context, input models.Studio
 		qb := models.NewStudioQueryBuilder(txn.GetTx())
 		jqb := models.NewJoinsQueryBuilder(txn.GetTx())
 
+		if err := qb.ValidateNames(newStudio.ID, newStudio.Name, input.Aliases); err != nil {
+			return err
+		}
+
 		var err error
 		studio, err = qb.Create(newStudio)
 		if err != nil {
 			return err
 		}
 
+		// Save the aliases
+		studioAliases := models.CreateStudioAliases(studio.ID, input.Aliases)
+		if err := qb.CreateAliases(studioAliases); err != nil {
+			return err
+		}
+
 		// TODO - save child studios
 
 		// Save the URLs
+		sqb := models.NewSiteQueryBuilder(txn.GetTx())
+		if err := sqb.ResolveURLs(input.Urls, models.ValidSiteTypeEnumStudio); err != nil {
+			return err
+		}
 		studioUrls := models.CreateStudioUrls(studio.ID, input.Urls)
 		if err := qb.CreateUrls(studioUrls); err != nil {
 			return err
@@ -63,7 +77,12 @@ func (r *mutationResolver) StudioCreate(ctx context.Context, input models.Studio
 			return err
 		}
 
-		return nil
+		after, err := getAuditStudio(txn.GetTx(), studio.ID)
+		if err != nil {
+			return err
+		}
+
+		return logAudit(ctx, txn.GetTx(), "studioCreate", models.AuditTargetTypeEnumStudio, studio.ID, nil, after)
 	})
 
 	if err != nil {
@@ -92,18 +111,41 @@ func (r *mutationResolver) StudioUpdate(ctx context.Context, input models.Studio
 			return err
 		}
 
+		if updatedStudio == nil {
+			return models.NotFoundError(studioID)
+		}
+
+		before, err := getAuditStudio(txn.GetTx(), studioID)
+		if err != nil {
+			return err
+		}
+
 		updatedStudio.UpdatedAt = models.SQLiteTimestamp{Timestamp: time.Now()}
 
 		// Populate studio from the input
 		updatedStudio.CopyFromUpdateInput(input)
 
+		if err := qb.ValidateNames(updatedStudio.ID, updatedStudio.Name, input.Aliases); err != nil {
+			return err
+		}
+
 		studio, err = qb.Update(*updatedStudio)
 		if err != nil {
 			return err
 		}
 
+		// Save the aliases
+		studioAliases := models.CreateStudioAliases(studio.ID, input.Aliases)
+		if err := qb.UpdateAliases(studio.ID, studioAliases); err != nil {
+			return err
+		}
+
 		// Save the URLs
 		// TODO - only do this if provided
+		sqb := models.NewSiteQueryBuilder(txn.GetTx())
+		if err := sqb.ResolveURLs(input.Urls, models.ValidSiteTypeEnumStudio); err != nil {
+			return err
+		}
 		studioUrls := models.CreateStudioUrls(studio.ID, input.Urls)
 
 		if err := qb.UpdateUrls(studio.ID, studioUrls); err != nil {
@@ -130,7 +172,12 @@ func (r *mutationResolver) StudioUpdate(ctx context.Context, input models.Studio
 			}
 		}
 
-		return nil
+		after, err := getAuditStudio(txn.GetTx(), studio.ID)
+		if err != nil {
+			return err
+		}
+
+		return logAudit(ctx, txn.GetTx(), "studioUpdate", models.AuditTargetTypeEnumStudio, studio.ID, before, after)
 	})
 
 	if err != nil {
@@ -154,6 +201,11 @@ func (r *mutationResolver) StudioDestroy(ctx context.Context, input models.Studi
 		qb := models.NewStudioQueryBuilder(txn.GetTx())
 		iqb := models.NewImageQueryBuilder(txn.GetTx())
 
+		before, err := getAuditStudio(txn.GetTx(), studioID)
+		if err != nil {
+			return err
+		}
+
 		existingImages, err := iqb.FindByStudioID(studioID)
 
 		// references have on delete cascade, so shouldn't be necessary
@@ -171,7 +223,7 @@ func (r *mutationResolver) StudioDestroy(ctx context.Context, input models.Studi
 			}
 		}
 
-		return nil
+		return logAudit(ctx, txn.GetTx(), "studioDestroy", models.AuditTargetTypeEnumStudio, studioID, before, nil)
 	})
 
 	if err != nil {
@@ -51,6 +51,11 @@ func (r *mutationResolver) TagCreate(ctx context.Context, input models.TagCreate
 		return nil, err
 	}
 
+	if err := logAudit(ctx, tx, "tagCreate", models.AuditTargetTypeEnumTag, tag.ID, nil, tag); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
 	// Commit
 	if err := tx.Commit(); err != nil {
 		return nil, err
@@ -72,9 +77,16 @@ func (r *mutationResolver) TagUpdate(ctx context.Context, input models.TagUpdate
 	updatedTag, err := qb.Find(tagID)
 
 	if err != nil {
+		_ = tx.Rollback()
 		return nil, err
 	}
 
+	if updatedTag == nil {
+		_ = tx.Rollback()
+		return nil, models.NotFoundError(tagID)
+	}
+
+	before := *updatedTag
 	updatedTag.UpdatedAt = models.SQLiteTimestamp{Timestamp: time.Now()}
 
 	// Populate performer from the input
@@ -94,6 +106,11 @@ func (r *mutationResolver) TagUpdate(ctx context.Context, input models.TagUpdate
 		return nil, err
 	}
 
+	if err := logAudit(ctx, tx, "tagUpdate", models.AuditTargetTypeEnumTag, tag.ID, before, tag); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
 	// Commit
 	if err := tx.Commit(); err != nil {
 		return nil, err
@@ -115,13 +132,26 @@ func (r *mutationResolver) TagDestroy(ctx context.Context, input models.TagDestr
 
 	tagID, err := uuid.FromString(input.ID)
 	if err != nil {
+		_ = tx.Rollback()
+		return false, err
+	}
+
+	tag, err := qb.Find(tagID)
+	if err != nil {
+		_ = tx.Rollback()
 		return false, err
 	}
+
 	if err = qb.Destroy(tagID); err != nil {
 		_ = tx.Rollback()
 		return false, err
 	}
 
+	if err := logAudit(ctx, tx, "tagDestroy", models.AuditTargetTypeEnumTag, tagID, tag, nil); err != nil {
+		_ = tx.Rollback()
+		return false, err
+	}
+
 	if err := tx.Commit(); err != nil {
 		return false, err
 	}
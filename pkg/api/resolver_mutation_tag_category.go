@@ -45,6 +45,11 @@ func (r *mutationResolver) TagCategoryCreate(ctx context.Context, input models.T
 		return nil, err
 	}
 
+	if err := logAudit(ctx, tx, "tagCategoryCreate", models.AuditTargetTypeEnumTagCategory, category.ID, nil, category); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
 	// Commit
 	if err := tx.Commit(); err != nil {
 		return nil, err
@@ -66,9 +71,16 @@ func (r *mutationResolver) TagCategoryUpdate(ctx context.Context, input models.T
 	updatedCategory, err := qb.Find(categoryID)
 
 	if err != nil {
+		_ = tx.Rollback()
 		return nil, err
 	}
 
+	if updatedCategory == nil {
+		_ = tx.Rollback()
+		return nil, models.NotFoundError(categoryID)
+	}
+
+	before := *updatedCategory
 	updatedCategory.UpdatedAt = models.SQLiteTimestamp{Timestamp: time.Now()}
 
 	// Populate category from the input
@@ -80,6 +92,11 @@ func (r *mutationResolver) TagCategoryUpdate(ctx context.Context, input models.T
 		return nil, err
 	}
 
+	if err := logAudit(ctx, tx, "tagCategoryUpdate", models.AuditTargetTypeEnumTagCategory, category.ID, before, category); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
 	// Commit
 	if err := tx.Commit(); err != nil {
 		return nil, err
@@ -98,13 +115,26 @@ func (r *mutationResolver) TagCategoryDestroy(ctx context.Context, input models.
 
 	categoryID, err := uuid.FromString(input.ID)
 	if err != nil {
+		_ = tx.Rollback()
+		return false, err
+	}
+
+	category, err := qb.Find(categoryID)
+	if err != nil {
+		_ = tx.Rollback()
 		return false, err
 	}
+
 	if err = qb.Destroy(categoryID); err != nil {
 		_ = tx.Rollback()
 		return false, err
 	}
 
+	if err := logAudit(ctx, tx, "tagCategoryDestroy", models.AuditTargetTypeEnumTagCategory, categoryID, category, nil); err != nil {
+		_ = tx.Rollback()
+		return false, err
+	}
+
 	if err := tx.Commit(); err != nil {
 		return false, err
 	}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/gofrs/uuid"
 
@@ -32,6 +33,11 @@ func (r *mutationResolver) UserCreate(ctx context.Context, input models.UserCrea
 		return nil, err
 	}
 
+	if err := logAudit(ctx, tx, "userCreate", models.AuditTargetTypeEnumUser, u.ID, nil, newAuditUser(u, input.Roles)); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
 	// Commit
 	if err := tx.Commit(); err != nil {
 		return nil, err
@@ -64,12 +70,23 @@ func (r *mutationResolver) UserUpdate(ctx context.Context, input models.UserUpda
 		return nil, err
 	}
 
+	before, err := getAuditUser(tx, userID)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
 	user, err := user.Update(tx, input)
 	if err != nil {
 		_ = tx.Rollback()
 		return nil, err
 	}
 
+	if err := logAudit(ctx, tx, "userUpdate", models.AuditTargetTypeEnumUser, userID, before, newAuditUser(user, input.Roles)); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
 	// Commit
 	if err := tx.Commit(); err != nil {
 		return nil, err
@@ -102,6 +119,12 @@ func (r *mutationResolver) UserDestroy(ctx context.Context, input models.UserDes
 		return false, err
 	}
 
+	before, err := getAuditUser(tx, userID)
+	if err != nil {
+		_ = tx.Rollback()
+		return false, err
+	}
+
 	ret, err := user.Destroy(tx, input)
 
 	if err != nil {
@@ -109,6 +132,11 @@ func (r *mutationResolver) UserDestroy(ctx context.Context, input models.UserDes
 		return false, err
 	}
 
+	if err := logAudit(ctx, tx, "userDestroy", models.AuditTargetTypeEnumUser, userID, before, nil); err != nil {
+		_ = tx.Rollback()
+		return false, err
+	}
+
 	if err := tx.Commit(); err != nil {
 		return false, err
 	}
@@ -122,6 +150,10 @@ func (r *mutationResolver) RegenerateAPIKey(ctx context.Context, userID *string)
 		return "", ErrUnauthorized
 	}
 
+	if err := validateNotNamedAPIKey(ctx); err != nil {
+		return "", err
+	}
+
 	if userID != nil {
 		if currentUser.ID.String() != *userID {
 			// changing another user api key
@@ -152,6 +184,294 @@ func (r *mutationResolver) RegenerateAPIKey(ctx context.Context, userID *string)
 	return ret, err
 }
 
+func (r *mutationResolver) APIKeyCreate(ctx context.Context, input models.APIKeyCreateInput) (*models.APIKeyCreateResult, error) {
+	currentUser := getCurrentUser(ctx)
+	if currentUser == nil {
+		return nil, ErrUnauthorized
+	}
+
+	if err := validateNotNamedAPIKey(ctx); err != nil {
+		return nil, err
+	}
+
+	var ret *models.APIKeyCreateResult
+	err := database.WithTransaction(ctx, func(txn database.Transaction) error {
+		apiKey, key, err := user.CreateAPIKey(txn.GetTx(), currentUser.ID, getCurrentRoles(ctx), input)
+		if err != nil {
+			return err
+		}
+
+		ret = &models.APIKeyCreateResult{
+			APIKey: apiKey,
+			Key:    key,
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *mutationResolver) APIKeyRevoke(ctx context.Context, id string) (bool, error) {
+	currentUser := getCurrentUser(ctx)
+	if currentUser == nil {
+		return false, ErrUnauthorized
+	}
+
+	if err := validateNotNamedAPIKey(ctx); err != nil {
+		return false, err
+	}
+
+	keyID, err := uuid.FromString(id)
+	if err != nil {
+		return false, err
+	}
+
+	// admins may revoke the keys of any user
+	userID := &currentUser.ID
+	if validateAdmin(ctx) == nil {
+		userID = nil
+	}
+
+	err = database.WithTransaction(ctx, func(txn database.Transaction) error {
+		return user.RevokeAPIKey(txn.GetTx(), keyID, userID)
+	})
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *mutationResolver) RevokeSession(ctx context.Context, id string) (bool, error) {
+	currentUser := getCurrentUser(ctx)
+	if currentUser == nil {
+		return false, ErrUnauthorized
+	}
+
+	if err := validateNotNamedAPIKey(ctx); err != nil {
+		return false, err
+	}
+
+	sessionID, err := uuid.FromString(id)
+	if err != nil {
+		return false, err
+	}
+
+	// admins may revoke the sessions of any user
+	userID := &currentUser.ID
+	if validateAdmin(ctx) == nil {
+		userID = nil
+	}
+
+	err = database.WithTransaction(ctx, func(txn database.Transaction) error {
+		return user.RevokeSession(txn.GetTx(), sessionID, userID)
+	})
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *mutationResolver) TotpEnroll(ctx context.Context) (*models.TOTPEnrollment, error) {
+	currentUser := getCurrentUser(ctx)
+	if currentUser == nil {
+		return nil, ErrUnauthorized
+	}
+
+	if err := validateNotNamedAPIKey(ctx); err != nil {
+		return nil, err
+	}
+
+	var ret *models.TOTPEnrollment
+	err := database.WithTransaction(ctx, func(txn database.Transaction) error {
+		var txnErr error
+		ret, txnErr = user.EnrollTOTP(txn.GetTx(), currentUser.ID)
+		return txnErr
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *mutationResolver) TotpActivate(ctx context.Context, code string) ([]string, error) {
+	currentUser := getCurrentUser(ctx)
+	if currentUser == nil {
+		return nil, ErrUnauthorized
+	}
+
+	if err := validateNotNamedAPIKey(ctx); err != nil {
+		return nil, err
+	}
+
+	var ret []string
+	err := database.WithTransaction(ctx, func(txn database.Transaction) error {
+		var txnErr error
+		ret, txnErr = user.ActivateTOTP(txn.GetTx(), currentUser.ID, code)
+		return txnErr
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *mutationResolver) TotpRegenerateRecoveryCodes(ctx context.Context, code string) ([]string, error) {
+	currentUser := getCurrentUser(ctx)
+	if currentUser == nil {
+		return nil, ErrUnauthorized
+	}
+
+	if err := validateNotNamedAPIKey(ctx); err != nil {
+		return nil, err
+	}
+
+	var ret []string
+	err := database.WithTransaction(ctx, func(txn database.Transaction) error {
+		var txnErr error
+		ret, txnErr = user.RegenerateRecoveryCodes(txn.GetTx(), currentUser.ID, code)
+		return txnErr
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *mutationResolver) TotpDisable(ctx context.Context, code string) (bool, error) {
+	currentUser := getCurrentUser(ctx)
+	if currentUser == nil {
+		return false, ErrUnauthorized
+	}
+
+	if err := validateNotNamedAPIKey(ctx); err != nil {
+		return false, err
+	}
+
+	err := database.WithTransaction(ctx, func(txn database.Transaction) error {
+		return user.DisableTOTP(txn.GetTx(), currentUser.ID, code)
+	})
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *mutationResolver) TotpReset(ctx context.Context, userID string) (bool, error) {
+	if err := validateAdmin(ctx); err != nil {
+		return false, err
+	}
+
+	id, err := uuid.FromString(userID)
+	if err != nil {
+		return false, err
+	}
+
+	err = database.WithTransaction(ctx, func(txn database.Transaction) error {
+		before, err := getAuditUser(txn.GetTx(), id)
+		if err != nil {
+			return err
+		}
+
+		if err := user.ResetTOTP(txn.GetTx(), id); err != nil {
+			return err
+		}
+
+		after, err := getAuditUser(txn.GetTx(), id)
+		if err != nil {
+			return err
+		}
+
+		return logAudit(ctx, txn.GetTx(), "totpReset", models.AuditTargetTypeEnumUser, id, before, after)
+	})
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *mutationResolver) SuspendUser(ctx context.Context, id string, until *time.Time, reason string) (*models.UserModerationAction, error) {
+	if err := validateAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	userID, err := uuid.FromString(id)
+	if err != nil {
+		return nil, err
+	}
+
+	currentUser := getCurrentUser(ctx)
+
+	var ret *models.UserModerationAction
+	err = database.WithTransaction(ctx, func(txn database.Transaction) error {
+		var txnErr error
+		ret, txnErr = user.Suspend(txn.GetTx(), userID, currentUser.ID, until, reason)
+		if txnErr != nil {
+			return txnErr
+		}
+
+		return logAudit(ctx, txn.GetTx(), "suspendUser", models.AuditTargetTypeEnumUser, userID, nil, ret)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *mutationResolver) UnsuspendUser(ctx context.Context, id string, reason *string) (*models.UserModerationAction, error) {
+	if err := validateAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	userID, err := uuid.FromString(id)
+	if err != nil {
+		return nil, err
+	}
+
+	currentUser := getCurrentUser(ctx)
+
+	unsuspendReason := ""
+	if reason != nil {
+		unsuspendReason = *reason
+	}
+
+	var ret *models.UserModerationAction
+	err = database.WithTransaction(ctx, func(txn database.Transaction) error {
+		var txnErr error
+		ret, txnErr = user.Unsuspend(txn.GetTx(), userID, currentUser.ID, unsuspendReason)
+		if txnErr != nil {
+			return txnErr
+		}
+
+		return logAudit(ctx, txn.GetTx(), "unsuspendUser", models.AuditTargetTypeEnumUser, userID, nil, ret)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
 func (r *mutationResolver) ResetPassword(ctx context.Context, input models.ResetPasswordInput) (bool, error) {
 	err := database.WithTransaction(ctx, func(txn database.Transaction) error {
 		return user.ResetPassword(txn.GetTx(), manager.GetInstance().EmailManager, input.Email)
@@ -184,6 +504,10 @@ func (r *mutationResolver) ChangePassword(ctx context.Context, input models.User
 		return false, ErrUnauthorized
 	}
 
+	if err := validateNotNamedAPIKey(ctx); err != nil {
+		return false, err
+	}
+
 	if input.ExistingPassword == nil {
 		return false, user.ErrCurrentPasswordIncorrect
 	}
@@ -194,7 +518,13 @@ func (r *mutationResolver) ChangePassword(ctx context.Context, input models.User
 
 	tx := database.DB.MustBeginTx(ctx, nil)
 
-	err := user.ChangePassword(tx, userID, *input.ExistingPassword, input.NewPassword)
+	// other sessions of the user are ended
+	var currentSessionID *uuid.UUID
+	if session := getCurrentSession(ctx); session != nil {
+		currentSessionID = &session.ID
+	}
+
+	err := user.ChangePassword(tx, userID, *input.ExistingPassword, input.NewPassword, currentSessionID)
 	if err != nil {
 		_ = tx.Rollback()
 		return false, err
@@ -334,7 +664,11 @@ func (r *mutationResolver) GrantInvite(ctx context.Context, input models.GrantIn
 		qb := models.NewUserQueryBuilder(txn.GetTx())
 		userID, _ := uuid.FromString(input.UserID)
 
-		var txnErr error
+		before, txnErr := getAuditUser(txn.GetTx(), userID)
+		if txnErr != nil {
+			return txnErr
+		}
+
 		ret, txnErr = user.GrantInviteTokens(&qb, userID, input.Amount)
 		if txnErr != nil {
 			return txnErr
@@ -343,7 +677,12 @@ func (r *mutationResolver) GrantInvite(ctx context.Context, input models.GrantIn
 		// log the operation
 		logger.Userf(currentUser.Name, "GrantInvite", "+ %d to %s = %d", input.Amount, userID.String(), ret)
 
-		return nil
+		after, txnErr := getAuditUser(txn.GetTx(), userID)
+		if txnErr != nil {
+			return txnErr
+		}
+
+		return logAudit(ctx, txn.GetTx(), "grantInvite", models.AuditTargetTypeEnumUser, userID, before, after)
 	})
 
 	if err != nil {
@@ -364,7 +703,11 @@ func (r *mutationResolver) RevokeInvite(ctx context.Context, input models.Revoke
 		qb := models.NewUserQueryBuilder(txn.GetTx())
 		userID, _ := uuid.FromString(input.UserID)
 
-		var txnErr error
+		before, txnErr := getAuditUser(txn.GetTx(), userID)
+		if txnErr != nil {
+			return txnErr
+		}
+
 		ret, txnErr = user.RepealInviteTokens(&qb, userID, input.Amount)
 		if txnErr != nil {
 			return txnErr
@@ -373,7 +716,12 @@ func (r *mutationResolver) RevokeInvite(ctx context.Context, input models.Revoke
 		// log the operation
 		logger.Userf(currentUser.Name, "RevokeInvite", "- %d to %s = %d", input.Amount, userID.String(), ret)
 
-		return nil
+		after, txnErr := getAuditUser(txn.GetTx(), userID)
+		if txnErr != nil {
+			return txnErr
+		}
+
+		return logAudit(ctx, txn.GetTx(), "revokeInvite", models.AuditTargetTypeEnumUser, userID, before, after)
 	})
 
 	if err != nil {
@@ -0,0 +1,25 @@
+package api
+
+import (
+	"context"
+
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+func (r *queryResolver) QueryAuditLog(ctx context.Context, auditLogFilter *models.AuditLogFilterType, filter *models.QuerySpec) (*models.QueryAuditLogResultType, error) {
+	if err := validateAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	qb := models.NewAuditLogQueryBuilder(nil)
+
+	entries, count, err := qb.Query(auditLogFilter, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.QueryAuditLogResultType{
+		Entries: entries,
+		Count:   count,
+	}, nil
+}
@@ -0,0 +1,34 @@
+package api
+
+import (
+	"context"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+func (r *queryResolver) FindGroup(ctx context.Context, id string) (*models.Group, error) {
+	if err := validateRead(ctx); err != nil {
+		return nil, err
+	}
+
+	qb := models.NewGroupQueryBuilder(nil)
+
+	idUUID, _ := uuid.FromString(id)
+	return qb.Find(idUUID)
+}
+
+func (r *queryResolver) QueryGroups(ctx context.Context, groupFilter *models.GroupFilterType, filter *models.QuerySpec) (*models.QueryGroupsResultType, error) {
+	if err := validateRead(ctx); err != nil {
+		return nil, err
+	}
+
+	qb := models.NewGroupQueryBuilder(nil)
+
+	groups, count := qb.Query(groupFilter, filter)
+	return &models.QueryGroupsResultType{
+		Groups: groups,
+		Count:  count,
+	}, nil
+}
@@ -2,19 +2,25 @@ package api
 
 import (
 	"context"
+	"fmt"
+	"time"
+
 	"github.com/gofrs/uuid"
 
 	"github.com/stashapp/stash-box/pkg/models"
 )
 
-func (r *queryResolver) FindPerformer(ctx context.Context, id string) (*models.Performer, error) {
+func (r *queryResolver) FindPerformer(ctx context.Context, id string, asOf *time.Time) (*models.Performer, error) {
 	if err := validateRead(ctx); err != nil {
 		return nil, err
 	}
 
-	qb := models.NewPerformerQueryBuilder(nil)
-
 	idUUID, _ := uuid.FromString(id)
+	if asOf != nil {
+		return models.FindPerformerAsOf(nil, idUUID, *asOf)
+	}
+
+	qb := models.NewPerformerQueryBuilder(nil)
 	return qb.Find(idUUID)
 }
 func (r *queryResolver) QueryPerformers(ctx context.Context, performerFilter *models.PerformerFilterType, filter *models.QuerySpec) (*models.QueryPerformersResultType, error) {
@@ -30,3 +36,47 @@ func (r *queryResolver) QueryPerformers(ctx context.Context, performerFilter *mo
 		Count:      count,
 	}, nil
 }
+
+// maxPerformerConnectionDepth bounds the search for performer connections,
+// since each level of the search can reach many more performers.
+const maxPerformerConnectionDepth = 4
+
+func (r *queryResolver) PerformerConnections(ctx context.Context, a string, b string, maxDepth *int) (*models.PerformerConnection, error) {
+	if err := validateRead(ctx); err != nil {
+		return nil, err
+	}
+
+	aID, err := uuid.FromString(a)
+	if err != nil {
+		return nil, err
+	}
+	bID, err := uuid.FromString(b)
+	if err != nil {
+		return nil, err
+	}
+
+	depth := 3
+	if maxDepth != nil {
+		depth = *maxDepth
+	}
+	if depth < 1 || depth > maxPerformerConnectionDepth {
+		return nil, fmt.Errorf("max_depth must be between 1 and %d", maxPerformerConnectionDepth)
+	}
+
+	qb := models.NewPerformerQueryBuilder(nil)
+	return qb.FindConnection(aID, bID, depth)
+}
+
+func (r *queryResolver) QueryPerformerDuplicates(ctx context.Context, duplicateFilter *models.PerformerDuplicateFilterType, filter *models.QuerySpec) (*models.QueryPerformerDuplicatesResultType, error) {
+	if err := validateAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	qb := models.NewPerformerDuplicateQueryBuilder(nil)
+
+	duplicates, count := qb.Query(duplicateFilter, filter)
+	return &models.QueryPerformerDuplicatesResultType{
+		Duplicates: duplicates,
+		Count:      count,
+	}, nil
+}
@@ -50,7 +50,11 @@ func (r *queryResolver) QueryScenes(ctx context.Context, sceneFilter *models.Sce
 
 	qb := models.NewSceneQueryBuilder(nil)
 
-	scenes, count := qb.Query(sceneFilter, filter)
+	scenes, count, err := qb.Query(sceneFilter, filter)
+	if err != nil {
+		return nil, err
+	}
+
 	return &models.QueryScenesResultType{
 		Scenes: scenes,
 		Count:  count,
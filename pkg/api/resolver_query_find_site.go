@@ -0,0 +1,38 @@
+package api
+
+import (
+	"context"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+func (r *queryResolver) FindSite(ctx context.Context, id string) (*models.Site, error) {
+	if err := validateRead(ctx); err != nil {
+		return nil, err
+	}
+
+	qb := models.NewSiteQueryBuilder(nil)
+
+	UUID, _ := uuid.FromString(id)
+	return qb.Find(UUID)
+}
+
+func (r *queryResolver) QuerySites(ctx context.Context, siteFilter *models.SiteFilterType, filter *models.QuerySpec) (*models.QuerySitesResultType, error) {
+	if err := validateRead(ctx); err != nil {
+		return nil, err
+	}
+
+	qb := models.NewSiteQueryBuilder(nil)
+
+	sites, count, err := qb.Query(siteFilter, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.QuerySitesResultType{
+		Sites: sites,
+		Count: count,
+	}, nil
+}
@@ -2,26 +2,34 @@ package api
 
 import (
 	"context"
+	"time"
+
 	"github.com/gofrs/uuid"
 
 	"github.com/stashapp/stash-box/pkg/models"
 )
 
-func (r *queryResolver) FindTag(ctx context.Context, id *string, name *string) (*models.Tag, error) {
+func (r *queryResolver) FindTag(ctx context.Context, id *string, name *string, asOf *time.Time) (*models.Tag, error) {
 	if err := validateRead(ctx); err != nil {
 		return nil, err
 	}
 
 	qb := models.NewTagQueryBuilder(nil)
 
+	var tag *models.Tag
+	var err error
 	if id != nil {
 		idUUID, _ := uuid.FromString(*id)
-		return qb.Find(idUUID)
+		tag, err = qb.Find(idUUID)
 	} else if name != nil {
-		return qb.FindByName(*name)
+		tag, err = qb.FindByName(*name)
+	}
+
+	if err != nil || tag == nil || asOf == nil {
+		return tag, err
 	}
 
-	return nil, nil
+	return models.FindTagAsOf(nil, tag.ID, *asOf)
 }
 
 func (r *queryResolver) QueryTags(ctx context.Context, tagFilter *models.TagFilterType, filter *models.QuerySpec) (*models.QueryTagsResultType, error) {
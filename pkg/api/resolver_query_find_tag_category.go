@@ -35,3 +35,12 @@ func (r *queryResolver) QueryTagCategories(ctx context.Context, filter *models.Q
 		Count:         count,
 	}, nil
 }
+
+func (r *queryResolver) TagCategoryUsage(ctx context.Context, group *models.TagGroupEnum) ([]*models.TagCategoryUsage, error) {
+	if err := validateRead(ctx); err != nil {
+		return nil, err
+	}
+
+	qb := models.NewTagCategoryQueryBuilder(nil)
+	return qb.GetUsage(group)
+}
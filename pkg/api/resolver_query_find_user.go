@@ -2,9 +2,11 @@ package api
 
 import (
 	"context"
+	"time"
 
 	"github.com/gofrs/uuid"
 
+	"github.com/stashapp/stash-box/pkg/manager/config"
 	"github.com/stashapp/stash-box/pkg/models"
 )
 
@@ -61,6 +63,55 @@ func (r *queryResolver) Me(ctx context.Context) (*models.User, error) {
 	return currentUser, nil
 }
 
+func (r *queryResolver) TopAPIConsumers(ctx context.Context, limit *int) ([]*models.UserAPICallCount, error) {
+	if err := validateAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	n := 10
+	if limit != nil {
+		n = *limit
+	}
+
+	qb := models.NewUserQueryBuilder(nil)
+	since := time.Now().Add(-config.GetAPICallWindow())
+	return qb.FindTopAPIConsumers(since, n)
+}
+
+func (r *queryResolver) MySessions(ctx context.Context) ([]*models.UserSession, error) {
+	currentUser := getCurrentUser(ctx)
+	if currentUser == nil {
+		return nil, ErrUnauthorized
+	}
+
+	qb := models.NewUserSessionQueryBuilder(nil)
+	return qb.FindByUserID(currentUser.ID, time.Now())
+}
+
+func (r *queryResolver) APIKeys(ctx context.Context, userID *string) ([]*models.APIKey, error) {
+	currentUser := getCurrentUser(ctx)
+	if currentUser == nil {
+		return nil, ErrUnauthorized
+	}
+
+	id := currentUser.ID
+	if userID != nil && *userID != currentUser.ID.String() {
+		// listing another user's keys must be admin
+		if err := validateAdmin(ctx); err != nil {
+			return nil, err
+		}
+
+		var err error
+		id, err = uuid.FromString(*userID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	qb := models.NewAPIKeyQueryBuilder(nil)
+	return qb.FindByUserID(id)
+}
+
 func removeSensitiveUserDetails(ctx context.Context, users models.Users) {
 	// don't need to remove details if we're admin
 	if validateAdmin(ctx) == nil {
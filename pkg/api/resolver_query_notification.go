@@ -0,0 +1,65 @@
+package api
+
+import (
+	"context"
+
+	"github.com/stashapp/stash-box/pkg/models"
+	"github.com/stashapp/stash-box/pkg/user"
+)
+
+func (r *queryResolver) Notifications(ctx context.Context, unreadOnly *bool, filter *models.QuerySpec) (*models.QueryNotificationsResultType, error) {
+	currentUser := getCurrentUser(ctx)
+	if currentUser == nil {
+		return nil, ErrUnauthorized
+	}
+
+	qb := models.NewNotificationQueryBuilder(nil)
+
+	notifications, count, err := qb.Query(currentUser.ID, unreadOnly != nil && *unreadOnly, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	unreadCount, err := qb.CountUnread(currentUser.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.QueryNotificationsResultType{
+		Count:         count,
+		UnreadCount:   unreadCount,
+		Notifications: notifications,
+	}, nil
+}
+
+func (r *queryResolver) NotificationPreferences(ctx context.Context) ([]*models.NotificationPreference, error) {
+	currentUser := getCurrentUser(ctx)
+	if currentUser == nil {
+		return nil, ErrUnauthorized
+	}
+
+	prefs, err := user.GetNotificationPreferences(nil, currentUser.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toNotificationPreferences(prefs), nil
+}
+
+func toNotificationPreferences(prefs models.UserNotificationPreferences) []*models.NotificationPreference {
+	var ret []*models.NotificationPreference
+	for _, p := range prefs {
+		var t models.NotificationTypeEnum
+		if !resolveEnumString(p.Type, &t) {
+			continue
+		}
+
+		ret = append(ret, &models.NotificationPreference{
+			Type:    t,
+			Enabled: p.Enabled,
+			Email:   p.Email,
+		})
+	}
+
+	return ret
+}
@@ -59,3 +59,37 @@ func (r *queryResolver) SearchScene(ctx context.Context, term string, limit *int
 
 	return qb.SearchScenes(trimmedQuery, searchLimit)
 }
+
+func (r *queryResolver) SearchStudio(ctx context.Context, term string, limit *int) ([]*models.Studio, error) {
+	if err := validateRead(ctx); err != nil {
+		return nil, err
+	}
+
+	qb := models.NewStudioQueryBuilder(nil)
+
+	trimmedQuery := strings.TrimSpace(term)
+	studioID, err := uuid.FromString(trimmedQuery)
+	if err == nil {
+		var studios []*models.Studio
+		studio, err := qb.Find(studioID)
+		if studio != nil {
+			studios = append(studios, studio)
+		}
+		return studios, err
+	}
+
+	searchLimit := 5
+	if limit != nil {
+		searchLimit = *limit
+	}
+
+	return qb.SearchStudios(trimmedQuery, searchLimit)
+}
+
+func (r *queryResolver) FindByURL(ctx context.Context, url string) (*models.URLMatches, error) {
+	if err := validateRead(ctx); err != nil {
+		return nil, err
+	}
+
+	return models.FindByURL(nil, url)
+}
@@ -4,6 +4,7 @@ package api_test
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/stashapp/stash-box/pkg/api"
@@ -91,6 +92,20 @@ func comparePerformers(input []*models.PerformerAppearanceInput, performers []*m
 				return false
 			}
 		}
+
+		if v.Role != input[i].Role {
+			if v.Role == nil || input[i].Role == nil {
+				return false
+			}
+
+			if *v.Role != *input[i].Role {
+				return false
+			}
+		}
+
+		if v.Credited != (input[i].Credited == nil || *input[i].Credited) {
+			return false
+		}
 	}
 
 	return true
@@ -165,7 +180,7 @@ func (s *sceneTestRunner) verifyCreatedScene(input models.SceneCreateInput, scen
 		s.fieldMismatch(input.Performers, performers, "Performers")
 	}
 
-	tags, err := s.resolver.Scene().Tags(s.ctx, scene)
+	tags, err := s.resolver.Scene().Tags(s.ctx, scene, nil, nil)
 	if err != nil {
 		s.t.Errorf("Error getting scene tags: %s", err.Error())
 	}
@@ -410,7 +425,7 @@ func (s *sceneTestRunner) verifyUpdatedScene(input models.SceneUpdateInput, scen
 		s.fieldMismatch(input.Performers, performers, "Performers")
 	}
 
-	tags, _ := s.resolver.Scene().Tags(s.ctx, scene)
+	tags, _ := s.resolver.Scene().Tags(s.ctx, scene, nil, nil)
 	if !compareTags(input.TagIds, tags) {
 		s.fieldMismatch(input.TagIds, tags, "Tags")
 	}
@@ -498,14 +513,9 @@ func (s *sceneTestRunner) verifyInvalidModifier(filter models.SceneFilterType) {
 		PerPage: &pageSize,
 	}
 
-	defer func() {
-		if r := recover(); r != nil {
-			// success
-		} else {
-			s.t.Error("Expected error for invalid modifier")
-		}
-	}()
-	s.resolver.Query().QueryScenes(s.ctx, &filter, &querySpec)
+	if _, err := s.resolver.Query().QueryScenes(s.ctx, &filter, &querySpec); err == nil {
+		s.t.Error("Expected error for invalid modifier")
+	}
 }
 
 func (s *sceneTestRunner) testQueryScenesByStudio() {
@@ -673,6 +683,9 @@ func (s *sceneTestRunner) testQueryScenesByPerformer() {
 
 	filter.Performers.Modifier = models.CriterionModifierNotNull
 	s.verifyInvalidModifier(filter)
+
+	filter.Performers.Modifier = models.CriterionModifierIncludesChildren
+	s.verifyInvalidModifier(filter)
 }
 
 func (s *sceneTestRunner) testQueryScenesByTag() {
@@ -755,6 +768,284 @@ func (s *sceneTestRunner) testQueryScenesByTag() {
 	s.verifyInvalidModifier(filter)
 }
 
+func (s *sceneTestRunner) testQueryScenesByFuzzyDate() {
+	prefix := "testQueryScenesByFuzzyDate_"
+	dayTitle := prefix + "day"
+	monthTitle := prefix + "month"
+	yearTitle := prefix + "year"
+
+	dayDate := "2003-02-15"
+	monthDate := "2003-02"
+	yearDate := "2003"
+	monthAccuracy := models.DateAccuracyEnumMonth
+	yearAccuracy := models.DateAccuracyEnumYear
+
+	dayScene, err := s.createTestScene(&models.SceneCreateInput{
+		Title: &dayTitle,
+		Date:  &dayDate,
+	})
+	if err != nil {
+		return
+	}
+
+	monthScene, err := s.createTestScene(&models.SceneCreateInput{
+		Title:        &monthTitle,
+		Date:         &monthDate,
+		DateAccuracy: &monthAccuracy,
+	})
+	if err != nil {
+		return
+	}
+
+	yearScene, err := s.createTestScene(&models.SceneCreateInput{
+		Title:        &yearTitle,
+		Date:         &yearDate,
+		DateAccuracy: &yearAccuracy,
+	})
+	if err != nil {
+		return
+	}
+
+	accuracy, _ := s.resolver.Scene().DateAccuracy(s.ctx, monthScene)
+	if accuracy == nil || *accuracy != monthAccuracy {
+		s.fieldMismatch(monthAccuracy, accuracy, "DateAccuracy")
+	}
+
+	date, _ := s.resolver.Scene().Date(s.ctx, monthScene)
+	if date == nil || *date != "2003-02-01" {
+		s.fieldMismatch("2003-02-01", date, "Date")
+	}
+
+	dayID := dayScene.ID.String()
+	monthID := monthScene.ID.String()
+	yearID := yearScene.ID.String()
+
+	// a day within the month matches all three scenes
+	filter := models.SceneFilterType{
+		Title: &prefix,
+		Date: &models.DateCriterionInput{
+			Value:    "2003-02-15",
+			Modifier: models.CriterionModifierEquals,
+		},
+	}
+	s.verifyQueryScenesResult(filter, []string{dayID, monthID, yearID})
+
+	// a day outside the month only matches the year
+	filter.Date.Value = "2003-03-10"
+	s.verifyQueryScenesResult(filter, []string{yearID})
+
+	filter.Date.Modifier = models.CriterionModifierNotEquals
+	s.verifyQueryScenesResult(filter, []string{dayID, monthID})
+
+	filter.Date.Modifier = models.CriterionModifierLessThan
+	s.verifyQueryScenesResult(filter, []string{dayID, monthID})
+
+	filter.Date.Modifier = models.CriterionModifierGreaterThan
+	filter.Date.Value = "2003-02-10"
+	s.verifyQueryScenesResult(filter, []string{dayID})
+}
+
+func (s *sceneTestRunner) testQueryScenesByAlias() {
+	prefix := "testQueryScenesByAlias_"
+	aliasTitle := prefix + "alias"
+	roleTitle := prefix + "role"
+	plainTitle := prefix + "plain"
+
+	performer, _ := s.createTestPerformer(nil)
+	performerID := performer.ID.String()
+
+	alias := prefix + "stage name"
+	role := prefix + "detective"
+	credited := false
+	position := 1
+
+	aliasScene, err := s.createTestScene(&models.SceneCreateInput{
+		Title: &aliasTitle,
+		Performers: []*models.PerformerAppearanceInput{
+			&models.PerformerAppearanceInput{
+				PerformerID: performerID,
+				As:          &alias,
+			},
+		},
+	})
+	if err != nil {
+		return
+	}
+
+	roleInput := []*models.PerformerAppearanceInput{
+		&models.PerformerAppearanceInput{
+			PerformerID: performerID,
+			Role:        &role,
+			Position:    &position,
+			Credited:    &credited,
+		},
+	}
+	roleScene, err := s.createTestScene(&models.SceneCreateInput{
+		Title:      &roleTitle,
+		Performers: roleInput,
+	})
+	if err != nil {
+		return
+	}
+
+	performers, _ := s.resolver.Scene().Performers(s.ctx, roleScene)
+	if !comparePerformers(roleInput, performers) {
+		s.fieldMismatch(roleInput, performers, "Performers")
+	}
+	if len(performers) == 1 && (performers[0].Position == nil || *performers[0].Position != position) {
+		s.fieldMismatch(position, performers[0].Position, "Position")
+	}
+
+	plainScene, err := s.createTestScene(&models.SceneCreateInput{
+		Title: &plainTitle,
+		Performers: []*models.PerformerAppearanceInput{
+			&models.PerformerAppearanceInput{
+				PerformerID: performerID,
+			},
+		},
+	})
+	if err != nil {
+		return
+	}
+
+	aliasID := aliasScene.ID.String()
+	roleID := roleScene.ID.String()
+	plainID := plainScene.ID.String()
+
+	filter := models.SceneFilterType{
+		Title: &prefix,
+		Alias: &models.StringCriterionInput{
+			Value:    "stage name",
+			Modifier: models.CriterionModifierEquals,
+		},
+	}
+	s.verifyQueryScenesResult(filter, []string{aliasID})
+
+	filter.Alias.Value = "detective"
+	s.verifyQueryScenesResult(filter, []string{roleID})
+
+	filter.Alias.Modifier = models.CriterionModifierNotEquals
+	s.verifyQueryScenesResult(filter, []string{aliasID, plainID})
+
+	filter.Alias.Modifier = models.CriterionModifierIsNull
+	s.verifyQueryScenesResult(filter, []string{plainID})
+}
+
+func (s *sceneTestRunner) testSceneNameResolution() {
+	alias := s.generateTagName()
+	tag, err := s.createTestTag(&models.TagCreateInput{
+		Name:    s.generateTagName(),
+		Aliases: []string{alias},
+	})
+	if err != nil {
+		return
+	}
+	performer, err := s.createTestPerformer(nil)
+	if err != nil {
+		return
+	}
+
+	title := "title"
+	input := models.SceneCreateInput{
+		Title:    &title,
+		TagNames: []string{alias},
+		PerformersByName: []*models.PerformerNameAppearanceInput{
+			&models.PerformerNameAppearanceInput{
+				PerformerName: performer.Name,
+			},
+		},
+		Fingerprints: []*models.FingerprintInput{
+			s.generateSceneFingerprint(),
+		},
+	}
+	scene, err := s.createTestScene(&input)
+	if err != nil {
+		return
+	}
+
+	tags, _ := s.resolver.Scene().Tags(s.ctx, scene, nil, nil)
+	if len(tags) != 1 || tags[0].ID != tag.ID {
+		s.fieldMismatch(tag.ID, tags, "Tags")
+	}
+	performers, _ := s.resolver.Scene().Performers(s.ctx, scene)
+	if len(performers) != 1 || performers[0].Performer.ID != performer.ID {
+		s.fieldMismatch(performer.ID, performers, "Performers")
+	}
+
+	unknownName := s.generateTagName()
+	input.TagNames = []string{tag.Name, unknownName}
+	input.Fingerprints = []*models.FingerprintInput{
+		s.generateSceneFingerprint(),
+	}
+	_, err = s.resolver.Mutation().SceneCreate(s.ctx, input)
+	if err == nil || !strings.Contains(err.Error(), unknownName) {
+		s.t.Errorf("Expected error listing unknown tag %s, got %v", unknownName, err)
+	}
+
+	unknownPerformer := s.generatePerformerName()
+	input.TagNames = nil
+	input.PerformersByName = []*models.PerformerNameAppearanceInput{
+		&models.PerformerNameAppearanceInput{
+			PerformerName: unknownPerformer,
+		},
+	}
+	_, err = s.resolver.Mutation().SceneCreate(s.ctx, input)
+	if err == nil || !strings.Contains(err.Error(), unknownPerformer) {
+		s.t.Errorf("Expected error listing unknown performer %s, got %v", unknownPerformer, err)
+	}
+	input.PerformersByName = nil
+
+	// an active tag takes precedence over a deleted tag with the same name
+	tagID := tag.ID.String()
+	destroyEdit, err := s.createTestTagEdit(models.OperationEnumDestroy, &models.TagEditDetailsInput{}, &models.EditInput{
+		Operation: models.OperationEnumDestroy,
+		ID:        &tagID,
+	})
+	if err != nil {
+		return
+	}
+	if _, err := s.applyEdit(destroyEdit.ID.String()); err != nil {
+		return
+	}
+	activeTag, err := s.createTestTag(&models.TagCreateInput{
+		Name: tag.Name,
+	})
+	if err != nil {
+		return
+	}
+
+	input.TagNames = []string{tag.Name}
+	input.Fingerprints = []*models.FingerprintInput{
+		s.generateSceneFingerprint(),
+	}
+	scene, err = s.createTestScene(&input)
+	if err != nil {
+		return
+	}
+
+	tags, _ = s.resolver.Scene().Tags(s.ctx, scene, nil, nil)
+	if len(tags) != 1 || tags[0].ID != activeTag.ID {
+		s.fieldMismatch(activeTag.ID, tags, "Tags")
+	}
+}
+
+func (s *sceneTestRunner) testSceneEditNotImplemented() {
+	date := "2005"
+	accuracy := models.DateAccuracyEnumYear
+	_, err := s.resolver.Mutation().SceneEdit(s.ctx, models.SceneEditInput{
+		Edit: &models.EditInput{
+			Operation: models.OperationEnumCreate,
+		},
+		Details: &models.SceneEditDetailsInput{
+			Date:         &date,
+			DateAccuracy: &accuracy,
+		},
+	})
+	if err != api.ErrSceneEditsNotImplemented {
+		s.t.Errorf("Expected ErrSceneEditsNotImplemented, got %v", err)
+	}
+}
+
 func (s *sceneTestRunner) testUnauthorisedSceneModify() {
 	// test each api interface - all require modify so all should fail
 	_, err := s.resolver.Mutation().SceneCreate(s.ctx, models.SceneCreateInput{})
@@ -834,6 +1125,26 @@ func TestQueryScenesByTag(t *testing.T) {
 	pt.testQueryScenesByTag()
 }
 
+func TestQueryScenesByFuzzyDate(t *testing.T) {
+	pt := createSceneTestRunner(t)
+	pt.testQueryScenesByFuzzyDate()
+}
+
+func TestQueryScenesByAlias(t *testing.T) {
+	pt := createSceneTestRunner(t)
+	pt.testQueryScenesByAlias()
+}
+
+func TestSceneNameResolution(t *testing.T) {
+	pt := createSceneTestRunner(t)
+	pt.testSceneNameResolution()
+}
+
+func TestSceneEditNotImplemented(t *testing.T) {
+	pt := createSceneTestRunner(t)
+	pt.testSceneEditNotImplemented()
+}
+
 func TestUnauthorisedSceneModify(t *testing.T) {
 	pt := &sceneTestRunner{
 		testRunner: *asRead(t),
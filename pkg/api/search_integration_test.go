@@ -3,6 +3,7 @@
 package api_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stashapp/stash-box/pkg/api"
@@ -127,6 +128,149 @@ func (s *searchTestRunner) testSearchSceneByID() {
 		s.fieldMismatch(createdScene.ID, scenes[0].ID, "ID")
 	}
 }
+func (s *searchTestRunner) testFindByURL() {
+	performer, err := s.createTestPerformer(&models.PerformerCreateInput{
+		Name: s.generatePerformerName(),
+		Urls: []*models.URLInput{
+			&models.URLInput{
+				URL:  "https://www.example.com/models/testFindByURL/?ref_id=1",
+				Type: "HOME",
+			},
+		},
+	})
+	if err != nil {
+		return
+	}
+
+	scene, err := s.createTestScene(&models.SceneCreateInput{
+		Urls: []*models.URLInput{
+			&models.URLInput{
+				URL:  "http://example.com/models/testFindByURL?ref_id=1&utm_source=feed",
+				Type: "STUDIO",
+			},
+		},
+	})
+	if err != nil {
+		return
+	}
+
+	variants := []string{
+		"https://www.example.com/models/testFindByURL/?ref_id=1",
+		"http://EXAMPLE.com/models/testFindByURL?utm_medium=social&ref_id=1#bio",
+		"example.com/models/testFindByURL/?ref_id=1&fbclid=abc",
+	}
+
+	for _, url := range variants {
+		matches, err := s.resolver.Query().FindByURL(s.ctx, url)
+		if err != nil {
+			s.t.Errorf("Error finding by url: %s", err.Error())
+			return
+		}
+
+		if len(matches.Performers) != 1 || matches.Performers[0].ID != performer.ID {
+			s.t.Errorf("Expected performer %s for url %s, got %v", performer.ID, url, matches.Performers)
+		}
+
+		if len(matches.Scenes) != 1 || matches.Scenes[0].ID != scene.ID {
+			s.t.Errorf("Expected scene %s for url %s, got %v", scene.ID, url, matches.Scenes)
+		}
+	}
+
+	// path case and query values are significant
+	matches, _ := s.resolver.Query().FindByURL(s.ctx, "https://example.com/models/testFindByURL?ref_id=2")
+	if len(matches.Performers) != 0 || len(matches.Scenes) != 0 {
+		s.t.Errorf("Expected no matches for different query")
+	}
+}
+
+func (s *searchTestRunner) testURLEditWarning() {
+	url := "https://example.com/models/testURLEditWarning"
+	existing, err := s.createTestPerformer(&models.PerformerCreateInput{
+		Name: s.generatePerformerName(),
+		Urls: []*models.URLInput{
+			&models.URLInput{
+				URL:  url,
+				Type: "HOME",
+			},
+		},
+	})
+	if err != nil {
+		return
+	}
+
+	name := s.generatePerformerName()
+	edit, err := s.createTestPerformerEdit(models.OperationEnumCreate, &models.PerformerEditDetailsInput{
+		Name: &name,
+		Urls: []*models.URLInput{
+			&models.URLInput{
+				URL:  "http://www.example.com/models/testURLEditWarning/",
+				Type: "HOME",
+			},
+		},
+	}, nil, nil)
+	if err != nil {
+		return
+	}
+
+	warnings, err := s.resolver.Edit().Warnings(s.ctx, edit)
+	if err != nil {
+		s.t.Errorf("Error getting warnings: %s", err.Error())
+		return
+	}
+
+	if len(warnings) != 1 || warnings[0].Type != models.EditWarningTypeEnumURLAlreadyAttached {
+		s.t.Errorf("Expected url warning for performer %s, got %v", existing.ID, warnings)
+	}
+
+	// no warning when modifying the performer that has the url
+	modifyName := s.generatePerformerName()
+	existingID := existing.ID.String()
+	edit, err = s.createTestPerformerEdit(models.OperationEnumModify, &models.PerformerEditDetailsInput{
+		Name: &modifyName,
+		Urls: []*models.URLInput{
+			&models.URLInput{
+				URL:  url,
+				Type: "HOME",
+			},
+			&models.URLInput{
+				URL:  "https://example.com/models/testURLEditWarning/2",
+				Type: "SOCIAL",
+			},
+		},
+	}, &models.EditInput{
+		Operation: models.OperationEnumModify,
+		ID:        &existingID,
+	}, nil)
+	if err != nil {
+		return
+	}
+
+	warnings, _ = s.resolver.Edit().Warnings(s.ctx, edit)
+	if len(warnings) != 0 {
+		s.t.Errorf("Expected no warnings modifying performer, got %v", warnings)
+	}
+
+	// studio edits are checked too
+	studioName := s.generateStudioName()
+	edit, err = s.createTestStudioEdit(models.OperationEnumCreate, &models.StudioEditDetailsInput{
+		Name: &studioName,
+		Urls: []*models.URLInput{
+			&models.URLInput{
+				URL:  url,
+				Type: "HOME",
+			},
+		},
+	}, nil)
+	if err != nil {
+		return
+	}
+
+	warnings, _ = s.resolver.Edit().Warnings(s.ctx, edit)
+	if len(warnings) != 1 || !strings.Contains(warnings[0].Message, existing.Name) {
+		s.t.Errorf("Expected url warning for performer %s on studio edit, got %v", existing.ID, warnings)
+	}
+}
+
 func (s *searchTestRunner) testUnauthorisedSearch() {
 	// test each api interface - all require read so all should fail
 	_, err := s.resolver.Query().SearchPerformer(s.ctx, "", nil)
@@ -159,6 +303,16 @@ func TestSearchSceneByID(t *testing.T) {
 	pt := createSearchTestRunner(t)
 	pt.testSearchSceneByID()
 }
+func TestFindByURL(t *testing.T) {
+	pt := createSearchTestRunner(t)
+	pt.testFindByURL()
+}
+
+func TestURLEditWarning(t *testing.T) {
+	pt := createSearchTestRunner(t)
+	pt.testURLEditWarning()
+}
+
 func TestUnauthorisedSearch(t *testing.T) {
 	pt := &searchTestRunner{
 		testRunner: *asNone(t),
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"path"
 	"runtime/debug"
@@ -44,26 +45,7 @@ func getUserAndRoles(userID string) (*models.User, []models.RoleEnum, error) {
 		return nil, nil, err
 	}
 
-	return u, roles, nil
-}
-
-// returns the userID, a boolean set to true if api key was used, and an error
-func getRequestUserID(w http.ResponseWriter, r *http.Request) (string, bool, error) {
-	userID := ""
-	isAPIKey := false
-	var err error
-
-	// translate api key into current user, if present
-	apiKey := r.Header.Get(ApiKeyHeader)
-	if apiKey != "" {
-		isAPIKey = true
-		userID, err = user.GetUserIDFromAPIKey(apiKey)
-	} else {
-		// handle session
-		userID, err = getSessionUserID(w, r)
-	}
-
-	return userID, isAPIKey, err
+	return u, user.FilterTOTPRoles(u, roles), nil
 }
 
 func authenticateHandler() func(http.Handler) http.Handler {
@@ -74,12 +56,26 @@ func authenticateHandler() func(http.Handler) http.Handler {
 			// translate api key into current user, if present
 			userID := ""
 			apiKey := r.Header.Get(ApiKeyHeader)
+			isNamedKey := user.IsNamedAPIKey(apiKey)
+			var keyRoles []models.RoleEnum
+			var session *models.UserSession
 			var err error
-			if apiKey != "" {
+			if isNamedKey {
+				userID, keyRoles, err = user.GetNamedAPIKeyUser(apiKey)
+			} else if apiKey != "" {
 				userID, err = user.GetUserIDFromAPIKey(apiKey)
 			} else {
 				// handle session
-				userID, err = getSessionUserID(w, r)
+				session, err = getSession(w, r)
+				if session != nil {
+					userID = session.UserID.String()
+				}
+			}
+
+			if err == user.ErrInvalidToken {
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte(err.Error()))
+				return
 			}
 
 			if err != nil {
@@ -88,19 +84,38 @@ func authenticateHandler() func(http.Handler) http.Handler {
 				return
 			}
 
-			user, roles, err := getUserAndRoles(userID)
+			currentUser, roles, err := getUserAndRoles(userID)
 
 			// ensure api key of the user matches the passed one
-			if apiKey != "" && user != nil && user.APIKey != apiKey {
+			if apiKey != "" && !isNamedKey && currentUser != nil && currentUser.APIKey != apiKey {
 				w.WriteHeader(http.StatusUnauthorized)
-				w.Write([]byte(err.Error()))
+				w.Write([]byte(user.ErrInvalidToken.Error()))
 				return
 			}
 
-			// TODO - increment api key counters
+			// suspended users may not use api keys
+			if apiKey != "" && currentUser != nil {
+				if err := user.ValidateNotSuspended(nil, currentUser); err != nil {
+					status := http.StatusInternalServerError
+					if user.IsSuspendedError(err) {
+						status = http.StatusForbidden
+					}
+					w.WriteHeader(status)
+					w.Write([]byte(err.Error()))
+					return
+				}
+			}
+
+			// named keys only grant their roles that the user still has
+			if isNamedKey {
+				roles = user.RestrictRoles(roles, keyRoles)
+			}
 
-			ctx = context.WithValue(ctx, ContextUser, user)
+			ctx = context.WithValue(ctx, ContextUser, currentUser)
 			ctx = context.WithValue(ctx, ContextRoles, roles)
+			ctx = context.WithValue(ctx, ContextSession, session)
+			ctx = context.WithValue(ctx, ContextIP, getRequestIP(r))
+			ctx = context.WithValue(ctx, ContextNamedAPIKey, isNamedKey)
 
 			r = r.WithContext(ctx)
 
@@ -109,6 +124,29 @@ func authenticateHandler() func(http.Handler) http.Handler {
 	}
 }
 
+// countAPICallHandler counts the request towards the API calls of the
+// current user. It must run after authenticateHandler.
+func countAPICallHandler() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if currentUser := getCurrentUser(r.Context()); currentUser != nil {
+				user.CountAPICall(currentUser.ID)
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// getRequestIP returns the IP address of the client making the request.
+func getRequestIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
 func redirect(w http.ResponseWriter, req *http.Request) {
 	target := "https://" + req.Host + req.URL.Path
 	if len(req.URL.RawQuery) > 0 {
@@ -159,7 +197,7 @@ func Start() {
 	})
 	gqlHandler := handler.GraphQL(models.NewExecutableSchema(models.Config{Resolvers: &Resolver{}}), recoverFunc, requestMiddleware, websocketUpgrader)
 
-	r.Handle("/graphql", dataloader.Middleware(gqlHandler))
+	r.With(rateLimitHandler(), countAPICallHandler()).Handle("/graphql", dataloader.Middleware(gqlHandler))
 
 	if !config.GetIsProduction() {
 		r.Handle("/playground", handler.Playground("GraphQL playground", "/graphql"))
@@ -177,6 +215,9 @@ func Start() {
 		return
 	})
 	r.HandleFunc("/logout", handleLogout)
+	r.HandleFunc("/oidc/login", handleOIDCLogin)
+	r.HandleFunc("/oidc/link", handleOIDCLink)
+	r.HandleFunc("/oidc/callback", handleOIDCCallback)
 
 	r.Mount("/image", imageRoutes{}.Routes())
 
@@ -3,20 +3,29 @@ package api
 import (
 	"net/http"
 
+	"github.com/gofrs/uuid"
+	"github.com/gorilla/sessions"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/stashapp/stash-box/pkg/database"
 	"github.com/stashapp/stash-box/pkg/manager/config"
+	"github.com/stashapp/stash-box/pkg/models"
 	"github.com/stashapp/stash-box/pkg/user"
-
-	"github.com/gorilla/sessions"
 )
 
 const cookieName = "session"
 const usernameFormKey = "username"
 const passwordFormKey = "password"
-const userIDKey = "userID"
-const maxCookieAge = 60 * 60 * 1 // 1 hours
+const deviceFormKey = "device"
+const totpFormKey = "totp"
+const sessionTokenKey = "sessionToken"
 
 var sessionStore = sessions.NewCookieStore(config.GetSessionStoreKey())
 
+func getCookieMaxAge() int {
+	return int(config.GetSessionExpiry().Seconds())
+}
+
 func handleLogin(w http.ResponseWriter, r *http.Request) {
 	newSession, err := sessionStore.Get(r, cookieName)
 
@@ -25,8 +34,8 @@ func handleLogin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	username := r.FormValue("username")
-	password := r.FormValue("password")
+	username := r.FormValue(usernameFormKey)
+	password := r.FormValue(passwordFormKey)
 
 	// authenticate the user
 	userID, err := user.Authenticate(username, password)
@@ -39,16 +48,69 @@ func handleLogin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	newSession.Values[userIDKey] = userID
-	newSession.Options.MaxAge = maxCookieAge
+	var token string
+	err = database.WithTransaction(r.Context(), func(txn database.Transaction) error {
+		userUUID, _ := uuid.FromString(userID)
+		if err := user.ValidateLoginSuspension(txn.GetTx(), userUUID); err != nil {
+			return err
+		}
+
+		if err := user.ValidateLoginTOTP(txn.GetTx(), userUUID, r.FormValue(totpFormKey)); err != nil {
+			return err
+		}
+
+		var txnErr error
+		token, txnErr = createLoginSession(txn.GetTx(), r, newSession, userUUID)
+		return txnErr
+	})
 
-	err = newSession.Save(r, w)
+	if err == user.ErrTOTPRequired || err == user.ErrInvalidTOTP {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	} else if err == user.ErrTOTPLocked {
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+		return
+	} else if user.IsSuspendedError(err) {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	err = saveLoginSession(w, r, newSession, token)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 }
 
+// createLoginSession creates a login session for the user, replacing the
+// previous session of the client, if any. It returns the session token.
+func createLoginSession(tx *sqlx.Tx, r *http.Request, cookie *sessions.Session, userID uuid.UUID) (string, error) {
+	if oldToken, _ := cookie.Values[sessionTokenKey].(string); oldToken != "" {
+		if err := user.DestroySession(tx, oldToken); err != nil {
+			return "", err
+		}
+	}
+
+	info := user.SessionInfo{
+		Device:    r.FormValue(deviceFormKey),
+		IP:        getRequestIP(r),
+		UserAgent: r.UserAgent(),
+	}
+
+	return user.CreateSession(tx, userID, info)
+}
+
+// saveLoginSession stores the session token in the session cookie.
+func saveLoginSession(w http.ResponseWriter, r *http.Request, cookie *sessions.Session, token string) error {
+	cookie.Values[sessionTokenKey] = token
+	cookie.Options.MaxAge = getCookieMaxAge()
+
+	return cookie.Save(r, w)
+}
+
 func handleLogout(w http.ResponseWriter, r *http.Request) {
 	session, err := sessionStore.Get(r, cookieName)
 	if err != nil {
@@ -56,7 +118,18 @@ func handleLogout(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	delete(session.Values, userIDKey)
+	if token, _ := session.Values[sessionTokenKey].(string); token != "" {
+		err = database.WithTransaction(r.Context(), func(txn database.Transaction) error {
+			return user.DestroySession(txn.GetTx(), token)
+		})
+
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	delete(session.Values, sessionTokenKey)
 	session.Options.MaxAge = -1
 
 	err = session.Save(r, w)
@@ -66,24 +139,49 @@ func handleLogout(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-func getSessionUserID(w http.ResponseWriter, r *http.Request) (string, error) {
+// getSession returns the login session of the request, or nil if there is
+// none. The session cookie is refreshed, or removed if the session has
+// expired or been revoked.
+func getSession(w http.ResponseWriter, r *http.Request) (*models.UserSession, error) {
 	session, err := sessionStore.Get(r, cookieName)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	if !session.IsNew {
-		userIDInt := session.Values[userIDKey]
-		userID, _ := userIDInt.(string)
+	if session.IsNew {
+		return nil, nil
+	}
 
-		// refresh the cookie
-		err = session.Save(r, w)
-		if err != nil {
-			return "", err
-		}
+	token, _ := session.Values[sessionTokenKey].(string)
+	if token == "" {
+		return nil, nil
+	}
+
+	userSession, err := user.GetSession(token, getRequestIP(r))
+	if err != nil {
+		return nil, err
+	}
+
+	if userSession == nil {
+		delete(session.Values, sessionTokenKey)
+		session.Options.MaxAge = -1
+	} else {
+		session.Options.MaxAge = getCookieMaxAge()
+	}
 
-		return userID, nil
+	// refresh the cookie
+	if err := session.Save(r, w); err != nil {
+		return nil, err
+	}
+
+	return userSession, nil
+}
+
+func getSessionUserID(w http.ResponseWriter, r *http.Request) (string, error) {
+	session, err := getSession(w, r)
+	if err != nil || session == nil {
+		return "", err
 	}
 
-	return "", nil
+	return session.UserID.String(), nil
 }
@@ -0,0 +1,299 @@
+// +build integration
+
+package api_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stashapp/stash-box/pkg/api"
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+type siteTestRunner struct {
+	testRunner
+}
+
+func createSiteTestRunner(t *testing.T) *siteTestRunner {
+	return &siteTestRunner{
+		testRunner: *asAdmin(t),
+	}
+}
+
+func (s *siteTestRunner) testCreateSite() {
+	regex := `^https://example\.org/`
+	icon := "https://example.org/favicon.ico"
+
+	input := models.SiteCreateInput{
+		Name:       s.generateSiteName(),
+		Regex:      &regex,
+		Icon:       &icon,
+		ValidTypes: []models.ValidSiteTypeEnum{models.ValidSiteTypeEnumPerformer, models.ValidSiteTypeEnumScene},
+	}
+
+	site, err := s.createTestSite(&input)
+	if err != nil {
+		return
+	}
+
+	if input.Name != site.Name {
+		s.fieldMismatch(input.Name, site.Name, "Name")
+	}
+
+	r := s.resolver.Site()
+
+	if v, _ := r.Regex(s.ctx, site); !reflect.DeepEqual(v, input.Regex) {
+		s.fieldMismatch(*input.Regex, v, "Regex")
+	}
+	if v, _ := r.Icon(s.ctx, site); !reflect.DeepEqual(v, input.Icon) {
+		s.fieldMismatch(*input.Icon, v, "Icon")
+	}
+	if v, _ := r.ValidTypes(s.ctx, site); !reflect.DeepEqual(v, input.ValidTypes) {
+		s.fieldMismatch(input.ValidTypes, v, "ValidTypes")
+	}
+}
+
+func (s *siteTestRunner) testCreateSiteInvalidRegex() {
+	regex := "("
+	input := models.SiteCreateInput{
+		Name:       s.generateSiteName(),
+		Regex:      &regex,
+		ValidTypes: []models.ValidSiteTypeEnum{models.ValidSiteTypeEnumPerformer},
+	}
+
+	_, err := s.resolver.Mutation().SiteCreate(s.ctx, input)
+	if err == nil {
+		s.t.Error("Expected error creating site with invalid regex")
+	}
+}
+
+func (s *siteTestRunner) testUpdateSite() {
+	site, err := s.createTestSite(nil)
+	if err != nil {
+		return
+	}
+
+	newName := s.generateSiteName()
+	input := models.SiteUpdateInput{
+		ID:         site.ID.String(),
+		Name:       &newName,
+		ValidTypes: []models.ValidSiteTypeEnum{models.ValidSiteTypeEnumStudio},
+	}
+
+	updated, err := s.resolver.Mutation().SiteUpdate(s.ctx, input)
+	if err != nil {
+		s.t.Errorf("Error updating site: %s", err.Error())
+		return
+	}
+
+	if updated.Name != newName {
+		s.fieldMismatch(newName, updated.Name, "Name")
+	}
+
+	if !updated.IsValidFor(models.ValidSiteTypeEnumStudio) || updated.IsValidFor(models.ValidSiteTypeEnumPerformer) {
+		s.fieldMismatch(input.ValidTypes, updated.ValidTypes, "ValidTypes")
+	}
+}
+
+func (s *siteTestRunner) testQuerySites() {
+	site, err := s.createTestSite(nil)
+	if err != nil {
+		return
+	}
+
+	name := `"` + site.Name + `"`
+	validType := models.ValidSiteTypeEnumPerformer
+	result, err := s.resolver.Query().QuerySites(s.ctx, &models.SiteFilterType{
+		Name:      &name,
+		ValidType: &validType,
+	}, nil)
+	if err != nil {
+		s.t.Errorf("Error querying sites: %s", err.Error())
+		return
+	}
+
+	if result.Count != 1 || result.Sites[0].ID != site.ID {
+		s.t.Errorf("Expected to find site %s, got %d sites", site.Name, result.Count)
+	}
+
+	validType = models.ValidSiteTypeEnumGroup
+	result, err = s.resolver.Query().QuerySites(s.ctx, &models.SiteFilterType{
+		Name:      &name,
+		ValidType: &validType,
+	}, nil)
+	if err != nil {
+		s.t.Errorf("Error querying sites: %s", err.Error())
+		return
+	}
+
+	if result.Count != 0 {
+		s.t.Errorf("Expected no sites valid for groups, got %d", result.Count)
+	}
+}
+
+func (s *siteTestRunner) testEditURLValidation() {
+	regex := `^https://example\.org/`
+	site, err := s.createTestSite(&models.SiteCreateInput{
+		Name:       s.generateSiteName(),
+		Regex:      &regex,
+		ValidTypes: []models.ValidSiteTypeEnum{models.ValidSiteTypeEnumPerformer},
+	})
+	if err != nil {
+		return
+	}
+
+	// type is matched to the site ignoring case
+	name := s.generatePerformerName()
+	edit, err := s.createTestPerformerEdit(models.OperationEnumCreate, &models.PerformerEditDetailsInput{
+		Name: &name,
+		Urls: []*models.URL{
+			{
+				URL:  "https://example.org/" + name,
+				Type: strings.ToUpper(site.Name),
+			},
+		},
+	}, nil, nil)
+	if err != nil {
+		return
+	}
+
+	urls := s.getEditPerformerDetails(edit).AddedUrls
+	if len(urls) != 1 || urls[0].SiteID == nil || *urls[0].SiteID != site.ID.String() || urls[0].Type != site.Name {
+		s.t.Errorf("Expected url to be linked to site %s", site.Name)
+	}
+
+	// urls not matching the site pattern are rejected
+	siteID := site.ID.String()
+	name = s.generatePerformerName()
+	_, err = s.resolver.Mutation().PerformerEdit(s.ctx, models.PerformerEditInput{
+		Edit: &models.EditInput{
+			Operation: models.OperationEnumCreate,
+		},
+		Details: &models.PerformerEditDetailsInput{
+			Name: &name,
+			Urls: []*models.URL{
+				{
+					URL:    "https://example.com/" + name,
+					SiteID: &siteID,
+				},
+			},
+		},
+	})
+	if err == nil {
+		s.t.Error("Expected error for url not matching site pattern")
+	}
+
+	// sites are only valid for their entity types
+	_, err = s.resolver.Mutation().GroupEdit(s.ctx, models.GroupEditInput{
+		Edit: &models.EditInput{
+			Operation: models.OperationEnumCreate,
+		},
+		Details: &models.GroupEditDetailsInput{
+			Title: &name,
+			Urls: []*models.URL{
+				{
+					URL:    "https://example.org/" + name,
+					SiteID: &siteID,
+				},
+			},
+		},
+	})
+	if err == nil {
+		s.t.Error("Expected error for site not valid for groups")
+	}
+}
+
+func (s *siteTestRunner) testURLSiteResolver() {
+	site, err := s.createTestSite(nil)
+	if err != nil {
+		return
+	}
+
+	siteID := site.ID.String()
+	input := models.PerformerCreateInput{
+		Name: s.generatePerformerName(),
+		Urls: []*models.URLInput{
+			{
+				URL:    "https://example.org/performer",
+				SiteID: &siteID,
+			},
+		},
+	}
+	performer, err := s.createTestPerformer(&input)
+	if err != nil {
+		return
+	}
+
+	urls, err := s.resolver.Performer().Urls(s.ctx, performer)
+	if err != nil {
+		s.t.Errorf("Error getting performer urls: %s", err.Error())
+		return
+	}
+
+	if len(urls) != 1 {
+		s.t.Errorf("Expected 1 url, got %d", len(urls))
+		return
+	}
+
+	if urls[0].Type != site.Name {
+		s.fieldMismatch(site.Name, urls[0].Type, "Type")
+	}
+
+	urlSite, err := s.resolver.URL().Site(s.ctx, urls[0])
+	if err != nil {
+		s.t.Errorf("Error getting url site: %s", err.Error())
+		return
+	}
+
+	if urlSite == nil || urlSite.ID != site.ID {
+		s.t.Errorf("Expected url site %s", site.Name)
+	}
+}
+
+func (s *siteTestRunner) testUnauthorisedSiteModify() {
+	_, err := s.resolver.Mutation().SiteCreate(s.ctx, models.SiteCreateInput{
+		Name:       s.generateSiteName(),
+		ValidTypes: []models.ValidSiteTypeEnum{models.ValidSiteTypeEnumPerformer},
+	})
+	if err != api.ErrUnauthorized {
+		s.t.Errorf("SiteCreate: got %v want %v", err, api.ErrUnauthorized)
+	}
+}
+
+func TestCreateSite(t *testing.T) {
+	pt := createSiteTestRunner(t)
+	pt.testCreateSite()
+}
+
+func TestCreateSiteInvalidRegex(t *testing.T) {
+	pt := createSiteTestRunner(t)
+	pt.testCreateSiteInvalidRegex()
+}
+
+func TestUpdateSite(t *testing.T) {
+	pt := createSiteTestRunner(t)
+	pt.testUpdateSite()
+}
+
+func TestQuerySites(t *testing.T) {
+	pt := createSiteTestRunner(t)
+	pt.testQuerySites()
+}
+
+func TestEditURLValidation(t *testing.T) {
+	pt := createSiteTestRunner(t)
+	pt.testEditURLValidation()
+}
+
+func TestURLSiteResolver(t *testing.T) {
+	pt := createSiteTestRunner(t)
+	pt.testURLSiteResolver()
+}
+
+func TestUnauthorisedSiteModify(t *testing.T) {
+	pt := &siteTestRunner{
+		testRunner: *asEdit(t),
+	}
+	pt.testUnauthorisedSiteModify()
+}
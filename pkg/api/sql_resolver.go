@@ -3,6 +3,7 @@ package api
 import (
 	"database/sql"
 	"reflect"
+	"time"
 
 	"github.com/stashapp/stash-box/pkg/models"
 	"github.com/stashapp/stash-box/pkg/utils"
@@ -66,3 +67,10 @@ func resolveNullInt64(value sql.NullInt64) (*int, error) {
 	}
 	return nil, nil
 }
+
+func resolveNullTimestamp(value models.NullSQLiteTimestamp) *time.Time {
+	if value.Valid {
+		return &value.Timestamp
+	}
+	return nil
+}
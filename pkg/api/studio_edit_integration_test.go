@@ -0,0 +1,150 @@
+// +build integration
+
+package api_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+type studioEditTestRunner struct {
+	testRunner
+}
+
+func createStudioEditTestRunner(t *testing.T) *studioEditTestRunner {
+	return &studioEditTestRunner{
+		testRunner: *asAdmin(t),
+	}
+}
+
+func (s *studioEditTestRunner) testCreateStudioEdit() {
+	parent, err := s.createTestStudio(nil)
+	if err != nil {
+		return
+	}
+	parentID := parent.ID.String()
+	name := s.generateStudioName()
+	studioEditDetailsInput := models.StudioEditDetailsInput{
+		Name:     &name,
+		Aliases:  []string{name + " Alias"},
+		ParentID: &parentID,
+	}
+	edit, err := s.createTestStudioEdit(models.OperationEnumCreate, &studioEditDetailsInput, nil)
+	if err != nil {
+		return
+	}
+
+	s.verifyEditOperation(models.OperationEnumCreate.String(), edit)
+	s.verifyEditStatus(models.VoteStatusEnumPending.String(), edit)
+	s.verifyEditTargetType(models.TargetTypeEnumStudio.String(), edit)
+	s.verifyEditApplication(false, edit)
+
+	details, _ := s.resolver.Edit().Details(s.ctx, edit)
+	studioDetails := details.(*models.StudioEdit)
+	if *studioDetails.Name != name {
+		s.fieldMismatch(name, *studioDetails.Name, "Name")
+	}
+	if !reflect.DeepEqual(studioDetails.AddedAliases, studioEditDetailsInput.Aliases) {
+		s.fieldMismatch(studioEditDetailsInput.Aliases, studioDetails.AddedAliases, "AddedAliases")
+	}
+
+	appliedEdit, err := s.applyEdit(edit.ID.String())
+	if err != nil {
+		return
+	}
+
+	target, _ := s.resolver.Edit().Target(s.ctx, appliedEdit)
+	studio := target.(*models.Studio)
+	if studio.Name != name {
+		s.fieldMismatch(name, studio.Name, "Name")
+	}
+	if studio.ParentStudioID.UUID != parent.ID {
+		s.fieldMismatch(parent.ID, studio.ParentStudioID.UUID, "ParentStudioID")
+	}
+
+	aliases, _ := s.resolver.Studio().Aliases(s.ctx, studio)
+	if !reflect.DeepEqual(aliases, studioEditDetailsInput.Aliases) {
+		s.fieldMismatch(studioEditDetailsInput.Aliases, aliases, "Aliases")
+	}
+}
+
+func (s *studioEditTestRunner) testModifyStudioEditAliases() {
+	createdStudio, err := s.createTestStudio(&models.StudioCreateInput{
+		Name:    s.generateStudioName(),
+		Aliases: []string{"Old Studio Alias"},
+	})
+	if err != nil {
+		return
+	}
+
+	id := createdStudio.ID.String()
+	newAliases := []string{"New Studio Alias"}
+	edit, err := s.createTestStudioEdit(models.OperationEnumModify, &models.StudioEditDetailsInput{
+		Aliases: newAliases,
+	}, &models.EditInput{
+		Operation: models.OperationEnumModify,
+		ID:        &id,
+	})
+	if err != nil {
+		return
+	}
+
+	details, _ := s.resolver.Edit().Details(s.ctx, edit)
+	studioDetails := details.(*models.StudioEdit)
+	if !reflect.DeepEqual(studioDetails.AddedAliases, newAliases) {
+		s.fieldMismatch(newAliases, studioDetails.AddedAliases, "AddedAliases")
+	}
+	if !reflect.DeepEqual(studioDetails.RemovedAliases, []string{"Old Studio Alias"}) {
+		s.fieldMismatch([]string{"Old Studio Alias"}, studioDetails.RemovedAliases, "RemovedAliases")
+	}
+
+	if _, err := s.applyEdit(edit.ID.String()); err != nil {
+		return
+	}
+
+	studio, _ := s.resolver.Query().FindStudio(s.ctx, &id, nil)
+	aliases, _ := s.resolver.Studio().Aliases(s.ctx, studio)
+	if !reflect.DeepEqual(aliases, newAliases) {
+		s.fieldMismatch(newAliases, aliases, "Aliases")
+	}
+}
+
+func (s *studioEditTestRunner) testStudioEditConflictingAlias() {
+	existing, err := s.createTestStudio(nil)
+	if err != nil {
+		return
+	}
+
+	name := s.generateStudioName()
+	input := models.StudioEditInput{
+		Edit: &models.EditInput{
+			Operation: models.OperationEnumCreate,
+		},
+		Details: &models.StudioEditDetailsInput{
+			Name:    &name,
+			Aliases: []string{existing.Name},
+		},
+	}
+
+	_, err = s.resolver.Mutation().StudioEdit(s.ctx, input)
+	if err == nil {
+		s.t.Errorf("StudioEdit: expected error for alias matching the name of another studio")
+	}
+}
+
+func TestCreateStudioEdit(t *testing.T) {
+	pt := createStudioEditTestRunner(t)
+	pt.testCreateStudioEdit()
+}
+
+func TestModifyStudioEditAliases(t *testing.T) {
+	pt := createStudioEditTestRunner(t)
+	pt.testModifyStudioEditAliases()
+}
+
+func TestStudioEditConflictingAlias(t *testing.T) {
+	pt := createStudioEditTestRunner(t)
+	pt.testStudioEditConflictingAlias()
+}
@@ -3,10 +3,14 @@
 package api_test
 
 import (
+	"context"
+	"reflect"
 	"strconv"
 	"testing"
 
 	"github.com/stashapp/stash-box/pkg/api"
+	"github.com/stashapp/stash-box/pkg/database"
+	"github.com/stashapp/stash-box/pkg/dataloader"
 	"github.com/stashapp/stash-box/pkg/models"
 )
 
@@ -179,6 +183,188 @@ func (s *studioTestRunner) testDestroyStudio() {
 	// TODO - ensure scene was not removed
 }
 
+func (s *studioTestRunner) createChildStudio(parent *models.Studio) *models.Studio {
+	s.t.Helper()
+	parentID := parent.ID.String()
+	studio, _ := s.createTestStudio(&models.StudioCreateInput{
+		Name:     s.generateStudioName(),
+		ParentID: &parentID,
+	})
+	return studio
+}
+
+func (s *studioTestRunner) testStudioHierarchy() {
+	network, err := s.createTestStudio(nil)
+	if err != nil {
+		return
+	}
+	site := s.createChildStudio(network)
+	if site == nil {
+		return
+	}
+	subsite := s.createChildStudio(site)
+	if subsite == nil {
+		return
+	}
+
+	var sceneIDs []string
+	for _, studio := range []*models.Studio{network, site, subsite} {
+		studioID := studio.ID.String()
+		title := "title"
+		scene, err := s.createTestScene(&models.SceneCreateInput{
+			Title:    &title,
+			StudioID: &studioID,
+			Fingerprints: []*models.FingerprintInput{
+				s.generateSceneFingerprint(),
+			},
+		})
+		if err != nil {
+			return
+		}
+		sceneIDs = append(sceneIDs, scene.ID.String())
+	}
+
+	r := s.resolver.Studio()
+
+	descendants, err := r.Descendants(s.ctx, network)
+	if err != nil {
+		s.t.Errorf("Error getting descendants: %s", err.Error())
+		return
+	}
+	if len(descendants) != 2 {
+		s.t.Errorf("Expected 2 descendants, got %d", len(descendants))
+	}
+
+	ancestors, err := r.Ancestors(s.ctx, subsite)
+	if err != nil {
+		s.t.Errorf("Error getting ancestors: %s", err.Error())
+		return
+	}
+	if len(ancestors) != 2 || ancestors[0].ID != site.ID || ancestors[1].ID != network.ID {
+		s.t.Errorf("Expected ancestors %s, %s; got %v", site.ID, network.ID, ancestors)
+	}
+
+	includeDescendants := true
+	count, err := r.SceneCount(s.ctx, network, &includeDescendants)
+	if err != nil {
+		s.t.Errorf("Error getting scene count: %s", err.Error())
+		return
+	}
+	if count != 3 {
+		s.fieldMismatch(3, count, "SceneCount")
+	}
+
+	count, err = r.SceneCount(s.ctx, network, nil)
+	if err != nil {
+		s.t.Errorf("Error getting scene count: %s", err.Error())
+		return
+	}
+	if count != 1 {
+		s.fieldMismatch(1, count, "SceneCount")
+	}
+
+	siteID := site.ID.String()
+	filter := models.SceneFilterType{
+		Network: &siteID,
+	}
+	results, err := s.resolver.Query().QueryScenes(s.ctx, &filter, nil)
+	if err != nil {
+		s.t.Errorf("Error querying scenes: %s", err.Error())
+		return
+	}
+	if results.Count != 2 {
+		s.t.Errorf("Expected 2 network scenes, got %d", results.Count)
+	}
+	for _, scene := range results.Scenes {
+		if scene.ID.String() == sceneIDs[0] {
+			s.t.Errorf("Network filter returned scene of parent studio")
+		}
+	}
+
+	// deleted studios are excluded from the hierarchy
+	err = database.WithTransaction(s.ctx, func(txn database.Transaction) error {
+		qb := models.NewStudioQueryBuilder(txn.GetTx())
+		_, err := qb.SoftDelete(*subsite)
+		return err
+	})
+	if err != nil {
+		s.t.Errorf("Error deleting studio: %s", err.Error())
+		return
+	}
+
+	descendants, _ = r.Descendants(s.ctx, network)
+	if len(descendants) != 1 || descendants[0].ID != site.ID {
+		s.t.Errorf("Expected descendant %s, got %v", site.ID, descendants)
+	}
+
+	// fresh loaders, since the count of the network is cached
+	ctx := context.WithValue(s.ctx, dataloader.GetLoadersKey(), dataloader.GetLoaders())
+	count, _ = r.SceneCount(ctx, network, &includeDescendants)
+	if count != 2 {
+		s.fieldMismatch(2, count, "SceneCount")
+	}
+
+	results, _ = s.resolver.Query().QueryScenes(s.ctx, &filter, nil)
+	if results == nil || results.Count != 1 {
+		s.t.Errorf("Expected 1 network scene after deleting studio, got %v", results)
+	}
+}
+
+func (s *studioTestRunner) testStudioAliases() {
+	name := s.generateStudioName()
+	alias := name + "-formerly"
+	createdStudio, err := s.createTestStudio(&models.StudioCreateInput{
+		Name:    name,
+		Aliases: []string{alias},
+	})
+	if err != nil {
+		return
+	}
+
+	aliases, _ := s.resolver.Studio().Aliases(s.ctx, createdStudio)
+	if !reflect.DeepEqual(aliases, []string{alias}) {
+		s.fieldMismatch([]string{alias}, aliases, "Aliases")
+	}
+
+	// aliases may not match the name of another studio
+	_, err = s.resolver.Mutation().StudioCreate(s.ctx, models.StudioCreateInput{
+		Name:    s.generateStudioName(),
+		Aliases: []string{name},
+	})
+	if err == nil {
+		s.t.Errorf("StudioCreate: expected error for alias matching the name of another studio")
+	}
+
+	// names may not match the alias of another studio
+	_, err = s.resolver.Mutation().StudioCreate(s.ctx, models.StudioCreateInput{
+		Name: alias,
+	})
+	if err == nil {
+		s.t.Errorf("StudioCreate: expected error for name matching the alias of another studio")
+	}
+
+	quotedAlias := "\"" + alias + "\""
+	results, err := s.resolver.Query().QueryStudios(s.ctx, &models.StudioFilterType{
+		Names: &quotedAlias,
+	}, nil)
+	if err != nil {
+		s.t.Errorf("Error querying studios: %s", err.Error())
+		return
+	}
+	if results.Count != 1 || results.Studios[0].ID != createdStudio.ID {
+		s.t.Errorf("Expected studio %s from names filter, got %v", createdStudio.ID, results.Studios)
+	}
+
+	studios, err := s.resolver.Query().SearchStudio(s.ctx, alias, nil)
+	if err != nil {
+		s.t.Errorf("Error searching studios: %s", err.Error())
+		return
+	}
+	if len(studios) == 0 || studios[0].ID != createdStudio.ID {
+		s.t.Errorf("Expected studio %s from alias search, got %v", createdStudio.ID, studios)
+	}
+}
+
 func (s *studioTestRunner) testUnauthorisedStudioModify() {
 	// test each api interface - all require modify so all should fail
 	_, err := s.resolver.Mutation().StudioCreate(s.ctx, models.StudioCreateInput{})
@@ -235,7 +421,15 @@ func TestDestroyStudio(t *testing.T) {
 	pt.testDestroyStudio()
 }
 
-// TODO - test parent/children studios
+func TestStudioHierarchy(t *testing.T) {
+	pt := createStudioTestRunner(t)
+	pt.testStudioHierarchy()
+}
+
+func TestStudioAliases(t *testing.T) {
+	pt := createStudioTestRunner(t)
+	pt.testStudioAliases()
+}
 
 func TestUnauthorisedStudioModify(t *testing.T) {
 	pt := &studioTestRunner{
@@ -185,6 +185,98 @@ func (s *tagTestRunner) testUnauthorisedTagCategoryQuery() {
 	}
 }
 
+func (s *tagCategoryTestRunner) testSceneTagCategories() {
+	peopleCategory, err := s.createTestTagCategory(&models.TagCategoryCreateInput{
+		Name:  s.generateCategoryName(),
+		Group: models.TagGroupEnumPeople,
+	})
+	if err != nil {
+		return
+	}
+	actionCategory, err := s.createTestTagCategory(nil)
+	if err != nil {
+		return
+	}
+
+	peopleCategoryID := peopleCategory.ID.String()
+	actionCategoryID := actionCategory.ID.String()
+	peopleTag, err := s.createTestTag(&models.TagCreateInput{
+		Name:       s.generateTagName(),
+		CategoryID: &peopleCategoryID,
+	})
+	if err != nil {
+		return
+	}
+	actionTag, err := s.createTestTag(&models.TagCreateInput{
+		Name:       s.generateTagName(),
+		CategoryID: &actionCategoryID,
+	})
+	if err != nil {
+		return
+	}
+
+	title := "title"
+	scene, err := s.createTestScene(&models.SceneCreateInput{
+		Title:  &title,
+		TagIds: []string{peopleTag.ID.String(), actionTag.ID.String()},
+		Fingerprints: []*models.FingerprintInput{
+			s.generateSceneFingerprint(),
+		},
+	})
+	if err != nil {
+		return
+	}
+
+	r := s.resolver.Scene()
+	tags, _ := r.Tags(s.ctx, scene, &peopleCategoryID, nil)
+	if len(tags) != 1 || tags[0].ID != peopleTag.ID {
+		s.fieldMismatch(peopleTag.ID, tags, "Tags by category")
+	}
+	group := models.TagGroupEnumAction
+	tags, _ = r.Tags(s.ctx, scene, nil, &group)
+	if len(tags) != 1 || tags[0].ID != actionTag.ID {
+		s.fieldMismatch(actionTag.ID, tags, "Tags by group")
+	}
+
+	page := 1
+	pageSize := 10
+	querySpec := models.QuerySpec{
+		Page:    &page,
+		PerPage: &pageSize,
+	}
+	results, err := s.resolver.Query().QueryScenes(s.ctx, &models.SceneFilterType{
+		TagCategory: &models.MultiIDCriterionInput{
+			Value:    []string{peopleCategoryID, actionCategoryID},
+			Modifier: models.CriterionModifierIncludesAll,
+		},
+	}, &querySpec)
+	if err != nil {
+		s.t.Errorf("Error querying scenes: %s", err.Error())
+		return
+	}
+	if results.Count != 1 || results.Scenes[0].ID != scene.ID {
+		s.t.Errorf("Expected scene %s with tag categories, got %v", scene.ID, results.Scenes)
+	}
+
+	usage, err := s.resolver.Query().TagCategoryUsage(s.ctx, nil)
+	if err != nil {
+		s.t.Errorf("Error getting tag category usage: %s", err.Error())
+		return
+	}
+	found := false
+	for _, u := range usage {
+		if u.CategoryID == peopleCategory.ID {
+			found = true
+			if u.TagCount != 1 || u.SceneCount != 1 {
+				s.t.Errorf("Expected 1 tag and 1 scene in category, got %d and %d", u.TagCount, u.SceneCount)
+			}
+		}
+	}
+	if !found {
+		s.t.Errorf("Missing usage for tag category %s", peopleCategory.ID)
+	}
+}
+
 func TestCreateTagCategory(t *testing.T) {
 	pt := createTagCategoryTestRunner(t)
 	pt.testCreateTagCategory()
@@ -213,3 +305,8 @@ func TestUnauthorisedTagCategoryQuery(t *testing.T) {
 	}
 	pt.testUnauthorisedTagCategoryQuery()
 }
+
+func TestSceneTagCategories(t *testing.T) {
+	pt := createTagCategoryTestRunner(t)
+	pt.testSceneTagCategories()
+}
@@ -367,7 +367,7 @@ func (s *tagEditTestRunner) testApplyModifyTagEdit() {
 		return
 	}
 
-	modifiedTag, _ := s.resolver.Query().FindTag(s.ctx, &id, nil)
+	modifiedTag, _ := s.resolver.Query().FindTag(s.ctx, &id, nil, nil)
 	s.verifyApplyModifyTagEdit(tagEditDetailsInput, modifiedTag, appliedEdit)
 }
 
@@ -420,7 +420,7 @@ func (s *tagEditTestRunner) testApplyDestroyTagEdit() {
 	}
 	appliedEdit, err := s.applyEdit(destroyEdit.ID.String())
 
-	destroyedTag, _ := s.resolver.Query().FindTag(s.ctx, &tagID, nil)
+	destroyedTag, _ := s.resolver.Query().FindTag(s.ctx, &tagID, nil, nil)
 	s.verifyApplyDestroyTagEdit(destroyedTag, appliedEdit, scene)
 }
 
@@ -434,7 +434,7 @@ func (s *tagEditTestRunner) verifyApplyDestroyTagEdit(destroyedTag *models.Tag,
 		s.fieldMismatch(destroyedTag.Deleted, true, "Deleted")
 	}
 
-	sceneTags, _ := s.resolver.Scene().Tags(s.ctx, scene)
+	sceneTags, _ := s.resolver.Scene().Tags(s.ctx, scene, nil, nil)
 	if len(sceneTags) > 0 {
 		s.fieldMismatch(len(sceneTags), 0, "Scene tag count")
 	}
@@ -528,7 +528,7 @@ func (s *tagEditTestRunner) verifyAppliedMergeTagEdit(input models.TagEditDetail
 	}
 
 	editTarget := s.getEditTagTarget(edit)
-	scene1Tags, _ := s.resolver.Scene().Tags(s.ctx, scene1)
+	scene1Tags, _ := s.resolver.Scene().Tags(s.ctx, scene1, nil, nil)
 	if len(scene1Tags) > 1 {
 		s.fieldMismatch(len(scene1Tags), 1, "Scene 1 tag count")
 	}
@@ -536,7 +536,7 @@ func (s *tagEditTestRunner) verifyAppliedMergeTagEdit(input models.TagEditDetail
 		s.fieldMismatch(scene1Tags[0].ID, editTarget.ID, "Scene 1 tag ID")
 	}
 
-	scene2Tags, _ := s.resolver.Scene().Tags(s.ctx, scene2)
+	scene2Tags, _ := s.resolver.Scene().Tags(s.ctx, scene2, nil, nil)
 	if len(scene2Tags) > 1 {
 		s.fieldMismatch(len(scene2Tags), 1, "Scene 2 tag count")
 	}
@@ -545,6 +545,90 @@ func (s *tagEditTestRunner) verifyAppliedMergeTagEdit(input models.TagEditDetail
 	}
 }
 
+func (s *tagEditTestRunner) testTagHierarchy() {
+	parent, err := s.createTestTag(nil)
+	if err != nil {
+		return
+	}
+	parentID := parent.ID.String()
+
+	name := s.generateTagName()
+	createdEdit, err := s.createTestTagEdit(models.OperationEnumCreate, &models.TagEditDetailsInput{
+		Name:      &name,
+		ParentIds: []string{parentID},
+	}, nil)
+	if err != nil {
+		return
+	}
+	appliedEdit, err := s.applyEdit(createdEdit.ID.String())
+	if err != nil {
+		return
+	}
+	child := s.getEditTagTarget(appliedEdit)
+
+	r := s.resolver.Tag()
+	parents, _ := r.Parents(s.ctx, child)
+	if len(parents) != 1 || parents[0].ID != parent.ID {
+		s.fieldMismatch(parentID, parents, "Parents")
+	}
+	children, _ := r.Children(s.ctx, parent)
+	if len(children) != 1 || children[0].ID != child.ID {
+		s.fieldMismatch(child.ID.String(), children, "Children")
+	}
+
+	// making the parent a child of its child must fail
+	editID := parentID
+	_, err = s.resolver.Mutation().TagEdit(s.ctx, models.TagEditInput{
+		Edit: &models.EditInput{
+			ID:        &editID,
+			Operation: models.OperationEnumModify,
+		},
+		Details: &models.TagEditDetailsInput{
+			ParentIds: []string{child.ID.String()},
+		},
+	})
+	if err == nil {
+		s.t.Error("Expected error creating cyclic tag hierarchy")
+	}
+
+	childID := child.ID.String()
+	title := "title"
+	scene, err := s.createTestScene(&models.SceneCreateInput{
+		Title:  &title,
+		TagIds: []string{childID},
+		Fingerprints: []*models.FingerprintInput{
+			s.generateSceneFingerprint(),
+		},
+	})
+	if err != nil {
+		return
+	}
+
+	impliedTags, _ := s.resolver.Scene().ImpliedTags(s.ctx, scene)
+	if len(impliedTags) != 1 || impliedTags[0].ID != parent.ID {
+		s.fieldMismatch(parentID, impliedTags, "ImpliedTags")
+	}
+
+	page := 1
+	pageSize := 10
+	results, err := s.resolver.Query().QueryScenes(s.ctx, &models.SceneFilterType{
+		Tags: &models.MultiIDCriterionInput{
+			Value:    []string{parentID},
+			Modifier: models.CriterionModifierIncludesChildren,
+		},
+	}, &models.QuerySpec{
+		Page:    &page,
+		PerPage: &pageSize,
+	})
+	if err != nil {
+		s.t.Errorf("Error querying scenes: %s", err.Error())
+		return
+	}
+	if results.Count != 1 || results.Scenes[0].ID != scene.ID {
+		s.t.Errorf("Expected scene %s when including child tags, got %v", scene.ID, results.Scenes)
+	}
+}
+
 func TestCreateTagEdit(t *testing.T) {
 	pt := createTagEditTestRunner(t)
 	pt.testCreateTagEdit()
@@ -584,3 +668,8 @@ func TestApplyMergeTagEdit(t *testing.T) {
 	pt := createTagEditTestRunner(t)
 	pt.testApplyMergeTagEdit()
 }
+
+func TestTagHierarchy(t *testing.T) {
+	pt := createTagEditTestRunner(t)
+	pt.testTagHierarchy()
+}
@@ -63,7 +63,7 @@ func (s *tagTestRunner) testFindTagById() {
 	}
 
 	tagID := createdTag.ID.String()
-	tag, err := s.resolver.Query().FindTag(s.ctx, &tagID, nil)
+	tag, err := s.resolver.Query().FindTag(s.ctx, &tagID, nil, nil)
 	if err != nil {
 		s.t.Errorf("Error finding tag: %s", err.Error())
 		return
@@ -89,7 +89,7 @@ func (s *tagTestRunner) testFindTagByName() {
 
 	tagName := createdTag.Name
 
-	tag, err := s.resolver.Query().FindTag(s.ctx, nil, &tagName)
+	tag, err := s.resolver.Query().FindTag(s.ctx, nil, &tagName, nil)
 	if err != nil {
 		s.t.Errorf("Error finding tag: %s", err.Error())
 		return
@@ -167,7 +167,7 @@ func (s *tagTestRunner) testDestroyTag() {
 	}
 
 	// ensure cannot find tag
-	foundTag, err := s.resolver.Query().FindTag(s.ctx, &tagID, nil)
+	foundTag, err := s.resolver.Query().FindTag(s.ctx, &tagID, nil, nil)
 	if err != nil {
 		s.t.Errorf("Error finding tag after destroying: %s", err.Error())
 		return
@@ -200,7 +200,7 @@ func (s *tagTestRunner) testUnauthorisedTagModify() {
 
 func (s *tagTestRunner) testUnauthorisedTagQuery() {
 	// test each api interface - all require read so all should fail
-	_, err := s.resolver.Query().FindTag(s.ctx, nil, nil)
+	_, err := s.resolver.Query().FindTag(s.ctx, nil, nil, nil)
 	if err != api.ErrUnauthorized {
 		s.t.Errorf("FindTag: got %v want %v", err, api.ErrUnauthorized)
 	}
@@ -211,6 +211,73 @@ func (s *tagTestRunner) testUnauthorisedTagQuery() {
 	}
 }
 
+func (s *tagTestRunner) testTagSceneCount() {
+	prefix := s.generateTagName()
+	usedTag, err := s.createTestTag(&models.TagCreateInput{
+		Name: prefix + "-used",
+	})
+	if err != nil {
+		return
+	}
+	unusedTag, err := s.createTestTag(&models.TagCreateInput{
+		Name: prefix + "-unused",
+	})
+	if err != nil {
+		return
+	}
+
+	title := "title"
+	_, err = s.createTestScene(&models.SceneCreateInput{
+		Title:  &title,
+		TagIds: []string{usedTag.ID.String()},
+		Fingerprints: []*models.FingerprintInput{
+			s.generateSceneFingerprint(),
+		},
+	})
+	if err != nil {
+		return
+	}
+
+	r := s.resolver.Tag()
+	if count, _ := r.SceneCount(s.ctx, usedTag); count != 1 {
+		s.fieldMismatch(1, count, "SceneCount")
+	}
+	if count, _ := r.SceneCount(s.ctx, unusedTag); count != 0 {
+		s.fieldMismatch(0, count, "SceneCount")
+	}
+
+	sort := "scene_count"
+	direction := models.SortDirectionEnumDesc
+	result, err := s.resolver.Query().QueryTags(s.ctx, &models.TagFilterType{
+		Name: &prefix,
+	}, &models.QuerySpec{
+		Sort:      &sort,
+		Direction: &direction,
+	})
+	if err != nil {
+		s.t.Errorf("Error querying tags: %s", err.Error())
+		return
+	}
+	if result.Count != 2 || result.Tags[0].ID != usedTag.ID {
+		s.t.Errorf("Expected %s sorted first, got %v", usedTag.Name, result.Tags)
+	}
+
+	result, err = s.resolver.Query().QueryTags(s.ctx, &models.TagFilterType{
+		Name: &prefix,
+		SceneCount: &models.IntCriterionInput{
+			Value:    0,
+			Modifier: models.CriterionModifierEquals,
+		},
+	}, nil)
+	if err != nil {
+		s.t.Errorf("Error querying tags: %s", err.Error())
+		return
+	}
+	if result.Count != 1 || result.Tags[0].ID != unusedTag.ID {
+		s.t.Errorf("Expected only unused tag %s, got %v", unusedTag.Name, result.Tags)
+	}
+}
+
 func TestCreateTag(t *testing.T) {
 	pt := createTagTestRunner(t)
 	pt.testCreateTag()
@@ -236,6 +303,11 @@ func TestDestroyTag(t *testing.T) {
 	pt.testDestroyTag()
 }
 
+func TestTagSceneCount(t *testing.T) {
+	pt := createTagTestRunner(t)
+	pt.testTagSceneCount()
+}
+
 func TestUnauthorisedTagModify(t *testing.T) {
 	pt := &tagTestRunner{
 		testRunner: *asRead(t),
@@ -7,8 +7,14 @@ import (
 	"testing"
 	"time"
 
+	"github.com/gofrs/uuid"
+
 	"github.com/stashapp/stash-box/pkg/api"
+	"github.com/stashapp/stash-box/pkg/database"
 	"github.com/stashapp/stash-box/pkg/models"
+	"github.com/stashapp/stash-box/pkg/oidc"
+	"github.com/stashapp/stash-box/pkg/user"
+	"github.com/stashapp/stash-box/pkg/utils"
 )
 
 type userTestRunner struct {
@@ -488,6 +494,622 @@ func (s *userTestRunner) testUserEditQuery() {
 	// TODO: Test edits are returned
 }
 
+func (s *userTestRunner) testUserAPICalls() {
+	createdUser, err := s.createTestUser(nil)
+	if err != nil {
+		return
+	}
+
+	user.CountAPICall(createdUser.ID)
+	user.CountAPICall(createdUser.ID)
+
+	if err := user.FlushAPICalls(s.ctx); err != nil {
+		s.t.Errorf("Error flushing API calls: %s", err.Error())
+		return
+	}
+
+	limit := 1000
+	consumers, err := s.resolver.Query().TopAPIConsumers(s.ctx, &limit)
+	if err != nil {
+		s.t.Errorf("Error finding top API consumers: %s", err.Error())
+		return
+	}
+
+	found := false
+	for _, c := range consumers {
+		if c.UserID == createdUser.ID {
+			found = true
+			if c.APICalls != 2 {
+				s.t.Errorf("Incorrect API calls: got %d, want 2", c.APICalls)
+			}
+		}
+	}
+	if !found {
+		s.t.Error("User not found in top API consumers")
+	}
+
+	name := createdUser.Name
+	filter := models.UserFilterType{
+		Name: &name,
+		APICalls: &models.IntCriterionInput{
+			Value:    2,
+			Modifier: models.CriterionModifierEquals,
+		},
+	}
+	result, err := s.resolver.Query().QueryUsers(s.ctx, &filter, nil)
+	if err != nil {
+		s.t.Errorf("Error querying users: %s", err.Error())
+		return
+	}
+
+	if result.Count != 1 {
+		s.t.Errorf("Incorrect user count for api_calls filter: got %d, want 1", result.Count)
+	}
+
+	// non-admins may not list API consumers
+	ctx := context.WithValue(context.TODO(), api.ContextUser, createdUser)
+	if _, err := s.resolver.Query().TopAPIConsumers(ctx, &limit); err == nil {
+		s.t.Error("Expected error for non-admin top API consumers query")
+	}
+
+	// calls counted for a user deleted before the flush are dropped
+	deletedUser, err := s.createTestUser(nil)
+	if err != nil {
+		return
+	}
+	user.CountAPICall(deletedUser.ID)
+	if _, err := s.resolver.Mutation().UserDestroy(s.ctx, models.UserDestroyInput{
+		ID: deletedUser.ID.String(),
+	}); err != nil {
+		s.t.Errorf("Error destroying user: %s", err.Error())
+		return
+	}
+
+	if err := user.FlushAPICalls(s.ctx); err != nil {
+		s.t.Errorf("Error flushing API calls of deleted user: %s", err.Error())
+	}
+}
+
+func (s *userTestRunner) testNamedAPIKeys() {
+	name := s.generateUserName()
+	createdUser, err := s.createTestUser(&models.UserCreateInput{
+		Name:     name,
+		Email:    name + "@example.com",
+		Password: "password" + name,
+		Roles:    []models.RoleEnum{models.RoleEnumEdit},
+	})
+	if err != nil {
+		return
+	}
+
+	ctx := context.WithValue(context.TODO(), api.ContextUser, createdUser)
+	ctx = context.WithValue(ctx, api.ContextRoles, []models.RoleEnum{models.RoleEnumEdit})
+
+	_, err = s.resolver.Mutation().APIKeyCreate(ctx, models.APIKeyCreateInput{
+		Name:  "admin",
+		Roles: []models.RoleEnum{models.RoleEnumAdmin},
+	})
+	if err != user.ErrAPIKeyRoles {
+		s.t.Errorf("Expected ErrAPIKeyRoles for role the user does not have, got %v", err)
+	}
+
+	past := time.Now().Add(-time.Hour)
+	_, err = s.resolver.Mutation().APIKeyCreate(ctx, models.APIKeyCreateInput{
+		Name:      "expired",
+		ExpiresAt: &past,
+	})
+	if err != user.ErrAPIKeyExpiry {
+		s.t.Errorf("Expected ErrAPIKeyExpiry for past expiry, got %v", err)
+	}
+
+	result, err := s.resolver.Mutation().APIKeyCreate(ctx, models.APIKeyCreateInput{
+		Name:  "scraper",
+		Roles: []models.RoleEnum{models.RoleEnumRead},
+	})
+	if err != nil {
+		s.t.Errorf("Error creating API key: %s", err.Error())
+		return
+	}
+
+	if !user.IsNamedAPIKey(result.Key) || result.APIKey.KeyHash == result.Key {
+		s.t.Errorf("Invalid API key: %s", result.Key)
+	}
+
+	keys, err := s.resolver.Query().APIKeys(ctx, nil)
+	if err != nil {
+		s.t.Errorf("Error listing API keys: %s", err.Error())
+		return
+	}
+	if len(keys) != 1 || keys[0].Name != "scraper" {
+		s.t.Errorf("Incorrect API keys: %v", keys)
+		return
+	}
+
+	userID, roles, err := user.GetNamedAPIKeyUser(result.Key)
+	if err != nil {
+		s.t.Errorf("Error authenticating API key: %s", err.Error())
+		return
+	}
+	if userID != createdUser.ID.String() {
+		s.t.Errorf("Incorrect API key user: got %s, want %s", userID, createdUser.ID.String())
+	}
+	if len(roles) != 1 || roles[0] != models.RoleEnumRead {
+		s.t.Errorf("Incorrect API key roles: %v", roles)
+	}
+
+	// a request made with the read-only key may not create a key with more
+	// roles, or manage keys at all
+	keyCtx := context.WithValue(context.TODO(), api.ContextUser, createdUser)
+	keyCtx = context.WithValue(keyCtx, api.ContextRoles, roles)
+	keyCtx = context.WithValue(keyCtx, api.ContextNamedAPIKey, true)
+	_, err = s.resolver.Mutation().APIKeyCreate(keyCtx, models.APIKeyCreateInput{
+		Name:  "escalated",
+		Roles: []models.RoleEnum{models.RoleEnumEdit},
+	})
+	if err != api.ErrNamedAPIKeyManagement {
+		s.t.Errorf("Expected ErrNamedAPIKeyManagement creating key with named key, got %v", err)
+	}
+	if _, err := s.resolver.Mutation().RegenerateAPIKey(keyCtx, nil); err != api.ErrNamedAPIKeyManagement {
+		s.t.Errorf("Expected ErrNamedAPIKeyManagement regenerating key with named key, got %v", err)
+	}
+
+	// nor manage the account's sessions, password or two-factor authentication
+	if _, err := s.resolver.Mutation().RevokeSession(keyCtx, uuid.Must(uuid.NewV4()).String()); err != api.ErrNamedAPIKeyManagement {
+		s.t.Errorf("Expected ErrNamedAPIKeyManagement revoking session with named key, got %v", err)
+	}
+	if _, err := s.resolver.Mutation().TotpEnroll(keyCtx); err != api.ErrNamedAPIKeyManagement {
+		s.t.Errorf("Expected ErrNamedAPIKeyManagement enrolling TOTP with named key, got %v", err)
+	}
+	if _, err := s.resolver.Mutation().TotpActivate(keyCtx, "000000"); err != api.ErrNamedAPIKeyManagement {
+		s.t.Errorf("Expected ErrNamedAPIKeyManagement activating TOTP with named key, got %v", err)
+	}
+	if _, err := s.resolver.Mutation().TotpRegenerateRecoveryCodes(keyCtx, "000000"); err != api.ErrNamedAPIKeyManagement {
+		s.t.Errorf("Expected ErrNamedAPIKeyManagement regenerating recovery codes with named key, got %v", err)
+	}
+	if _, err := s.resolver.Mutation().TotpDisable(keyCtx, "000000"); err != api.ErrNamedAPIKeyManagement {
+		s.t.Errorf("Expected ErrNamedAPIKeyManagement disabling TOTP with named key, got %v", err)
+	}
+	existingPassword := "password"
+	_, err = s.resolver.Mutation().ChangePassword(keyCtx, models.UserChangePasswordInput{
+		ExistingPassword: &existingPassword,
+		NewPassword:      "newpassword" + createdUser.Name,
+	})
+	if err != api.ErrNamedAPIKeyManagement {
+		s.t.Errorf("Expected ErrNamedAPIKeyManagement changing password with named key, got %v", err)
+	}
+
+	// keys are limited to the roles of the request, not of the user
+	readCtx := context.WithValue(context.TODO(), api.ContextUser, createdUser)
+	readCtx = context.WithValue(readCtx, api.ContextRoles, roles)
+	_, err = s.resolver.Mutation().APIKeyCreate(readCtx, models.APIKeyCreateInput{
+		Name:  "escalated",
+		Roles: []models.RoleEnum{models.RoleEnumEdit},
+	})
+	if err != user.ErrAPIKeyRoles {
+		s.t.Errorf("Expected ErrAPIKeyRoles for role the request does not have, got %v", err)
+	}
+
+	keyID := result.APIKey.ID.String()
+	otherCtx := context.WithValue(context.TODO(), api.ContextUser, userDB.read)
+	if _, err := s.resolver.Mutation().APIKeyRevoke(otherCtx, keyID); err == nil {
+		s.t.Error("Expected error revoking API key of another user")
+	}
+
+	if _, err := s.resolver.Mutation().APIKeyRevoke(ctx, keyID); err != nil {
+		s.t.Errorf("Error revoking API key: %s", err.Error())
+		return
+	}
+
+	if _, _, err := user.GetNamedAPIKeyUser(result.Key); err != user.ErrInvalidToken {
+		s.t.Errorf("Expected ErrInvalidToken for revoked key, got %v", err)
+	}
+}
+
+func (s *userTestRunner) testSessions() {
+	name := s.generateUserName()
+	password := "password" + name
+	createdUser, err := s.createTestUser(&models.UserCreateInput{
+		Name:     name,
+		Email:    name + "@example.com",
+		Password: password,
+		Roles:    []models.RoleEnum{models.RoleEnumRead},
+	})
+	if err != nil {
+		return
+	}
+
+	var tokens []string
+	for i := 0; i < 2; i++ {
+		err := database.WithTransaction(s.ctx, func(txn database.Transaction) error {
+			token, err := user.CreateSession(txn.GetTx(), createdUser.ID, user.SessionInfo{
+				IP:        "127.0.0.1",
+				UserAgent: "Mozilla/5.0 (X11; Linux x86_64; rv:89.0) Gecko/20100101 Firefox/89.0",
+			})
+			tokens = append(tokens, token)
+			return err
+		})
+		if err != nil {
+			s.t.Errorf("Error creating session: %s", err.Error())
+			return
+		}
+	}
+
+	current, err := user.GetSession(tokens[0], "127.0.0.2")
+	if err != nil || current == nil {
+		s.t.Errorf("Error getting session: %v", err)
+		return
+	}
+	if current.Device != "Firefox on Linux" || current.IP != "127.0.0.2" {
+		s.t.Errorf("Incorrect session metadata: device %s, ip %s", current.Device, current.IP)
+	}
+
+	ctx := context.WithValue(context.TODO(), api.ContextUser, createdUser)
+	ctx = context.WithValue(ctx, api.ContextSession, current)
+
+	sessions, err := s.resolver.Query().MySessions(ctx)
+	if err != nil {
+		s.t.Errorf("Error listing sessions: %s", err.Error())
+		return
+	}
+	if len(sessions) != 2 {
+		s.t.Errorf("Incorrect session count: got %d, want 2", len(sessions))
+	}
+
+	// changing the password ends the other session
+	_, err = s.resolver.Mutation().ChangePassword(ctx, models.UserChangePasswordInput{
+		ExistingPassword: &password,
+		NewPassword:      "new" + password,
+	})
+	if err != nil {
+		s.t.Errorf("Error changing password: %s", err.Error())
+		return
+	}
+
+	if other, _ := user.GetSession(tokens[1], "127.0.0.1"); other != nil {
+		s.t.Error("Expected other session to end after password change")
+	}
+
+	sessionID := current.ID.String()
+	otherCtx := context.WithValue(context.TODO(), api.ContextUser, userDB.read)
+	if _, err := s.resolver.Mutation().RevokeSession(otherCtx, sessionID); err == nil {
+		s.t.Error("Expected error revoking session of another user")
+	}
+
+	if _, err := s.resolver.Mutation().RevokeSession(ctx, sessionID); err != nil {
+		s.t.Errorf("Error revoking session: %s", err.Error())
+		return
+	}
+
+	if revoked, _ := user.GetSession(tokens[0], "127.0.0.1"); revoked != nil {
+		s.t.Error("Expected revoked session to end")
+	}
+}
+
+func (s *userTestRunner) validateLoginTOTP(userID uuid.UUID, code string) error {
+	return database.WithTransaction(s.ctx, func(txn database.Transaction) error {
+		return user.ValidateLoginTOTP(txn.GetTx(), userID, code)
+	})
+}
+
+func (s *userTestRunner) testTOTP() {
+	createdUser, err := s.createTestUser(nil)
+	if err != nil {
+		return
+	}
+
+	ctx := context.WithValue(context.TODO(), api.ContextUser, createdUser)
+
+	enrollment, err := s.resolver.Mutation().TotpEnroll(ctx)
+	if err != nil {
+		s.t.Errorf("Error enrolling TOTP: %s", err.Error())
+		return
+	}
+
+	if _, err := s.resolver.Mutation().TotpActivate(ctx, "000000x"); err != user.ErrInvalidTOTP {
+		s.t.Errorf("Expected ErrInvalidTOTP activating with invalid code, got %v", err)
+	}
+
+	step := utils.TOTPStep(time.Now())
+	code, _ := utils.TOTPCode(enrollment.Secret, step)
+	recoveryCodes, err := s.resolver.Mutation().TotpActivate(ctx, code)
+	if err != nil {
+		s.t.Errorf("Error activating TOTP: %s", err.Error())
+		return
+	}
+	if len(recoveryCodes) != 10 {
+		s.t.Errorf("Incorrect recovery code count: got %d, want 10", len(recoveryCodes))
+		return
+	}
+
+	if err := s.validateLoginTOTP(createdUser.ID, ""); err != user.ErrTOTPRequired {
+		s.t.Errorf("Expected ErrTOTPRequired for login without code, got %v", err)
+	}
+
+	// codes may not be reused
+	if err := s.validateLoginTOTP(createdUser.ID, code); err != user.ErrInvalidTOTP {
+		s.t.Errorf("Expected ErrInvalidTOTP for reused code, got %v", err)
+	}
+
+	next, _ := utils.TOTPCode(enrollment.Secret, step+1)
+	if err := s.validateLoginTOTP(createdUser.ID, next); err != nil {
+		s.t.Errorf("Error validating TOTP code: %s", err.Error())
+	}
+
+	if err := s.validateLoginTOTP(createdUser.ID, recoveryCodes[0]); err != nil {
+		s.t.Errorf("Error validating recovery code: %s", err.Error())
+	}
+	if err := s.validateLoginTOTP(createdUser.ID, recoveryCodes[0]); err != user.ErrInvalidTOTP {
+		s.t.Errorf("Expected ErrInvalidTOTP for reused recovery code, got %v", err)
+	}
+
+	if _, err := s.resolver.Mutation().TotpDisable(ctx, recoveryCodes[1]); err != nil {
+		s.t.Errorf("Error disabling TOTP: %s", err.Error())
+		return
+	}
+
+	if err := s.validateLoginTOTP(createdUser.ID, ""); err != nil {
+		s.t.Errorf("Expected no code to be required after disabling TOTP, got %v", err)
+	}
+}
+
+func (s *userTestRunner) testTOTPLockout() {
+	createdUser, err := s.createTestUser(nil)
+	if err != nil {
+		return
+	}
+
+	ctx := context.WithValue(context.TODO(), api.ContextUser, createdUser)
+
+	enrollment, err := s.resolver.Mutation().TotpEnroll(ctx)
+	if err != nil {
+		s.t.Errorf("Error enrolling TOTP: %s", err.Error())
+		return
+	}
+
+	step := utils.TOTPStep(time.Now())
+	code, _ := utils.TOTPCode(enrollment.Secret, step)
+	if _, err := s.resolver.Mutation().TotpActivate(ctx, code); err != nil {
+		s.t.Errorf("Error activating TOTP: %s", err.Error())
+		return
+	}
+
+	for i := 0; i < 5; i++ {
+		if err := s.validateLoginTOTP(createdUser.ID, "000000x"); err != user.ErrInvalidTOTP {
+			s.t.Errorf("Expected ErrInvalidTOTP for invalid code, got %v", err)
+			return
+		}
+	}
+
+	// valid codes are refused while locked out
+	next, _ := utils.TOTPCode(enrollment.Secret, step+1)
+	if err := s.validateLoginTOTP(createdUser.ID, next); err != user.ErrTOTPLocked {
+		s.t.Errorf("Expected ErrTOTPLocked after repeated invalid codes, got %v", err)
+	}
+}
+
+func (s *userTestRunner) loginOIDC(claims *oidc.Claims, autoProvision bool) (*models.User, error) {
+	var u *models.User
+	err := database.WithTransaction(s.ctx, func(txn database.Transaction) error {
+		var err error
+		u, err = user.LoginOIDC(txn.GetTx(), claims, autoProvision)
+		return err
+	})
+	return u, err
+}
+
+func (s *userTestRunner) testOIDCLogin() {
+	name := s.generateUserName()
+	createdUser, err := s.createTestUser(&models.UserCreateInput{
+		Name:     name,
+		Email:    name + "@example.com",
+		Password: "password" + name,
+		Roles:    []models.RoleEnum{models.RoleEnumRead},
+	})
+	if err != nil {
+		return
+	}
+
+	const issuer = "https://idp.example.com"
+
+	// an unverified email does not link an existing user
+	_, err = s.loginOIDC(&oidc.Claims{
+		Issuer:  issuer,
+		Subject: name,
+		Email:   createdUser.Email,
+	}, true)
+	if err != user.ErrOIDCEmailInUse {
+		s.t.Errorf("Expected ErrOIDCEmailInUse for unverified email, got %v", err)
+	}
+
+	claims := &oidc.Claims{
+		Issuer:        issuer,
+		Subject:       name,
+		Email:         createdUser.Email,
+		EmailVerified: true,
+	}
+	u, err := s.loginOIDC(claims, false)
+	if err != nil {
+		s.t.Errorf("Error linking identity: %s", err.Error())
+		return
+	}
+	if u.ID != createdUser.ID {
+		s.t.Errorf("Incorrect linked user: got %s, want %s", u.ID, createdUser.ID)
+	}
+
+	// the linked identity no longer needs the email
+	u, err = s.loginOIDC(&oidc.Claims{Issuer: issuer, Subject: name}, false)
+	if err != nil || u == nil || u.ID != createdUser.ID {
+		s.t.Errorf("Expected linked user on second login, got %v, %v", u, err)
+	}
+
+	// privileged users must link the identity themselves
+	modName := s.generateUserName()
+	modUser, err := s.createTestUser(&models.UserCreateInput{
+		Name:     modName,
+		Email:    modName + "@example.com",
+		Password: "password" + modName,
+		Roles:    []models.RoleEnum{models.RoleEnumModify},
+	})
+	if err != nil {
+		return
+	}
+	modClaims := &oidc.Claims{
+		Issuer:        issuer,
+		Subject:       modName,
+		Email:         modUser.Email,
+		EmailVerified: true,
+	}
+	if _, err := s.loginOIDC(modClaims, true); err != user.ErrOIDCLinkRequired {
+		s.t.Errorf("Expected ErrOIDCLinkRequired for privileged user, got %v", err)
+	}
+
+	err = database.WithTransaction(s.ctx, func(txn database.Transaction) error {
+		return user.LinkOIDCIdentity(txn.GetTx(), modUser.ID, modClaims)
+	})
+	if err != nil {
+		s.t.Errorf("Error linking identity: %s", err.Error())
+		return
+	}
+	u, err = s.loginOIDC(modClaims, false)
+	if err != nil || u == nil || u.ID != modUser.ID {
+		s.t.Errorf("Expected explicitly linked user on login, got %v, %v", u, err)
+	}
+
+	err = database.WithTransaction(s.ctx, func(txn database.Transaction) error {
+		return user.LinkOIDCIdentity(txn.GetTx(), createdUser.ID, modClaims)
+	})
+	if err != user.ErrOIDCIdentityInUse {
+		s.t.Errorf("Expected ErrOIDCIdentityInUse linking identity of another user, got %v", err)
+	}
+
+	newName := s.generateUserName()
+	newClaims := &oidc.Claims{
+		Issuer:            issuer,
+		Subject:           newName,
+		Email:             newName + "@example.com",
+		PreferredUsername: newName,
+	}
+	if _, err := s.loginOIDC(newClaims, false); err != user.ErrOIDCNoAccount {
+		s.t.Errorf("Expected ErrOIDCNoAccount without auto-provisioning, got %v", err)
+	}
+
+	// accounts are only provisioned for verified emails
+	if _, err := s.loginOIDC(newClaims, true); err != user.ErrOIDCEmailUnverified {
+		s.t.Errorf("Expected ErrOIDCEmailUnverified provisioning unverified email, got %v", err)
+	}
+
+	newClaims.EmailVerified = true
+
+	u, err = s.loginOIDC(newClaims, true)
+	if err != nil {
+		s.t.Errorf("Error provisioning user: %s", err.Error())
+		return
+	}
+	if u.Name != newName || u.Email != newClaims.Email {
+		s.t.Errorf("Incorrect provisioned user: name %s, email %s", u.Name, u.Email)
+	}
+
+	ctx := context.WithValue(context.TODO(), api.ContextUser, u)
+	roles, err := s.resolver.User().Roles(ctx, u)
+	if err != nil {
+		s.t.Errorf("Error getting provisioned user roles: %s", err.Error())
+		return
+	}
+	if len(roles) == 0 {
+		s.t.Error("Expected provisioned user to have the default roles")
+	}
+}
+
+func (s *userTestRunner) testSuspendUser() {
+	name := s.generateUserName()
+	createdUser, err := s.createTestUser(&models.UserCreateInput{
+		Name:     name,
+		Email:    name + "@example.com",
+		Password: "password" + name,
+		Roles:    []models.RoleEnum{models.RoleEnumEdit},
+	})
+	if err != nil {
+		return
+	}
+
+	userID := createdUser.ID.String()
+	until := time.Now().Add(time.Hour)
+
+	if _, err := s.resolver.Mutation().SuspendUser(s.ctx, userID, &until, " "); err != user.ErrEmptySuspensionReason {
+		s.t.Errorf("Expected ErrEmptySuspensionReason, got %v", err)
+	}
+
+	if _, err := s.resolver.Mutation().SuspendUser(s.ctx, userDB.admin.ID.String(), &until, "test"); err != user.ErrSuspendSelf {
+		s.t.Errorf("Expected ErrSuspendSelf, got %v", err)
+	}
+
+	suspension, err := s.resolver.Mutation().SuspendUser(s.ctx, userID, &until, "vandalism")
+	if err != nil {
+		s.t.Errorf("Error suspending user: %s", err.Error())
+		return
+	}
+
+	suspendedUser, err := user.Get(userID)
+	if err != nil {
+		s.t.Errorf("Error getting user: %s", err.Error())
+		return
+	}
+
+	ctx := context.WithValue(context.TODO(), api.ContextUser, suspendedUser)
+	ctx = context.WithValue(ctx, api.ContextRoles, []models.RoleEnum{models.RoleEnumEdit})
+
+	// the user can see their suspension, but not edit
+	visible, err := s.resolver.User().Suspension(ctx, suspendedUser)
+	if err != nil || visible == nil || visible.ID != suspension.ID || visible.Reason != "vandalism" {
+		s.t.Errorf("Expected suspension to be visible to the user, got %v, %v", visible, err)
+	}
+
+	if _, err := s.resolver.Mutation().TagEdit(ctx, models.TagEditInput{}); !user.IsSuspendedError(err) {
+		s.t.Errorf("Expected SuspendedError for edit, got %v", err)
+	}
+
+	if _, err := s.resolver.Query().Me(ctx); err != nil {
+		s.t.Errorf("Expected suspended user to keep read access, got %s", err.Error())
+	}
+
+	err = database.WithTransaction(s.ctx, func(txn database.Transaction) error {
+		return user.ValidateLoginSuspension(txn.GetTx(), createdUser.ID)
+	})
+	if !user.IsSuspendedError(err) {
+		s.t.Errorf("Expected SuspendedError for login, got %v", err)
+	}
+
+	if _, err := s.resolver.Mutation().UnsuspendUser(s.ctx, userID, nil); err != nil {
+		s.t.Errorf("Error unsuspending user: %s", err.Error())
+		return
+	}
+
+	if _, err := s.resolver.Mutation().UnsuspendUser(s.ctx, userID, nil); err != user.ErrNotSuspended {
+		s.t.Errorf("Expected ErrNotSuspended, got %v", err)
+	}
+
+	err = database.WithTransaction(s.ctx, func(txn database.Transaction) error {
+		return user.ValidateLoginSuspension(txn.GetTx(), createdUser.ID)
+	})
+	if err != nil {
+		s.t.Errorf("Expected login after suspension was lifted, got %s", err.Error())
+	}
+
+	history, err := s.resolver.User().ModerationHistory(s.ctx, createdUser)
+	if err != nil {
+		s.t.Errorf("Error getting moderation history: %s", err.Error())
+		return
+	}
+	if len(history) != 2 || history[0].Action != models.UserModerationActionEnumUnsuspend.String() {
+		s.t.Errorf("Incorrect moderation history: %v", history)
+	}
+
+	// the history is only visible to admins
+	if history, _ := s.resolver.User().ModerationHistory(ctx, createdUser); history != nil {
+		s.t.Error("Expected moderation history to be hidden from the user")
+	}
+}
+
 func TestCreateUser(t *testing.T) {
 	pt := createUserTestRunner(t)
 	pt.testCreateUser()
@@ -563,3 +1185,38 @@ func TestUserEditQuery(t *testing.T) {
 	pt := createUserTestRunner(t)
 	pt.testUserEditQuery()
 }
+
+func TestUserAPICalls(t *testing.T) {
+	pt := createUserTestRunner(t)
+	pt.testUserAPICalls()
+}
+
+func TestNamedAPIKeys(t *testing.T) {
+	pt := createUserTestRunner(t)
+	pt.testNamedAPIKeys()
+}
+
+func TestSessions(t *testing.T) {
+	pt := createUserTestRunner(t)
+	pt.testSessions()
+}
+
+func TestTOTP(t *testing.T) {
+	pt := createUserTestRunner(t)
+	pt.testTOTP()
+}
+
+func TestTOTPLockout(t *testing.T) {
+	pt := createUserTestRunner(t)
+	pt.testTOTPLockout()
+}
+
+func TestOIDCLogin(t *testing.T) {
+	pt := createUserTestRunner(t)
+	pt.testOIDCLogin()
+}
+
+func TestSuspendUser(t *testing.T) {
+	pt := createUserTestRunner(t)
+	pt.testSuspendUser()
+}
@@ -0,0 +1,43 @@
+CREATE TABLE "groups" (
+  "id" UUID NOT NULL PRIMARY KEY,
+  "title" VARCHAR(255) NOT NULL,
+  "studio_id" UUID,
+  "date" DATE,
+  "front_image_id" UUID,
+  "back_image_id" UUID,
+  "created_at" TIMESTAMP NOT NULL,
+  "updated_at" TIMESTAMP NOT NULL,
+  "deleted" BOOLEAN NOT NULL DEFAULT FALSE,
+  FOREIGN KEY("studio_id") REFERENCES "studios"("id") ON DELETE SET NULL,
+  FOREIGN KEY("front_image_id") REFERENCES "images"("id") ON DELETE SET NULL,
+  FOREIGN KEY("back_image_id") REFERENCES "images"("id") ON DELETE SET NULL
+);
+
+CREATE INDEX "groups_title_idx" ON "groups" ("title");
+CREATE INDEX "groups_studio_id_idx" ON "groups" ("studio_id");
+
+CREATE TABLE "group_urls" (
+  "group_id" UUID NOT NULL,
+  "url" VARCHAR NOT NULL,
+  "type" VARCHAR(255) NOT NULL,
+  FOREIGN KEY("group_id") REFERENCES "groups"("id") ON DELETE CASCADE,
+  UNIQUE ("group_id", "url")
+);
+
+CREATE TABLE "group_scenes" (
+  "group_id" UUID NOT NULL,
+  "scene_id" UUID NOT NULL,
+  "scene_number" INTEGER,
+  FOREIGN KEY("group_id") REFERENCES "groups"("id") ON DELETE CASCADE,
+  FOREIGN KEY("scene_id") REFERENCES "scenes"("id") ON DELETE CASCADE,
+  UNIQUE ("group_id", "scene_id")
+);
+
+CREATE INDEX "group_scenes_scene_id_idx" ON "group_scenes" ("scene_id");
+
+CREATE TABLE "group_edits" (
+  "edit_id" UUID NOT NULL,
+  "group_id" UUID NOT NULL,
+  FOREIGN KEY("edit_id") REFERENCES "edits"("id"),
+  FOREIGN KEY("group_id") REFERENCES "groups"("id")
+);
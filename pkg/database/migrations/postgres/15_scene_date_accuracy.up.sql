@@ -0,0 +1,3 @@
+ALTER TABLE "scenes" ADD COLUMN "date_accuracy" VARCHAR(10);
+
+UPDATE "scenes" SET "date_accuracy" = 'DAY' WHERE "date" IS NOT NULL;
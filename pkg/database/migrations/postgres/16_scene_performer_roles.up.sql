@@ -0,0 +1,4 @@
+ALTER TABLE "scene_performers"
+  ADD COLUMN "role" VARCHAR(255),
+  ADD COLUMN "position" INTEGER,
+  ADD COLUMN "credited" BOOLEAN NOT NULL DEFAULT TRUE;
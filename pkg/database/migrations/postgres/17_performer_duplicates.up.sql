@@ -0,0 +1,23 @@
+CREATE INDEX performer_aliases_trgm_idx ON performer_aliases USING GIN (alias gin_trgm_ops);
+
+CREATE TABLE "performer_duplicates" (
+  "id" UUID NOT NULL PRIMARY KEY,
+  "performer_id" UUID NOT NULL,
+  "duplicate_id" UUID NOT NULL,
+  "score" DOUBLE PRECISION NOT NULL,
+  "name_similarity" DOUBLE PRECISION NOT NULL,
+  "birthdate_match" BOOLEAN,
+  "shared_urls" INTEGER NOT NULL DEFAULT 0,
+  "shared_scenes" INTEGER NOT NULL DEFAULT 0,
+  "status" VARCHAR(10) NOT NULL,
+  "edit_id" UUID,
+  "created_at" TIMESTAMP NOT NULL,
+  "updated_at" TIMESTAMP NOT NULL,
+  FOREIGN KEY("performer_id") REFERENCES "performers"("id") ON DELETE CASCADE,
+  FOREIGN KEY("duplicate_id") REFERENCES "performers"("id") ON DELETE CASCADE,
+  FOREIGN KEY("edit_id") REFERENCES "edits"("id") ON DELETE SET NULL,
+  UNIQUE ("performer_id", "duplicate_id")
+);
+
+CREATE INDEX performer_duplicates_status_score_idx ON performer_duplicates (status, score DESC);
+CREATE INDEX performer_duplicates_duplicate_idx ON performer_duplicates (duplicate_id);
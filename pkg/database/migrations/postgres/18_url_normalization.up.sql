@@ -0,0 +1,38 @@
+-- Normalizes a URL for comparison: removes the scheme, leading www., fragment,
+-- trailing slashes and tracking parameters, lowercases the host and sorts the
+-- remaining query parameters.
+CREATE FUNCTION normalize_url(url TEXT) RETURNS TEXT AS $$
+DECLARE
+  rest TEXT;
+  host TEXT;
+  path TEXT;
+  query TEXT;
+BEGIN
+  rest := regexp_replace(trim(url), '^[a-zA-Z][a-zA-Z0-9+.-]*://', '');
+  rest := regexp_replace(rest, '#.*$', '');
+
+  host := regexp_replace(lower(substring(rest from '^[^/?]*')), '^www\.', '');
+  path := rtrim(COALESCE(substring(rest from '^[^/?]*([^?]*)'), ''), '/');
+  query := substring(rest from '\?(.*)$');
+
+  IF query IS NOT NULL THEN
+    query := array_to_string(ARRAY(
+      SELECT param FROM unnest(string_to_array(query, '&')) AS param
+      WHERE param <> ''
+      AND param !~* '^(utm_[a-z_]*|fbclid|gclid|dclid|msclkid|mc_cid|mc_eid|igshid|_ga)(=|$)'
+      ORDER BY param
+    ), '&');
+  END IF;
+
+  IF query IS NULL OR query = '' THEN
+    RETURN host || path;
+  END IF;
+
+  RETURN host || path || '?' || query;
+END
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+CREATE INDEX performer_urls_normalized_idx ON performer_urls (normalize_url(url));
+CREATE INDEX scene_urls_normalized_idx ON scene_urls (normalize_url(url));
+CREATE INDEX studio_urls_normalized_idx ON studio_urls (normalize_url(url));
+CREATE INDEX group_urls_normalized_idx ON group_urls (normalize_url(url));
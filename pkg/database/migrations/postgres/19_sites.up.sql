@@ -0,0 +1,41 @@
+CREATE TABLE "sites" (
+  "id" uuid NOT NULL PRIMARY KEY,
+  "name" text NOT NULL,
+  "description" text,
+  "url" text,
+  "regex" text,
+  "valid_types" text[] NOT NULL,
+  "icon" text,
+  "created_at" timestamp NOT NULL,
+  "updated_at" timestamp NOT NULL
+);
+
+CREATE UNIQUE INDEX "sites_name_idx" ON "sites" (lower("name"));
+
+ALTER TABLE "performer_urls" ADD COLUMN "site_id" uuid REFERENCES "sites"("id") ON DELETE SET NULL;
+ALTER TABLE "scene_urls" ADD COLUMN "site_id" uuid REFERENCES "sites"("id") ON DELETE SET NULL;
+ALTER TABLE "studio_urls" ADD COLUMN "site_id" uuid REFERENCES "sites"("id") ON DELETE SET NULL;
+ALTER TABLE "group_urls" ADD COLUMN "site_id" uuid REFERENCES "sites"("id") ON DELETE SET NULL;
+
+-- Create a site for each existing url type, ignoring case. The most common
+-- spelling is used as the name, and the site is valid for every entity type
+-- the url type is currently used with.
+WITH "url_types" AS (
+  SELECT "type", 'PERFORMER' AS "valid_type" FROM "performer_urls"
+  UNION ALL
+  SELECT "type", 'SCENE' FROM "scene_urls"
+  UNION ALL
+  SELECT "type", 'STUDIO' FROM "studio_urls"
+  UNION ALL
+  SELECT "type", 'GROUP' FROM "group_urls"
+)
+INSERT INTO "sites" ("id", "name", "valid_types", "created_at", "updated_at")
+SELECT md5(lower("type"))::uuid, mode() WITHIN GROUP (ORDER BY "type"), array_agg(DISTINCT "valid_type" ORDER BY "valid_type"), NOW(), NOW()
+FROM "url_types"
+WHERE trim("type") <> ''
+GROUP BY lower("type");
+
+UPDATE "performer_urls" SET "site_id" = "sites"."id" FROM "sites" WHERE "sites"."id" = md5(lower("performer_urls"."type"))::uuid;
+UPDATE "scene_urls" SET "site_id" = "sites"."id" FROM "sites" WHERE "sites"."id" = md5(lower("scene_urls"."type"))::uuid;
+UPDATE "studio_urls" SET "site_id" = "sites"."id" FROM "sites" WHERE "sites"."id" = md5(lower("studio_urls"."type"))::uuid;
+UPDATE "group_urls" SET "site_id" = "sites"."id" FROM "sites" WHERE "sites"."id" = md5(lower("group_urls"."type"))::uuid;
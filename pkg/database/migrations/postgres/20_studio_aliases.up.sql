@@ -0,0 +1,65 @@
+CREATE TABLE "studio_aliases" (
+  "studio_id" UUID NOT NULL,
+  "alias" VARCHAR(255) NOT NULL,
+  FOREIGN KEY("studio_id") REFERENCES "studios"("id") ON DELETE CASCADE,
+  UNIQUE ("alias")
+);
+
+CREATE INDEX studio_aliases_studio_id_idx ON studio_aliases (studio_id);
+CREATE INDEX studio_name_trgm_idx ON studios USING GIN (name gin_trgm_ops);
+CREATE INDEX studio_alias_trgm_idx ON studio_aliases USING GIN (alias gin_trgm_ops);
+
+-- The searchable name of a studio, including its aliases and the name of its parent
+CREATE OR REPLACE FUNCTION studio_search_name(UUID) RETURNS TEXT AS $$
+SELECT
+	T.name || ' ' || REGEXP_REPLACE(T.name, '[^a-zA-Z0-9]', '', 'g') || ' ' ||
+	COALESCE((
+		SELECT STRING_AGG(A.alias || ' ' || REGEXP_REPLACE(A.alias, '[^a-zA-Z0-9]', '', 'g'), ' ')
+		FROM studio_aliases A
+		WHERE A.studio_id = T.id
+	) || ' ', '') ||
+	CASE WHEN TP.name IS NOT NULL THEN (TP.name || ' ' || REGEXP_REPLACE(TP.name, '[^a-zA-Z0-9]', '', 'g') ) ELSE '' END
+FROM studios T
+LEFT JOIN studios TP ON T.parent_studio_id = TP.id
+WHERE T.id = $1
+$$ LANGUAGE sql STABLE;
+
+CREATE OR REPLACE FUNCTION insert_scene() RETURNS TRIGGER AS $$
+BEGIN
+INSERT INTO scene_search (scene_id, scene_title, scene_date, studio_name)
+SELECT
+	NEW.id,
+	REGEXP_REPLACE(NEW.title, '[^a-zA-Z0-9 ]+', '', 'g'),
+	NEW.date,
+	studio_search_name(T.id)
+FROM studios T
+WHERE T.id = NEW.studio_id;
+RETURN NULL;
+END;
+$$ LANGUAGE plpgsql; --The trigger used to update a table.
+
+CREATE OR REPLACE FUNCTION update_studio() RETURNS TRIGGER AS $$
+BEGIN
+IF (NEW.name != OLD.name OR NEW.parent_studio_id IS DISTINCT FROM OLD.parent_studio_id) THEN
+UPDATE scene_search SET studio_name = studio_search_name(S.studio_id)
+FROM scenes S
+JOIN studios T ON T.id = S.studio_id
+WHERE scene_search.scene_id = S.id
+AND (T.id = NEW.id OR T.parent_studio_id = NEW.id);
+END IF;
+RETURN NULL;
+END;
+$$ LANGUAGE plpgsql; --The trigger used to update a table.
+
+CREATE OR REPLACE FUNCTION update_studio_aliases() RETURNS TRIGGER AS $$
+BEGIN
+UPDATE scene_search SET studio_name = studio_search_name(S.studio_id)
+FROM scenes S
+WHERE scene_search.scene_id = S.id
+AND S.studio_id = COALESCE(NEW.studio_id, OLD.studio_id);
+RETURN NULL;
+END;
+$$ LANGUAGE plpgsql; --The trigger used to update a table.
+
+DROP TRIGGER IF EXISTS update_studio_aliases_search ON studio_aliases;
+CREATE TRIGGER update_studio_aliases_search AFTER INSERT OR UPDATE OR DELETE ON studio_aliases FOR EACH ROW EXECUTE PROCEDURE update_studio_aliases();
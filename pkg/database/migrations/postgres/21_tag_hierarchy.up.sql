@@ -0,0 +1,10 @@
+CREATE TABLE "tag_parents" (
+  "tag_id" UUID NOT NULL,
+  "parent_id" UUID NOT NULL,
+  FOREIGN KEY("tag_id") REFERENCES "tags"("id") ON DELETE CASCADE,
+  FOREIGN KEY("parent_id") REFERENCES "tags"("id") ON DELETE CASCADE,
+  PRIMARY KEY("tag_id", "parent_id"),
+  CHECK ("tag_id" != "parent_id")
+);
+
+CREATE INDEX tag_parents_parent_id_idx ON tag_parents (parent_id);
@@ -0,0 +1,31 @@
+CREATE TABLE "edit_votes" (
+  "id" UUID NOT NULL PRIMARY KEY,
+  "edit_id" UUID NOT NULL,
+  "user_id" UUID,
+  "created_at" TIMESTAMP NOT NULL,
+  "comment" TEXT,
+  "type" VARCHAR(20) NOT NULL,
+  FOREIGN KEY("edit_id") REFERENCES "edits"("id") ON DELETE CASCADE,
+  FOREIGN KEY("user_id") REFERENCES "users"("id") ON DELETE SET NULL,
+  UNIQUE ("edit_id", "user_id")
+);
+
+CREATE INDEX "edit_votes_user_id_idx" ON "edit_votes" ("user_id");
+
+ALTER TABLE "users"
+  ADD COLUMN "successful_edits" INTEGER NOT NULL DEFAULT 0,
+  ADD COLUMN "unsuccessful_edits" INTEGER NOT NULL DEFAULT 0,
+  ADD COLUMN "successful_votes" INTEGER NOT NULL DEFAULT 0,
+  ADD COLUMN "unsuccessful_votes" INTEGER NOT NULL DEFAULT 0;
+
+UPDATE "users" SET
+  "successful_edits" = (
+    SELECT COUNT(*) FROM "edits"
+    WHERE "edits"."user_id" = "users"."id"
+    AND "edits"."status" IN ('ACCEPTED', 'IMMEDIATE_ACCEPTED')
+  ),
+  "unsuccessful_edits" = (
+    SELECT COUNT(*) FROM "edits"
+    WHERE "edits"."user_id" = "users"."id"
+    AND "edits"."status" IN ('REJECTED', 'IMMEDIATE_REJECTED')
+  );
@@ -0,0 +1,9 @@
+CREATE TABLE "user_api_calls" (
+  "user_id" UUID NOT NULL,
+  "bucket" TIMESTAMP NOT NULL,
+  "calls" INTEGER NOT NULL,
+  FOREIGN KEY("user_id") REFERENCES "users"("id") ON DELETE CASCADE,
+  PRIMARY KEY ("user_id", "bucket")
+);
+
+CREATE INDEX "user_api_calls_bucket_idx" ON "user_api_calls" ("bucket");
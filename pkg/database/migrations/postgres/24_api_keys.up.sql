@@ -0,0 +1,20 @@
+CREATE TABLE "api_keys" (
+  "id" UUID NOT NULL PRIMARY KEY,
+  "user_id" UUID NOT NULL,
+  "name" VARCHAR(255) NOT NULL,
+  "key_hash" VARCHAR(64) NOT NULL,
+  "expires_at" TIMESTAMP,
+  "last_used_at" TIMESTAMP,
+  "created_at" TIMESTAMP NOT NULL,
+  FOREIGN KEY("user_id") REFERENCES "users"("id") ON DELETE CASCADE,
+  UNIQUE ("key_hash")
+);
+
+CREATE INDEX "api_keys_user_id_idx" ON "api_keys" ("user_id");
+
+CREATE TABLE "api_key_roles" (
+  "api_key_id" UUID NOT NULL,
+  "role" VARCHAR(20) NOT NULL,
+  FOREIGN KEY("api_key_id") REFERENCES "api_keys"("id") ON DELETE CASCADE,
+  UNIQUE ("api_key_id", "role")
+);
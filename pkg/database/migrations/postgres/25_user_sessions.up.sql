@@ -0,0 +1,16 @@
+CREATE TABLE "user_sessions" (
+  "id" UUID NOT NULL PRIMARY KEY,
+  "user_id" UUID NOT NULL,
+  "token_hash" VARCHAR(64) NOT NULL,
+  "device" VARCHAR(255) NOT NULL,
+  "ip" VARCHAR(64) NOT NULL,
+  "user_agent" TEXT NOT NULL,
+  "created_at" TIMESTAMP NOT NULL,
+  "last_active_at" TIMESTAMP NOT NULL,
+  "expires_at" TIMESTAMP NOT NULL,
+  FOREIGN KEY("user_id") REFERENCES "users"("id") ON DELETE CASCADE,
+  UNIQUE ("token_hash")
+);
+
+CREATE INDEX "user_sessions_user_id_idx" ON "user_sessions" ("user_id");
+CREATE INDEX "user_sessions_expires_at_idx" ON "user_sessions" ("expires_at");
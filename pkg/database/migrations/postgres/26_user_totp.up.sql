@@ -0,0 +1,11 @@
+ALTER TABLE "users"
+  ADD COLUMN "totp_secret" VARCHAR(64),
+  ADD COLUMN "totp_enabled" BOOLEAN NOT NULL DEFAULT FALSE,
+  ADD COLUMN "totp_last_step" BIGINT NOT NULL DEFAULT 0;
+
+CREATE TABLE "user_recovery_codes" (
+  "user_id" UUID NOT NULL,
+  "code_hash" VARCHAR(64) NOT NULL,
+  FOREIGN KEY("user_id") REFERENCES "users"("id") ON DELETE CASCADE,
+  UNIQUE ("user_id", "code_hash")
+);
@@ -0,0 +1,10 @@
+CREATE TABLE "user_identities" (
+  "issuer" VARCHAR(255) NOT NULL,
+  "subject" VARCHAR(255) NOT NULL,
+  "user_id" UUID NOT NULL,
+  "created_at" TIMESTAMP NOT NULL,
+  FOREIGN KEY("user_id") REFERENCES "users"("id") ON DELETE CASCADE,
+  PRIMARY KEY ("issuer", "subject")
+);
+
+CREATE INDEX "user_identities_user_id_idx" ON "user_identities" ("user_id");
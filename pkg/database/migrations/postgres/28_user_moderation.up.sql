@@ -0,0 +1,16 @@
+CREATE TABLE "user_moderation_actions" (
+  "id" UUID NOT NULL PRIMARY KEY,
+  "user_id" UUID NOT NULL,
+  "moderator_id" UUID,
+  "action" VARCHAR(20) NOT NULL,
+  "reason" TEXT NOT NULL,
+  "until" TIMESTAMP,
+  "created_at" TIMESTAMP NOT NULL,
+  FOREIGN KEY("user_id") REFERENCES "users"("id") ON DELETE CASCADE,
+  FOREIGN KEY("moderator_id") REFERENCES "users"("id") ON DELETE SET NULL
+);
+
+CREATE INDEX "user_moderation_actions_user_id_idx" ON "user_moderation_actions" ("user_id");
+
+ALTER TABLE "users"
+  ADD COLUMN "suspension_id" UUID REFERENCES "user_moderation_actions"("id") ON DELETE SET NULL;
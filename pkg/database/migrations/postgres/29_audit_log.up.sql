@@ -0,0 +1,27 @@
+-- entries outlive the user who made them, so user_id is not a foreign key
+CREATE TABLE "audit_log" (
+  "id" UUID NOT NULL PRIMARY KEY,
+  "user_id" UUID,
+  "user_name" VARCHAR(255) NOT NULL,
+  "action" VARCHAR(64) NOT NULL,
+  "target_type" VARCHAR(20) NOT NULL,
+  "target_id" UUID NOT NULL,
+  "before" JSONB,
+  "after" JSONB,
+  "ip" VARCHAR(64) NOT NULL,
+  "created_at" TIMESTAMP NOT NULL
+);
+
+CREATE INDEX "audit_log_created_at_idx" ON "audit_log" ("created_at");
+CREATE INDEX "audit_log_user_id_idx" ON "audit_log" ("user_id");
+CREATE INDEX "audit_log_target_idx" ON "audit_log" ("target_type", "target_id");
+
+CREATE OR REPLACE FUNCTION prevent_audit_log_change() RETURNS TRIGGER AS $$
+BEGIN
+RAISE EXCEPTION 'audit log entries cannot be changed';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "audit_log_append_only"
+BEFORE UPDATE OR DELETE ON "audit_log"
+FOR EACH ROW EXECUTE PROCEDURE prevent_audit_log_change();
@@ -0,0 +1,27 @@
+CREATE TABLE "notifications" (
+  "id" UUID NOT NULL PRIMARY KEY,
+  "user_id" UUID NOT NULL,
+  "type" VARCHAR(32) NOT NULL,
+  "edit_id" UUID,
+  "actor_id" UUID,
+  "read_at" TIMESTAMP,
+  "emailed_at" TIMESTAMP,
+  "created_at" TIMESTAMP NOT NULL,
+  FOREIGN KEY("user_id") REFERENCES "users"("id") ON DELETE CASCADE,
+  FOREIGN KEY("edit_id") REFERENCES "edits"("id") ON DELETE CASCADE,
+  FOREIGN KEY("actor_id") REFERENCES "users"("id") ON DELETE SET NULL
+);
+
+CREATE INDEX "notifications_user_id_created_at_idx" ON "notifications" ("user_id", "created_at");
+CREATE INDEX "notifications_unread_idx" ON "notifications" ("user_id") WHERE "read_at" IS NULL;
+CREATE INDEX "notifications_unemailed_idx" ON "notifications" ("user_id") WHERE "emailed_at" IS NULL;
+
+-- users without a row for a type receive the notification but not the email
+CREATE TABLE "user_notification_preferences" (
+  "user_id" UUID NOT NULL,
+  "type" VARCHAR(32) NOT NULL,
+  "enabled" BOOLEAN NOT NULL,
+  "email" BOOLEAN NOT NULL,
+  FOREIGN KEY("user_id") REFERENCES "users"("id") ON DELETE CASCADE,
+  UNIQUE ("user_id", "type")
+);
@@ -194,6 +194,9 @@ func sqlGenKeysCreate(i interface{}) (string, string) {
 		//get key for struct tag
 		rawKey := v.Type().Field(i).Tag.Get("db")
 		key := strings.Split(rawKey, ",")[0]
+		if key == "-" {
+			continue
+		}
 		switch t := v.Field(i).Interface().(type) {
 		case string:
 			if t != "" {
@@ -256,7 +259,7 @@ func sqlGenKeys(i interface{}, partial bool) string {
 		//get key for struct tag
 		rawKey := v.Type().Field(i).Tag.Get("db")
 		key := strings.Split(rawKey, ",")[0]
-		if key == "id" {
+		if key == "id" || key == "-" {
 			continue
 		}
 		switch t := v.Field(i).Interface().(type) {
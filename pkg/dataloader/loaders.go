@@ -12,22 +12,38 @@ import (
 const loadersKey = "dataloaders"
 
 type Loaders struct {
+	EditWarningsById       EditWarningsLoader
+	GroupById              GroupLoader
+	GroupScenesById        GroupScenesLoader
+	GroupUrlsById          URLLoader
+	SceneById              SceneLoader
 	SceneFingerprintsById  FingerprintsLoader
+	SceneGroupsById        GroupScenesLoader
 	ImageById              ImageLoader
 	PerformerById          PerformerLoader
 	PerformerAliasesById   StringsLoader
 	PerformerImageIDsById  UUIDsLoader
 	PerformerPiercingsById BodyModificationsLoader
+	PerformerStatsById     PerformerStatsLoader
 	PerformerTattoosById   BodyModificationsLoader
 	PerformerUrlsById      URLLoader
 	SceneImageIDsById      UUIDsLoader
 	SceneAppearancesById   SceneAppearancesLoader
 	SceneUrlsById          URLLoader
+	StudioById             StudioLoader
+	StudioAliasesById      StringsLoader
+	StudioSceneCountById   IntLoader
+	NetworkSceneCountById  IntLoader
 	StudioImageIDsById     UUIDsLoader
 	StudioUrlsById         URLLoader
 	SceneTagIDsById        UUIDsLoader
+	SceneImpliedTagIDsById UUIDsLoader
+	SiteById               SiteLoader
 	TagById                TagLoader
 	TagCategoryById        TagCategoryLoader
+	TagChildIDsById        UUIDsLoader
+	TagParentIDsById       UUIDsLoader
+	TagSceneCountById      IntLoader
 }
 
 func Middleware(next http.Handler) http.Handler {
@@ -47,6 +63,54 @@ func GetLoadersKey() string {
 }
 func GetLoaders() *Loaders {
 	return &Loaders{
+		EditWarningsById: EditWarningsLoader{
+			maxBatch: 100,
+			wait:     1 * time.Millisecond,
+			fetch: func(ids []uuid.UUID) ([][]*models.EditWarning, []error) {
+				qb := models.NewEditQueryBuilder(nil)
+				return qb.GetAllURLWarnings(ids)
+			},
+		},
+		GroupById: GroupLoader{
+			maxBatch: 100,
+			wait:     1 * time.Millisecond,
+			fetch: func(ids []uuid.UUID) ([]*models.Group, []error) {
+				qb := models.NewGroupQueryBuilder(nil)
+				return qb.FindByIds(ids)
+			},
+		},
+		GroupScenesById: GroupScenesLoader{
+			maxBatch: 100,
+			wait:     1 * time.Millisecond,
+			fetch: func(ids []uuid.UUID) ([]models.GroupScenes, []error) {
+				qb := models.NewGroupQueryBuilder(nil)
+				return qb.GetAllScenes(ids)
+			},
+		},
+		GroupUrlsById: URLLoader{
+			maxBatch: 100,
+			wait:     1 * time.Millisecond,
+			fetch: func(ids []uuid.UUID) ([][]*models.URL, []error) {
+				qb := models.NewGroupQueryBuilder(nil)
+				return qb.GetAllUrls(ids)
+			},
+		},
+		SceneById: SceneLoader{
+			maxBatch: 100,
+			wait:     1 * time.Millisecond,
+			fetch: func(ids []uuid.UUID) ([]*models.Scene, []error) {
+				qb := models.NewSceneQueryBuilder(nil)
+				return qb.FindByIds(ids)
+			},
+		},
+		SceneGroupsById: GroupScenesLoader{
+			maxBatch: 100,
+			wait:     1 * time.Millisecond,
+			fetch: func(ids []uuid.UUID) ([]models.GroupScenes, []error) {
+				qb := models.NewGroupQueryBuilder(nil)
+				return qb.GetAllSceneGroups(ids)
+			},
+		},
 		SceneFingerprintsById: FingerprintsLoader{
 			maxBatch: 100,
 			wait:     1 * time.Millisecond,
@@ -63,6 +127,22 @@ func GetLoaders() *Loaders {
 				return qb.FindByIds(ids)
 			},
 		},
+		PerformerStatsById: PerformerStatsLoader{
+			maxBatch: 100,
+			wait:     1 * time.Millisecond,
+			fetch: func(ids []uuid.UUID) ([]*models.PerformerStats, []error) {
+				qb := models.NewPerformerQueryBuilder(nil)
+				return qb.GetAllStats(ids)
+			},
+		},
+		StudioById: StudioLoader{
+			maxBatch: 100,
+			wait:     1 * time.Millisecond,
+			fetch: func(ids []uuid.UUID) ([]*models.Studio, []error) {
+				qb := models.NewStudioQueryBuilder(nil)
+				return qb.FindByIds(ids)
+			},
+		},
 		SceneImageIDsById: UUIDsLoader{
 			maxBatch: 100,
 			wait:     1 * time.Millisecond,
@@ -143,6 +223,30 @@ func GetLoaders() *Loaders {
 				return qb.FindByIds(ids)
 			},
 		},
+		StudioAliasesById: StringsLoader{
+			maxBatch: 100,
+			wait:     1 * time.Millisecond,
+			fetch: func(ids []uuid.UUID) ([][]string, []error) {
+				qb := models.NewStudioQueryBuilder(nil)
+				return qb.GetAllAliases(ids)
+			},
+		},
+		StudioSceneCountById: IntLoader{
+			maxBatch: 100,
+			wait:     1 * time.Millisecond,
+			fetch: func(ids []uuid.UUID) ([]int, []error) {
+				qb := models.NewStudioQueryBuilder(nil)
+				return qb.GetAllSceneCounts(ids)
+			},
+		},
+		NetworkSceneCountById: IntLoader{
+			maxBatch: 100,
+			wait:     1 * time.Millisecond,
+			fetch: func(ids []uuid.UUID) ([]int, []error) {
+				qb := models.NewStudioQueryBuilder(nil)
+				return qb.GetAllNetworkSceneCounts(ids)
+			},
+		},
 		StudioImageIDsById: UUIDsLoader{
 			maxBatch: 100,
 			wait:     1 * time.Millisecond,
@@ -159,6 +263,14 @@ func GetLoaders() *Loaders {
 				return qb.FindIdsBySceneIds(ids)
 			},
 		},
+		SceneImpliedTagIDsById: UUIDsLoader{
+			maxBatch: 100,
+			wait:     1 * time.Millisecond,
+			fetch: func(ids []uuid.UUID) ([][]uuid.UUID, []error) {
+				qb := models.NewTagQueryBuilder(nil)
+				return qb.FindImpliedIdsBySceneIds(ids)
+			},
+		},
 		TagById: TagLoader{
 			maxBatch: 1000,
 			wait:     1 * time.Millisecond,
@@ -175,5 +287,37 @@ func GetLoaders() *Loaders {
 				return qb.FindByIds(ids)
 			},
 		},
+		TagChildIDsById: UUIDsLoader{
+			maxBatch: 100,
+			wait:     1 * time.Millisecond,
+			fetch: func(ids []uuid.UUID) ([][]uuid.UUID, []error) {
+				qb := models.NewTagQueryBuilder(nil)
+				return qb.FindChildIdsByIds(ids)
+			},
+		},
+		TagParentIDsById: UUIDsLoader{
+			maxBatch: 100,
+			wait:     1 * time.Millisecond,
+			fetch: func(ids []uuid.UUID) ([][]uuid.UUID, []error) {
+				qb := models.NewTagQueryBuilder(nil)
+				return qb.FindParentIdsByIds(ids)
+			},
+		},
+		TagSceneCountById: IntLoader{
+			maxBatch: 100,
+			wait:     1 * time.Millisecond,
+			fetch: func(ids []uuid.UUID) ([]int, []error) {
+				qb := models.NewTagQueryBuilder(nil)
+				return qb.GetAllSceneCounts(ids)
+			},
+		},
+		SiteById: SiteLoader{
+			maxBatch: 1000,
+			wait:     1 * time.Millisecond,
+			fetch: func(ids []uuid.UUID) ([]*models.Site, []error) {
+				qb := models.NewSiteQueryBuilder(nil)
+				return qb.FindByIds(ids)
+			},
+		},
 	}
 }
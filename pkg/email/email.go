@@ -56,6 +56,23 @@ func (m *Manager) Send(email, subject, body string) error {
 		return err
 	}
 
+	if err := m.send(email, subject, body); err != nil {
+		return err
+	}
+
+	// add to email map
+	m.lastEmailed[email] = time.Now()
+
+	return nil
+}
+
+// SendNotification sends an email that the user has opted in to. Unlike Send,
+// it is not subject to or counted towards the email cooldown.
+func (m *Manager) SendNotification(email, subject, body string) error {
+	return m.send(email, subject, body)
+}
+
+func (m *Manager) send(email, subject, body string) error {
 	if len(config.GetMissingEmailSettings()) > 0 {
 		return errors.New("email settings not configured")
 	}
@@ -67,14 +84,5 @@ func (m *Manager) Send(email, subject, body string) error {
 
 	msg := []byte(from + endLine + to + endLine + subject + endLine + endLine + body + endLine)
 
-	err = smtp.SendMail(config.GetEmailHost()+":"+port, m.makeAuth(), config.GetEmailFrom(), []string{email}, msg)
-
-	if err != nil {
-		return err
-	}
-
-	// add to email map
-	m.lastEmailed[email] = time.Now()
-
-	return nil
+	return smtp.SendMail(config.GetEmailHost()+":"+port, m.makeAuth(), config.GetEmailFrom(), []string{email}, msg)
 }
@@ -0,0 +1,30 @@
+package manager
+
+import (
+	"context"
+	"time"
+
+	"github.com/stashapp/stash-box/pkg/logger"
+	"github.com/stashapp/stash-box/pkg/manager/config"
+	"github.com/stashapp/stash-box/pkg/user"
+)
+
+// StartAPICallFlushJob periodically writes the counted API calls to the
+// database, at the interval set in the configuration.
+func StartAPICallFlushJob() {
+	interval := config.GetAPICallFlushInterval()
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if err := user.FlushAPICalls(context.Background()); err != nil {
+				logger.Errorf("Error writing API call counts: %s", err.Error())
+			}
+		}
+	}()
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
@@ -45,6 +46,56 @@ const activationExpiryDefault = 2 * 60 * 60
 // 5 minutes
 const emailCooldownDefault = 5 * 60
 
+// Interval in seconds between searches for duplicate performers. Set to 0 to
+// disable.
+const PerformerDuplicateInterval = "performer_duplicate_interval"
+
+// 24 hours
+const performerDuplicateIntervalDefault = 24 * 60 * 60
+
+// Period in seconds over which the API calls of each user are counted
+const APICallWindow = "api_call_window"
+
+// 30 days
+const apiCallWindowDefault = 30 * 24 * 60 * 60
+
+// Interval in seconds between writes of the counted API calls to the
+// database
+const APICallFlushInterval = "api_call_flush_interval"
+
+// 1 minute
+const apiCallFlushIntervalDefault = 60
+
+// Interval in seconds between notification digest emails. Set to 0 to
+// disable.
+const NotificationDigestInterval = "notification_digest_interval"
+
+// 24 hours
+const notificationDigestIntervalDefault = 24 * 60 * 60
+
+// Roles that are only granted to users with two-factor authentication
+// enabled
+const TOTPRequiredRoles = "totp_required_roles"
+
+// Issuer shown by authenticator apps for two-factor authentication codes
+const TOTPIssuer = "totp_issuer"
+
+const totpIssuerDefault = "stash-box"
+
+// Time in seconds after which an unused login session expires. The expiry is
+// extended whenever the session is used.
+const SessionExpiry = "session_expiry"
+
+// 1 hour
+const sessionExpiryDefault = 60 * 60
+
+// Rate limits of the GraphQL API, keyed by role. Requests without a user use
+// the "anonymous" key.
+const RateLimits = "rate_limits"
+
+// AnonymousRateLimit is the rate limit key for requests without a user.
+const AnonymousRateLimit = "ANONYMOUS"
+
 // Email settings
 const EmailHost = "email_host"
 const EmailPort = "email_port"
@@ -161,6 +212,75 @@ func GetEmailCooldown() time.Duration {
 	return time.Duration(ret * int(time.Second))
 }
 
+// GetPerformerDuplicateInterval returns the duration between searches for
+// duplicate performers. A zero duration disables the search.
+func GetPerformerDuplicateInterval() time.Duration {
+	ret := performerDuplicateIntervalDefault
+	if viper.IsSet(PerformerDuplicateInterval) {
+		ret = viper.GetInt(PerformerDuplicateInterval)
+	}
+
+	return time.Duration(ret * int(time.Second))
+}
+
+// GetAPICallWindow returns the period over which the API calls of each user
+// are counted.
+func GetAPICallWindow() time.Duration {
+	ret := apiCallWindowDefault
+	if viper.IsSet(APICallWindow) {
+		ret = viper.GetInt(APICallWindow)
+	}
+
+	return time.Duration(ret * int(time.Second))
+}
+
+// GetAPICallFlushInterval returns the duration between writes of the counted
+// API calls to the database.
+func GetAPICallFlushInterval() time.Duration {
+	ret := apiCallFlushIntervalDefault
+	if viper.IsSet(APICallFlushInterval) {
+		ret = viper.GetInt(APICallFlushInterval)
+	}
+
+	return time.Duration(ret * int(time.Second))
+}
+
+// GetNotificationDigestInterval returns the duration between notification
+// digest emails. A zero duration disables the digest.
+func GetNotificationDigestInterval() time.Duration {
+	ret := notificationDigestIntervalDefault
+	if viper.IsSet(NotificationDigestInterval) {
+		ret = viper.GetInt(NotificationDigestInterval)
+	}
+
+	return time.Duration(ret * int(time.Second))
+}
+
+// GetTOTPRequiredRoles returns the roles that are only granted to users with
+// two-factor authentication enabled.
+func GetTOTPRequiredRoles() []string {
+	return viper.GetStringSlice(TOTPRequiredRoles)
+}
+
+// GetTOTPIssuer returns the issuer of two-factor authentication codes.
+func GetTOTPIssuer() string {
+	if viper.IsSet(TOTPIssuer) {
+		return viper.GetString(TOTPIssuer)
+	}
+	return totpIssuerDefault
+}
+
+// GetSessionExpiry returns the duration after which an unused login session
+// expires.
+func GetSessionExpiry() time.Duration {
+	ret := sessionExpiryDefault
+	if viper.IsSet(SessionExpiry) {
+		ret = viper.GetInt(SessionExpiry)
+	}
+
+	return time.Duration(ret * int(time.Second))
+}
+
 // GetDefaultUserRoles returns the default roles assigned to a new user
 // when created via registration.
 func GetDefaultUserRoles() []string {
@@ -243,6 +363,74 @@ func GetS3Config() *S3Config {
 	return nil
 }
 
+const OIDC = "oidc"
+
+// OIDCConfig configures login with an OpenID Connect identity provider.
+type OIDCConfig struct {
+	Issuer       string `mapstructure:"issuer"`
+	ClientID     string `mapstructure:"client_id"`
+	ClientSecret string `mapstructure:"client_secret"`
+	// Defaults to the /oidc/callback path of host_url
+	RedirectURL string   `mapstructure:"redirect_url"`
+	Scopes      []string `mapstructure:"scopes"`
+	// Create users for identities without an account
+	AutoProvision bool `mapstructure:"auto_provision"`
+}
+
+// GetOIDCConfig returns the OpenID Connect configuration, or nil if OpenID
+// Connect login is not configured.
+func GetOIDCConfig() *OIDCConfig {
+	if !viper.IsSet(OIDC) {
+		return nil
+	}
+
+	var config OIDCConfig
+	if err := viper.UnmarshalKey(OIDC, &config); err != nil {
+		logger.Errorf("Error reading OIDC config: %s", err.Error())
+		return nil
+	}
+
+	if config.Issuer == "" {
+		return nil
+	}
+
+	if config.RedirectURL == "" {
+		config.RedirectURL = strings.TrimSuffix(GetHostURL(), "/") + "/oidc/callback"
+	}
+
+	return &config
+}
+
+// RateLimit is a token bucket rate limit.
+type RateLimit struct {
+	// Requests per second added to the bucket
+	Rate float64 `mapstructure:"rate"`
+	// Maximum number of requests that can be made at once
+	Burst int `mapstructure:"burst"`
+}
+
+// GetRateLimits returns the configured rate limits keyed by upper case role
+// name, or AnonymousRateLimit. Returns nil if rate limiting is not
+// configured.
+func GetRateLimits() map[string]RateLimit {
+	if !viper.IsSet(RateLimits) {
+		return nil
+	}
+
+	var limits map[string]RateLimit
+	if err := viper.UnmarshalKey(RateLimits, &limits); err != nil {
+		logger.Errorf("Error reading rate limit config: %s", err.Error())
+		return nil
+	}
+
+	// viper keys are case insensitive
+	ret := make(map[string]RateLimit)
+	for k, v := range limits {
+		ret[strings.ToUpper(k)] = v
+	}
+	return ret
+}
+
 // ValidateImageLocation returns an error is image_location is not set.
 func ValidateImageLocation() error {
 	if GetImageLocation() == "" {
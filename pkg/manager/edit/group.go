@@ -0,0 +1,130 @@
+package edit
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+func ModifyGroupEdit(tx *sqlx.Tx, edit *models.Edit, input models.GroupEditInput, inputSpecified InputSpecifiedFunc) error {
+	gqb := models.NewGroupQueryBuilder(tx)
+
+	// get the existing group
+	groupID, _ := uuid.FromString(*input.Edit.ID)
+	group, err := gqb.Find(groupID)
+
+	if err != nil {
+		return err
+	}
+
+	if group == nil {
+		return errors.New("group with id " + groupID.String() + " not found")
+	}
+
+	// perform a diff against the input and the current object
+	groupEdit := input.Details.GroupEditFromDiff(*group)
+
+	if len(input.Details.Urls) != 0 || inputSpecified("urls") {
+		if err := ValidateURLs(tx, input.Details.Urls, models.ValidSiteTypeEnumGroup); err != nil {
+			return err
+		}
+		urls, err := gqb.GetUrls(groupID)
+		if err != nil {
+			return err
+		}
+		groupEdit.New.AddedUrls, groupEdit.New.RemovedUrls = URLCompare(input.Details.Urls, urls.ToURLs())
+	}
+
+	if len(input.Details.Scenes) != 0 || inputSpecified("scenes") {
+		scenes, err := gqb.GetScenes(groupID)
+		if err != nil {
+			return err
+		}
+		groupEdit.New.AddedScenes, groupEdit.New.RemovedScenes = GroupSceneCompare(input.Details.Scenes, scenes.ToGroupSceneInputs())
+	}
+
+	edit.SetData(groupEdit)
+	return nil
+}
+
+func CreateGroupEdit(tx *sqlx.Tx, edit *models.Edit, input models.GroupEditInput, inputSpecified InputSpecifiedFunc) error {
+	groupEdit := input.Details.GroupEditFromCreate()
+
+	if len(input.Details.Urls) != 0 || inputSpecified("urls") {
+		if err := ValidateURLs(tx, input.Details.Urls, models.ValidSiteTypeEnumGroup); err != nil {
+			return err
+		}
+		groupEdit.New.AddedUrls = input.Details.Urls
+	}
+
+	if len(input.Details.Scenes) != 0 || inputSpecified("scenes") {
+		groupEdit.New.AddedScenes = input.Details.Scenes
+	}
+
+	edit.SetData(groupEdit)
+	return nil
+}
+
+func DestroyGroupEdit(tx *sqlx.Tx, edit *models.Edit, input models.GroupEditInput, inputSpecified InputSpecifiedFunc) error {
+	gqb := models.NewGroupQueryBuilder(tx)
+
+	// get the existing group
+	groupID, _ := uuid.FromString(*input.Edit.ID)
+	group, err := gqb.Find(groupID)
+
+	if err != nil {
+		return err
+	}
+
+	if group == nil {
+		return errors.New("group with id " + groupID.String() + " not found")
+	}
+
+	return nil
+}
+
+func groupSceneKey(s *models.GroupSceneInput) string {
+	if s.SceneNumber != nil {
+		return s.SceneID + ":" + strconv.Itoa(*s.SceneNumber)
+	}
+	return s.SceneID
+}
+
+// GroupSceneCompare returns the scene entries in subject that are not in
+// against, and the entries in against that are not in subject. An entry
+// whose scene number has changed is returned in both.
+func GroupSceneCompare(subject []*models.GroupSceneInput, against []*models.GroupSceneInput) (added []*models.GroupSceneInput, missing []*models.GroupSceneInput) {
+	subjectKeys := make(map[string]bool)
+	for _, s := range subject {
+		subjectKeys[groupSceneKey(s)] = true
+	}
+
+	againstKeys := make(map[string]bool)
+	for _, a := range against {
+		againstKeys[groupSceneKey(a)] = true
+	}
+
+	addedKeys := make(map[string]bool)
+	for _, s := range subject {
+		key := groupSceneKey(s)
+		if !againstKeys[key] && !addedKeys[key] {
+			added = append(added, s)
+			addedKeys[key] = true
+		}
+	}
+
+	missingKeys := make(map[string]bool)
+	for _, a := range against {
+		key := groupSceneKey(a)
+		if !subjectKeys[key] && !missingKeys[key] {
+			missing = append(missing, a)
+			missingKeys[key] = true
+		}
+	}
+
+	return
+}
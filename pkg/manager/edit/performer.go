@@ -46,6 +46,9 @@ func ModifyPerformerEdit(tx *sqlx.Tx, edit *models.Edit, input models.PerformerE
 	}
 	performerEdit.New.AddedPiercings, performerEdit.New.RemovedPiercings = BodyModCompare(input.Details.Piercings, piercings.ToBodyModifications())
 
+	if err := ValidateURLs(tx, input.Details.Urls, models.ValidSiteTypeEnumPerformer); err != nil {
+		return err
+	}
 	urls, err := pqb.GetUrls(performerID)
 	if err != nil {
 		return err
@@ -132,6 +135,9 @@ func MergePerformerEdit(tx *sqlx.Tx, edit *models.Edit, input models.PerformerEd
 	}
 	performerEdit.New.AddedPiercings, performerEdit.New.RemovedPiercings = BodyModCompare(input.Details.Piercings, piercings.ToBodyModifications())
 
+	if err := ValidateURLs(tx, input.Details.Urls, models.ValidSiteTypeEnumPerformer); err != nil {
+		return err
+	}
 	urls, err := pqb.GetUrls(performerID)
 	if err != nil {
 		return err
@@ -177,6 +183,9 @@ func CreatePerformerEdit(tx *sqlx.Tx, edit *models.Edit, input models.PerformerE
 	}
 
 	if len(input.Details.Urls) != 0 || inputSpecified("urls") {
+		if err := ValidateURLs(tx, input.Details.Urls, models.ValidSiteTypeEnumPerformer); err != nil {
+			return err
+		}
 		performerEdit.New.AddedUrls = input.Details.Urls
 	}
 
@@ -0,0 +1,139 @@
+package edit
+
+import (
+	"errors"
+
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/stashapp/stash-box/pkg/models"
+	"github.com/stashapp/stash-box/pkg/utils"
+)
+
+func ModifyStudioEdit(tx *sqlx.Tx, edit *models.Edit, input models.StudioEditInput, inputSpecified InputSpecifiedFunc) error {
+	sqb := models.NewStudioQueryBuilder(tx)
+
+	// get the existing studio
+	studioID, _ := uuid.FromString(*input.Edit.ID)
+	studio, err := sqb.Find(studioID)
+
+	if err != nil {
+		return err
+	}
+
+	if studio == nil {
+		return errors.New("studio with id " + studioID.String() + " not found")
+	}
+
+	// perform a diff against the input and the current object
+	studioEdit := input.Details.StudioEditFromDiff(*studio)
+
+	aliases, err := sqb.GetAliases(studioID)
+	if err != nil {
+		return err
+	}
+	newAliases := aliases.ToAliases()
+	if len(input.Details.Aliases) != 0 || inputSpecified("aliases") {
+		studioEdit.New.AddedAliases, studioEdit.New.RemovedAliases = utils.StrSliceCompare(input.Details.Aliases, aliases.ToAliases())
+		newAliases = input.Details.Aliases
+	}
+
+	name := studio.Name
+	if input.Details.Name != nil {
+		name = *input.Details.Name
+	}
+	if err := sqb.ValidateNames(studioID, name, newAliases); err != nil {
+		return err
+	}
+
+	if len(input.Details.Urls) != 0 || inputSpecified("urls") {
+		if err := ValidateURLs(tx, input.Details.Urls, models.ValidSiteTypeEnumStudio); err != nil {
+			return err
+		}
+		urls, err := sqb.GetUrls(studioID)
+		if err != nil {
+			return err
+		}
+		studioEdit.New.AddedUrls, studioEdit.New.RemovedUrls = URLCompare(input.Details.Urls, urls.ToURLs())
+	}
+
+	if len(input.Details.ChildStudioIds) != 0 || inputSpecified("child_studio_ids") {
+		children, err := sqb.FindByParentID(studioID)
+		if err != nil {
+			return err
+		}
+
+		existingChildren := []string{}
+		for _, child := range children {
+			existingChildren = append(existingChildren, child.ID.String())
+		}
+		studioEdit.New.AddedChildStudios, studioEdit.New.RemovedChildStudios = utils.StrSliceCompare(input.Details.ChildStudioIds, existingChildren)
+	}
+
+	if len(input.Details.ImageIds) != 0 || inputSpecified("image_ids") {
+		images, err := sqb.GetImages(studioID)
+		if err != nil {
+			return err
+		}
+
+		existingImages := []string{}
+		for _, image := range images {
+			existingImages = append(existingImages, image.ID())
+		}
+		studioEdit.New.AddedImages, studioEdit.New.RemovedImages = utils.StrSliceCompare(input.Details.ImageIds, existingImages)
+	}
+
+	edit.SetData(studioEdit)
+	return nil
+}
+
+func CreateStudioEdit(tx *sqlx.Tx, edit *models.Edit, input models.StudioEditInput, inputSpecified InputSpecifiedFunc) error {
+	studioEdit := input.Details.StudioEditFromCreate()
+
+	if studioEdit.New.Name != nil {
+		sqb := models.NewStudioQueryBuilder(tx)
+		if err := sqb.ValidateNames(uuid.Nil, *studioEdit.New.Name, input.Details.Aliases); err != nil {
+			return err
+		}
+	}
+
+	if len(input.Details.Aliases) != 0 || inputSpecified("aliases") {
+		studioEdit.New.AddedAliases = input.Details.Aliases
+	}
+
+	if len(input.Details.Urls) != 0 || inputSpecified("urls") {
+		if err := ValidateURLs(tx, input.Details.Urls, models.ValidSiteTypeEnumStudio); err != nil {
+			return err
+		}
+		studioEdit.New.AddedUrls = input.Details.Urls
+	}
+
+	if len(input.Details.ChildStudioIds) != 0 || inputSpecified("child_studio_ids") {
+		studioEdit.New.AddedChildStudios = input.Details.ChildStudioIds
+	}
+
+	if len(input.Details.ImageIds) != 0 || inputSpecified("image_ids") {
+		studioEdit.New.AddedImages = input.Details.ImageIds
+	}
+
+	edit.SetData(studioEdit)
+	return nil
+}
+
+func DestroyStudioEdit(tx *sqlx.Tx, edit *models.Edit, input models.StudioEditInput, inputSpecified InputSpecifiedFunc) error {
+	sqb := models.NewStudioQueryBuilder(tx)
+
+	// get the existing studio
+	studioID, _ := uuid.FromString(*input.Edit.ID)
+	studio, err := sqb.Find(studioID)
+
+	if err != nil {
+		return err
+	}
+
+	if studio == nil {
+		return errors.New("studio with id " + studioID.String() + " not found")
+	}
+
+	return nil
+}
@@ -44,6 +44,20 @@ func ModifyTagEdit(tx *sqlx.Tx, edit *models.Edit, input models.TagEditInput, in
 		tagEdit.New.AddedAliases, tagEdit.New.RemovedAliases = utils.StrSliceCompare(input.Details.Aliases, aliases)
 	}
 
+	// determine unspecified parents vs no parents
+	if len(input.Details.ParentIds) != 0 || inputSpecified("parent_ids") {
+		parents, err := tqb.GetParents(tagID)
+
+		if err != nil {
+			return err
+		}
+
+		tagEdit.New.AddedParents, tagEdit.New.RemovedParents = utils.StrSliceCompare(input.Details.ParentIds, parents.ToParentIDs())
+		if err := tqb.ValidateParents(tagID, tagEdit.New.AddedParents); err != nil {
+			return err
+		}
+	}
+
 	edit.SetData(tagEdit)
 	return nil
 }
@@ -101,6 +115,20 @@ func MergeTagEdit(tx *sqlx.Tx, edit *models.Edit, input models.TagEditInput, inp
 		tagEdit.New.AddedAliases, tagEdit.New.RemovedAliases = utils.StrSliceCompare(input.Details.Aliases, aliases)
 	}
 
+	// determine unspecified parents vs no parents
+	if len(input.Details.ParentIds) != 0 || inputSpecified("parent_ids") {
+		parents, err := tqb.GetParents(tagID)
+
+		if err != nil {
+			return err
+		}
+
+		tagEdit.New.AddedParents, tagEdit.New.RemovedParents = utils.StrSliceCompare(input.Details.ParentIds, parents.ToParentIDs())
+		if err := tqb.ValidateParents(tagID, tagEdit.New.AddedParents); err != nil {
+			return err
+		}
+	}
+
 	edit.SetData(tagEdit)
 	return nil
 }
@@ -113,6 +141,14 @@ func CreateTagEdit(tx *sqlx.Tx, edit *models.Edit, input models.TagEditInput, in
 		tagEdit.New.AddedAliases = input.Details.Aliases
 	}
 
+	if len(input.Details.ParentIds) != 0 {
+		tqb := models.NewTagQueryBuilder(tx)
+		if err := tqb.ValidateParents(uuid.Nil, input.Details.ParentIds); err != nil {
+			return err
+		}
+		tagEdit.New.AddedParents = input.Details.ParentIds
+	}
+
 	edit.SetData(tagEdit)
 	return nil
 }
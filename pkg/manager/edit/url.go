@@ -0,0 +1,14 @@
+package edit
+
+import (
+	"github.com/jmoiron/sqlx"
+
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+// ValidateURLs links the urls to their sites, returning an error if a url is
+// not valid for its site.
+func ValidateURLs(tx *sqlx.Tx, urls []*models.URL, validType models.ValidSiteTypeEnum) error {
+	sqb := models.NewSiteQueryBuilder(tx)
+	return sqb.ResolveURLs(urls, validType)
+}
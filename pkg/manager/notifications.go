@@ -0,0 +1,31 @@
+package manager
+
+import (
+	"context"
+	"time"
+
+	"github.com/stashapp/stash-box/pkg/logger"
+	"github.com/stashapp/stash-box/pkg/manager/config"
+	"github.com/stashapp/stash-box/pkg/user"
+)
+
+// StartNotificationDigestJob periodically emails users their new
+// notifications, at the interval set in the configuration. The job is not
+// started if email is not configured.
+func StartNotificationDigestJob() {
+	interval := config.GetNotificationDigestInterval()
+	if interval <= 0 || len(config.GetMissingEmailSettings()) > 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if err := user.SendNotificationDigests(context.Background(), GetInstance().EmailManager); err != nil {
+				logger.Errorf("Error sending notification digests: %s", err.Error())
+			}
+		}
+	}()
+}
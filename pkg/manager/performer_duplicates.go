@@ -0,0 +1,77 @@
+package manager
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/database"
+	"github.com/stashapp/stash-box/pkg/logger"
+	"github.com/stashapp/stash-box/pkg/manager/config"
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+// RefreshPerformerDuplicates rescores the candidate duplicate performer
+// pairs, one batch of performers per transaction, and removes pending pairs
+// that no longer qualify. Returns the number of pairs meeting the minimum
+// score.
+func RefreshPerformerDuplicates(ctx context.Context) (int, error) {
+	// timestamps are stored to the second
+	now := time.Now().Truncate(time.Second)
+
+	count := 0
+	var after *uuid.UUID
+	for {
+		err := database.WithTransaction(ctx, func(txn database.Transaction) error {
+			qb := models.NewPerformerDuplicateQueryBuilder(txn.GetTx())
+
+			batchCount, last, err := qb.RefreshBatch(after, now)
+			count += batchCount
+			after = last
+			return err
+		})
+		if err != nil {
+			return 0, err
+		}
+
+		if after == nil {
+			break
+		}
+	}
+
+	err := database.WithTransaction(ctx, func(txn database.Transaction) error {
+		qb := models.NewPerformerDuplicateQueryBuilder(txn.GetTx())
+		return qb.DestroyStalePending(now)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// StartPerformerDuplicateJob periodically refreshes the duplicate performer
+// candidates, at the interval set in the configuration.
+func StartPerformerDuplicateJob() {
+	interval := config.GetPerformerDuplicateInterval()
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			start := time.Now()
+			count, err := RefreshPerformerDuplicates(context.Background())
+			if err != nil {
+				logger.Errorf("Error refreshing duplicate performers: %s", err.Error())
+				continue
+			}
+
+			logger.Infof("Found %d duplicate performer candidates in %s", count, time.Since(start))
+		}
+	}()
+}
@@ -2,6 +2,8 @@ package models
 
 import (
 	"errors"
+
+	"github.com/gofrs/uuid"
 )
 
 func (e TagEditDetailsInput) TagEditFromDiff(orig Tag) TagEditData {
@@ -338,6 +340,123 @@ func (e PerformerEditDetailsInput) PerformerEditFromCreate() PerformerEditData {
 	}
 }
 
+func (e GroupEditDetailsInput) GroupEditFromDiff(orig Group) GroupEditData {
+	newData := &GroupEdit{}
+	oldData := &GroupEdit{}
+
+	if e.Title != nil && *e.Title != orig.Title {
+		newTitle := *e.Title
+		newData.Title = &newTitle
+		oldData.Title = &orig.Title
+	}
+
+	if e.Date == nil && orig.Date.Valid {
+		oldData.Date = &orig.Date.String
+	} else if e.Date != nil && (!orig.Date.Valid || *e.Date != orig.Date.String) {
+		newDate := *e.Date
+		newData.Date = &newDate
+		if orig.Date.Valid {
+			oldData.Date = &orig.Date.String
+		}
+	}
+
+	newData.StudioID, oldData.StudioID = diffNullUUID(e.StudioID, orig.StudioID)
+	newData.FrontImageID, oldData.FrontImageID = diffNullUUID(e.FrontImageID, orig.FrontImageID)
+	newData.BackImageID, oldData.BackImageID = diffNullUUID(e.BackImageID, orig.BackImageID)
+
+	return GroupEditData{
+		New: newData,
+		Old: oldData,
+	}
+}
+
+func (e GroupEditDetailsInput) GroupEditFromCreate() GroupEditData {
+	newData := &GroupEdit{}
+
+	if e.Title != nil {
+		newTitle := *e.Title
+		newData.Title = &newTitle
+	}
+
+	if e.Date != nil {
+		newDate := *e.Date
+		newData.Date = &newDate
+	}
+
+	if e.StudioID != nil {
+		newStudio := *e.StudioID
+		newData.StudioID = &newStudio
+	}
+
+	if e.FrontImageID != nil {
+		newFrontImage := *e.FrontImageID
+		newData.FrontImageID = &newFrontImage
+	}
+
+	if e.BackImageID != nil {
+		newBackImage := *e.BackImageID
+		newData.BackImageID = &newBackImage
+	}
+
+	return GroupEditData{
+		New: newData,
+	}
+}
+
+func (e StudioEditDetailsInput) StudioEditFromDiff(orig Studio) StudioEditData {
+	newData := &StudioEdit{}
+	oldData := &StudioEdit{}
+
+	if e.Name != nil && *e.Name != orig.Name {
+		newName := *e.Name
+		newData.Name = &newName
+		oldData.Name = &orig.Name
+	}
+
+	newData.ParentID, oldData.ParentID = diffNullUUID(e.ParentID, orig.ParentStudioID)
+
+	return StudioEditData{
+		New: newData,
+		Old: oldData,
+	}
+}
+
+func (e StudioEditDetailsInput) StudioEditFromCreate() StudioEditData {
+	newData := &StudioEdit{}
+
+	if e.Name != nil {
+		newName := *e.Name
+		newData.Name = &newName
+	}
+
+	if e.ParentID != nil {
+		newParent := *e.ParentID
+		newData.ParentID = &newParent
+	}
+
+	return StudioEditData{
+		New: newData,
+	}
+}
+
+// diffNullUUID returns the new and old values of an optional ID field if
+// the input differs from the original value.
+func diffNullUUID(input *string, orig uuid.NullUUID) (newValue *string, oldValue *string) {
+	if orig.Valid {
+		old := orig.UUID.String()
+		if input == nil || *input != old {
+			oldValue = &old
+		}
+	}
+
+	if input != nil && (!orig.Valid || *input != orig.UUID.String()) {
+		newID := *input
+		newValue = &newID
+	}
+
+	return
+}
+
 type EditSliceValue interface {
 	ID() string
 }
@@ -2,6 +2,7 @@ package models
 
 import (
 	"bytes"
+	"database/sql"
 	"encoding/json"
 	"time"
 
@@ -14,8 +15,6 @@ import (
 const (
 	editTable   = "edits"
 	editJoinKey = "edit_id"
-
-	//voteTable = "votes"
 )
 
 var (
@@ -31,13 +30,21 @@ var (
 		return &EditPerformer{}
 	})
 
+	editStudioTable = database.NewTableJoin(editTable, "studio_edits", editJoinKey, func() interface{} {
+		return &EditStudio{}
+	})
+
+	editGroupTable = database.NewTableJoin(editTable, "group_edits", editJoinKey, func() interface{} {
+		return &EditGroup{}
+	})
+
 	editCommentTable = database.NewTableJoin(editTable, "edit_comments", editJoinKey, func() interface{} {
 		return &EditComment{}
 	})
 
-	// voteDBTable = database.NewTable(editTable, func() interface{} {
-	// 	return &Edit{}
-	// })
+	editVoteTable = database.NewTableJoin(editTable, "edit_votes", editJoinKey, func() interface{} {
+		return &VoteComment{}
+	})
 )
 
 type Edit struct {
@@ -110,6 +117,16 @@ func (p *Edit) ImmediateReject() {
 	p.UpdatedAt = SQLiteTimestamp{Timestamp: time.Now()}
 }
 
+// IsAccepted returns true if the edit was closed by accepting it.
+func (p Edit) IsAccepted() bool {
+	return p.Status == VoteStatusEnumAccepted.String() || p.Status == VoteStatusEnumImmediateAccepted.String()
+}
+
+// IsRejected returns true if the edit was closed by rejecting it.
+func (p Edit) IsRejected() bool {
+	return p.Status == VoteStatusEnumRejected.String() || p.Status == VoteStatusEnumImmediateRejected.String()
+}
+
 func (e *Edit) SetData(data interface{}) error {
 	buffer := &bytes.Buffer{}
 	encoder := json.NewEncoder(buffer)
@@ -143,6 +160,18 @@ func (e *Edit) GetPerformerData() (*PerformerEditData, error) {
 	return &data, nil
 }
 
+func (e *Edit) GetStudioData() (*StudioEditData, error) {
+	data := StudioEditData{}
+	_ = json.Unmarshal(e.Data, &data)
+	return &data, nil
+}
+
+func (e *Edit) GetGroupData() (*GroupEditData, error) {
+	data := GroupEditData{}
+	_ = json.Unmarshal(e.Data, &data)
+	return &data, nil
+}
+
 type Edits []*Edit
 
 func (p Edits) Each(fn func(interface{})) {
@@ -189,14 +218,76 @@ func (p *EditPerformers) Add(o interface{}) {
 	*p = append(*p, o.(*EditPerformer))
 }
 
-// type VoteComment struct {
-// 	ID      uuid.UUID      `db:"id" json:"id"`
-// 	EditID  uuid.UUID      `db:"edit_id" json:"edit_id"`
-// 	UserID  uuid.UUID      `db:"user_id" json:"user_id"`
-// 	Date    SQLiteDate     `db:"date" json:"date"`
-// 	Comment sql.NullString `db:"comment" json:"comment"`
-// 	Type    string         `db:"type" json:"type"`
-// }
+type EditStudio struct {
+	EditID   uuid.UUID `db:"edit_id" json:"edit_id"`
+	StudioID uuid.UUID `db:"studio_id" json:"studio_id"`
+}
+
+type EditStudios []*EditStudio
+
+func (p EditStudios) Each(fn func(interface{})) {
+	for _, v := range p {
+		fn(*v)
+	}
+}
+
+func (p *EditStudios) Add(o interface{}) {
+	*p = append(*p, o.(*EditStudio))
+}
+
+type EditGroup struct {
+	EditID  uuid.UUID `db:"edit_id" json:"edit_id"`
+	GroupID uuid.UUID `db:"group_id" json:"group_id"`
+}
+
+type EditGroups []*EditGroup
+
+func (p EditGroups) Each(fn func(interface{})) {
+	for _, v := range p {
+		fn(*v)
+	}
+}
+
+func (p *EditGroups) Add(o interface{}) {
+	*p = append(*p, o.(*EditGroup))
+}
+
+type VoteComment struct {
+	ID        uuid.UUID       `db:"id" json:"id"`
+	EditID    uuid.UUID       `db:"edit_id" json:"edit_id"`
+	UserID    uuid.NullUUID   `db:"user_id" json:"user_id"`
+	CreatedAt SQLiteTimestamp `db:"created_at" json:"created_at"`
+	Comment   sql.NullString  `db:"comment" json:"comment"`
+	Type      string          `db:"type" json:"type"`
+}
+
+func NewVoteComment(UUID uuid.UUID, user *User, edit *Edit, input EditVoteInput) *VoteComment {
+	ret := &VoteComment{
+		ID:        UUID,
+		EditID:    edit.ID,
+		UserID:    uuid.NullUUID{UUID: user.ID, Valid: true},
+		CreatedAt: SQLiteTimestamp{Timestamp: time.Now()},
+		Type:      input.Type.String(),
+	}
+
+	if input.Comment != nil {
+		ret.Comment = sql.NullString{String: *input.Comment, Valid: true}
+	}
+
+	return ret
+}
+
+type VoteComments []*VoteComment
+
+func (p VoteComments) Each(fn func(interface{})) {
+	for _, v := range p {
+		fn(*v)
+	}
+}
+
+func (p *VoteComments) Add(o interface{}) {
+	*p = append(*p, o.(*VoteComment))
+}
 
 // func (p *Scene) CopyFromCreateInput(input SceneCreateInput) {
 // 	CopyFull(p, input)
@@ -220,6 +311,8 @@ type TagEdit struct {
 	AddedAliases   []string `json:"added_aliases,omitempty"`
 	RemovedAliases []string `json:"removed_aliases,omitempty"`
 	CategoryID     *string  `json:"category_id,omitempty"`
+	AddedParents   []string `json:"added_parents,omitempty"`
+	RemovedParents []string `json:"removed_parents,omitempty"`
 }
 
 func (TagEdit) IsEditDetails() {}
@@ -270,6 +363,47 @@ type PerformerEditData struct {
 	SetMergeAliases  bool           `json:"merge_aliases,omitempty"`
 }
 
+func (StudioEdit) IsEditDetails() {}
+
+type StudioEdit struct {
+	Name                *string  `json:"name,omitempty"`
+	AddedAliases        []string `json:"added_aliases,omitempty"`
+	RemovedAliases      []string `json:"removed_aliases,omitempty"`
+	AddedUrls           []*URL   `json:"added_urls,omitempty"`
+	RemovedUrls         []*URL   `json:"removed_urls,omitempty"`
+	ParentID            *string  `json:"parent_id,omitempty"`
+	AddedChildStudios   []string `json:"added_child_studios,omitempty"`
+	RemovedChildStudios []string `json:"removed_child_studios,omitempty"`
+	AddedImages         []string `json:"added_images,omitempty"`
+	RemovedImages       []string `json:"removed_images,omitempty"`
+}
+
+type StudioEditData struct {
+	New          *StudioEdit `json:"new_data,omitempty"`
+	Old          *StudioEdit `json:"old_data,omitempty"`
+	MergeSources []string    `json:"merge_sources,omitempty"`
+}
+
+func (GroupEdit) IsEditDetails() {}
+
+type GroupEdit struct {
+	Title         *string            `json:"title,omitempty"`
+	StudioID      *string            `json:"studio_id,omitempty"`
+	Date          *string            `json:"date,omitempty"`
+	FrontImageID  *string            `json:"front_image_id,omitempty"`
+	BackImageID   *string            `json:"back_image_id,omitempty"`
+	AddedUrls     []*URL             `json:"added_urls,omitempty"`
+	RemovedUrls   []*URL             `json:"removed_urls,omitempty"`
+	AddedScenes   []*GroupSceneInput `json:"added_scenes,omitempty"`
+	RemovedScenes []*GroupSceneInput `json:"removed_scenes,omitempty"`
+}
+
+type GroupEditData struct {
+	New          *GroupEdit `json:"new_data,omitempty"`
+	Old          *GroupEdit `json:"old_data,omitempty"`
+	MergeSources []string   `json:"merge_sources,omitempty"`
+}
+
 type EditData struct {
 	New          *json.RawMessage `json:"new_data,omitempty"`
 	Old          *json.RawMessage `json:"old_data,omitempty"`
@@ -0,0 +1,327 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/database"
+)
+
+const (
+	groupTable   = "groups"
+	groupJoinKey = "group_id"
+)
+
+var (
+	groupDBTable = database.NewTable(groupTable, func() interface{} {
+		return &Group{}
+	})
+
+	groupUrlTable = database.NewTableJoin(groupTable, "group_urls", groupJoinKey, func() interface{} {
+		return &GroupUrl{}
+	})
+
+	groupSceneTable = database.NewTableJoin(groupTable, "group_scenes", groupJoinKey, func() interface{} {
+		return &GroupScene{}
+	})
+
+	sceneGroupTable = database.NewTableJoin(sceneTable, "group_scenes", sceneJoinKey, func() interface{} {
+		return &GroupScene{}
+	})
+)
+
+type Group struct {
+	ID           uuid.UUID       `db:"id" json:"id"`
+	Title        string          `db:"title" json:"title"`
+	StudioID     uuid.NullUUID   `db:"studio_id,omitempty" json:"studio_id"`
+	Date         SQLiteDate      `db:"date" json:"date"`
+	FrontImageID uuid.NullUUID   `db:"front_image_id,omitempty" json:"front_image_id"`
+	BackImageID  uuid.NullUUID   `db:"back_image_id,omitempty" json:"back_image_id"`
+	CreatedAt    SQLiteTimestamp `db:"created_at" json:"created_at"`
+	UpdatedAt    SQLiteTimestamp `db:"updated_at" json:"updated_at"`
+	Deleted      bool            `db:"deleted" json:"deleted"`
+}
+
+func (Group) GetTable() database.Table {
+	return groupDBTable
+}
+
+func (p Group) GetID() uuid.UUID {
+	return p.ID
+}
+
+type Groups []*Group
+
+func (p Groups) Each(fn func(interface{})) {
+	for _, v := range p {
+		fn(*v)
+	}
+}
+
+func (p *Groups) Add(o interface{}) {
+	*p = append(*p, o.(*Group))
+}
+
+type GroupUrl struct {
+	GroupID uuid.UUID     `db:"group_id" json:"group_id"`
+	URL     string        `db:"url" json:"url"`
+	Type    string        `db:"type" json:"type"`
+	SiteID  uuid.NullUUID `db:"site_id" json:"site_id"`
+}
+
+func (p GroupUrl) ID() string {
+	return p.URL + p.Type
+}
+
+func (p *GroupUrl) ToURL() URL {
+	url := URL{
+		URL:    p.URL,
+		Type:   p.Type,
+		SiteID: siteIDString(p.SiteID),
+	}
+	return url
+}
+
+type GroupUrls []*GroupUrl
+
+func (p GroupUrls) Each(fn func(interface{})) {
+	for _, v := range p {
+		fn(*v)
+	}
+}
+
+func (p GroupUrls) EachPtr(fn func(interface{})) {
+	for _, v := range p {
+		fn(v)
+	}
+}
+
+func (p *GroupUrls) Add(o interface{}) {
+	*p = append(*p, o.(*GroupUrl))
+}
+
+func (p *GroupUrls) Remove(id string) {
+	for i, v := range *p {
+		if (*v).ID() == id {
+			(*p)[i] = (*p)[len(*p)-1]
+			*p = (*p)[:len(*p)-1]
+			break
+		}
+	}
+}
+
+func (p GroupUrls) ToURLs() []*URL {
+	var ret []*URL
+	for _, v := range p {
+		url := v.ToURL()
+		ret = append(ret, &url)
+	}
+	return ret
+}
+
+func CreateGroupUrls(groupID uuid.UUID, urls []*URLInput) GroupUrls {
+	var ret GroupUrls
+
+	for _, urlInput := range urls {
+		ret = append(ret, &GroupUrl{
+			GroupID: groupID,
+			URL:     urlInput.URL,
+			Type:    urlInput.Type,
+			SiteID:  urlSiteID(urlInput),
+		})
+	}
+
+	return ret
+}
+
+type GroupScene struct {
+	GroupID     uuid.UUID     `db:"group_id" json:"group_id"`
+	SceneID     uuid.UUID     `db:"scene_id" json:"scene_id"`
+	SceneNumber sql.NullInt64 `db:"scene_number" json:"scene_number"`
+}
+
+// ID returns a key identifying the scene and its position within the group,
+// so that a change of scene number is treated as a removal and an addition.
+func (p GroupScene) ID() string {
+	if p.SceneNumber.Valid {
+		return fmt.Sprintf("%s:%d", p.SceneID.String(), p.SceneNumber.Int64)
+	}
+	return p.SceneID.String()
+}
+
+type GroupScenes []*GroupScene
+
+func (p GroupScenes) Each(fn func(interface{})) {
+	for _, v := range p {
+		fn(*v)
+	}
+}
+
+func (p GroupScenes) EachPtr(fn func(interface{})) {
+	for _, v := range p {
+		fn(v)
+	}
+}
+
+func (p *GroupScenes) Add(o interface{}) {
+	*p = append(*p, o.(*GroupScene))
+}
+
+func (p *GroupScenes) Remove(id string) {
+	for i, v := range *p {
+		if (*v).ID() == id {
+			(*p)[i] = (*p)[len(*p)-1]
+			*p = (*p)[:len(*p)-1]
+			break
+		}
+	}
+}
+
+// Sort orders the scenes by scene number. Scenes without a number are
+// placed last.
+func (p GroupScenes) Sort() {
+	sort.SliceStable(p, func(i, j int) bool {
+		if !p[i].SceneNumber.Valid {
+			return false
+		}
+		if !p[j].SceneNumber.Valid {
+			return true
+		}
+		return p[i].SceneNumber.Int64 < p[j].SceneNumber.Int64
+	})
+}
+
+func (p GroupScenes) ToGroupSceneInputs() []*GroupSceneInput {
+	var ret []*GroupSceneInput
+	for _, v := range p {
+		input := &GroupSceneInput{
+			SceneID: v.SceneID.String(),
+		}
+		if v.SceneNumber.Valid {
+			sceneNumber := int(v.SceneNumber.Int64)
+			input.SceneNumber = &sceneNumber
+		}
+		ret = append(ret, input)
+	}
+	return ret
+}
+
+func CreateGroupScenes(groupID uuid.UUID, scenes []*GroupSceneInput) GroupScenes {
+	var ret GroupScenes
+
+	for _, s := range scenes {
+		sceneID, _ := uuid.FromString(s.SceneID)
+		join := &GroupScene{
+			GroupID: groupID,
+			SceneID: sceneID,
+		}
+
+		if s.SceneNumber != nil {
+			join.SceneNumber = sql.NullInt64{Int64: int64(*s.SceneNumber), Valid: true}
+		}
+
+		ret = append(ret, join)
+	}
+
+	return ret
+}
+
+func (p *Group) IsEditTarget() {
+}
+
+func (p *Group) setDate(date string) {
+	p.Date = SQLiteDate{String: date, Valid: true}
+}
+
+func parseNullUUID(id *string) uuid.NullUUID {
+	if id != nil {
+		UUID, err := uuid.FromString(*id)
+		if err == nil {
+			return uuid.NullUUID{UUID: UUID, Valid: true}
+		}
+	}
+
+	return uuid.NullUUID{}
+}
+
+func (p *Group) CopyFromCreateInput(input GroupCreateInput) {
+	CopyFull(p, input)
+
+	if input.Date != nil {
+		p.setDate(*input.Date)
+	}
+
+	p.StudioID = parseNullUUID(input.StudioID)
+	p.FrontImageID = parseNullUUID(input.FrontImageID)
+	p.BackImageID = parseNullUUID(input.BackImageID)
+}
+
+func (p *Group) CopyFromUpdateInput(input GroupUpdateInput) {
+	CopyFull(p, input)
+
+	if input.Date != nil {
+		p.setDate(*input.Date)
+	} else {
+		p.Date = SQLiteDate{}
+	}
+
+	p.StudioID = parseNullUUID(input.StudioID)
+	p.FrontImageID = parseNullUUID(input.FrontImageID)
+	p.BackImageID = parseNullUUID(input.BackImageID)
+}
+
+func (p *Group) CopyFromGroupEdit(input GroupEdit, old GroupEdit) {
+	if input.Title != nil {
+		p.Title = *input.Title
+	}
+	if input.Date != nil {
+		p.setDate(*input.Date)
+	} else if old.Date != nil {
+		p.Date = SQLiteDate{}
+	}
+	if input.StudioID != nil {
+		p.StudioID = parseNullUUID(input.StudioID)
+	} else if old.StudioID != nil {
+		p.StudioID = uuid.NullUUID{}
+	}
+	if input.FrontImageID != nil {
+		p.FrontImageID = parseNullUUID(input.FrontImageID)
+	} else if old.FrontImageID != nil {
+		p.FrontImageID = uuid.NullUUID{}
+	}
+	if input.BackImageID != nil {
+		p.BackImageID = parseNullUUID(input.BackImageID)
+	} else if old.BackImageID != nil {
+		p.BackImageID = uuid.NullUUID{}
+	}
+}
+
+func nullUUIDString(id uuid.NullUUID) string {
+	if id.Valid {
+		return id.UUID.String()
+	}
+	return ""
+}
+
+func (p *Group) ValidateModifyEdit(edit GroupEditData) error {
+	if edit.Old.Title != nil && *edit.Old.Title != p.Title {
+		return fmt.Errorf("Invalid title. Expected '%v' but was '%v'", *edit.Old.Title, p.Title)
+	}
+	if edit.Old.Date != nil && *edit.Old.Date != p.Date.String {
+		return fmt.Errorf("Invalid date. Expected '%v' but was '%v'", *edit.Old.Date, p.Date.String)
+	}
+	if edit.Old.StudioID != nil && *edit.Old.StudioID != nullUUIDString(p.StudioID) {
+		return fmt.Errorf("Invalid studio. Expected '%v' but was '%v'", *edit.Old.StudioID, nullUUIDString(p.StudioID))
+	}
+	if edit.Old.FrontImageID != nil && *edit.Old.FrontImageID != nullUUIDString(p.FrontImageID) {
+		return fmt.Errorf("Invalid front image. Expected '%v' but was '%v'", *edit.Old.FrontImageID, nullUUIDString(p.FrontImageID))
+	}
+	if edit.Old.BackImageID != nil && *edit.Old.BackImageID != nullUUIDString(p.BackImageID) {
+		return fmt.Errorf("Invalid back image. Expected '%v' but was '%v'", *edit.Old.BackImageID, nullUUIDString(p.BackImageID))
+	}
+
+	return nil
+}
@@ -0,0 +1,260 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gofrs/uuid"
+)
+
+// PerformerSnapshot is the state of the joined fields of a performer at a
+// point in time.
+type PerformerSnapshot struct {
+	Aliases   []string
+	Urls      []*URL
+	Tattoos   []*BodyModification
+	Piercings []*BodyModification
+	ImageIDs  []string
+}
+
+// Undo reverts the joined field changes made by the edit.
+func (s *PerformerSnapshot) Undo(edit PerformerEdit) {
+	s.Aliases = undoStrings(s.Aliases, edit.AddedAliases, edit.RemovedAliases)
+	s.ImageIDs = undoStrings(s.ImageIDs, edit.AddedImages, edit.RemovedImages)
+	s.Tattoos = undoBodyMods(s.Tattoos, edit.AddedTattoos, edit.RemovedTattoos)
+	s.Piercings = undoBodyMods(s.Piercings, edit.AddedPiercings, edit.RemovedPiercings)
+
+	var urls []*URL
+	for _, url := range s.Urls {
+		if !containsURL(edit.AddedUrls, url) {
+			urls = append(urls, url)
+		}
+	}
+	for _, url := range edit.RemovedUrls {
+		if !containsURL(urls, url) {
+			urls = append(urls, url)
+		}
+	}
+	s.Urls = urls
+}
+
+// TagSnapshot is the state of the joined fields of a tag at a point in time.
+type TagSnapshot struct {
+	Aliases []string
+}
+
+// Undo reverts the joined field changes made by the edit.
+func (s *TagSnapshot) Undo(edit TagEdit) {
+	s.Aliases = undoStrings(s.Aliases, edit.AddedAliases, edit.RemovedAliases)
+}
+
+// UndoPerformerEdit reverts the field changes made by an applied modify or
+// merge edit.
+func (p *Performer) UndoPerformerEdit(data PerformerEditData) {
+	if data.New == nil || data.Old == nil {
+		return
+	}
+
+	updatedAt := p.UpdatedAt
+	p.CopyFromPerformerEdit(*data.Old, *data.New)
+	p.UpdatedAt = updatedAt
+
+	if p.Snapshot != nil {
+		p.Snapshot.Undo(*data.New)
+	}
+}
+
+// UndoTagEdit reverts the field changes made by an applied modify or merge
+// edit.
+func (p *Tag) UndoTagEdit(data TagEditData) {
+	if data.New == nil || data.Old == nil {
+		return
+	}
+
+	if data.Old.Name != nil {
+		p.Name = *data.Old.Name
+	}
+	if data.Old.Description != nil {
+		p.Description = sql.NullString{String: *data.Old.Description, Valid: true}
+	} else if data.New.Description != nil {
+		p.Description = sql.NullString{}
+	}
+	if data.Old.CategoryID != nil {
+		categoryID, err := uuid.FromString(*data.Old.CategoryID)
+		if err == nil {
+			p.CategoryID = uuid.NullUUID{UUID: categoryID, Valid: true}
+		}
+	} else if data.New.CategoryID != nil {
+		p.CategoryID = uuid.NullUUID{}
+	}
+
+	if p.Snapshot != nil {
+		p.Snapshot.Undo(*data.New)
+	}
+}
+
+func undoStrings(current []string, added []string, removed []string) []string {
+	ret := []string{}
+	for _, v := range current {
+		if !containsString(added, v) {
+			ret = append(ret, v)
+		}
+	}
+	for _, v := range removed {
+		if !containsString(ret, v) {
+			ret = append(ret, v)
+		}
+	}
+	return ret
+}
+
+func undoBodyMods(current []*BodyModification, added []*BodyModification, removed []*BodyModification) []*BodyModification {
+	var ret []*BodyModification
+	for _, v := range current {
+		if !containsBodyMod(added, v) {
+			ret = append(ret, v)
+		}
+	}
+	for _, v := range removed {
+		if !containsBodyMod(ret, v) {
+			ret = append(ret, v)
+		}
+	}
+	return ret
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsURL(values []*URL, value *URL) bool {
+	for _, v := range values {
+		if v.URL == value.URL {
+			return true
+		}
+	}
+	return false
+}
+
+func containsBodyMod(values []*BodyModification, value *BodyModification) bool {
+	for _, v := range values {
+		if bodyModString(v) == bodyModString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func bodyModString(mod *BodyModification) string {
+	if mod.Description == nil || *mod.Description == "" {
+		return mod.Location
+	}
+	return mod.Location + ": " + *mod.Description
+}
+
+// EntityFieldChange is a change to a single field of an entity made by an
+// applied edit.
+type EntityFieldChange struct {
+	Edit     *Edit
+	Field    string
+	OldValue *string
+	NewValue *string
+}
+
+// EditFieldChanges lists the field changes described by the new and old
+// values of an edit. newData and oldData must be pointers to the same edit
+// details type, and either may be nil. Added and removed list values are
+// reported as individual changes to the list field.
+func EditFieldChanges(edit *Edit, newData interface{}, oldData interface{}) []*EntityFieldChange {
+	var ret []*EntityFieldChange
+
+	newValue := reflect.ValueOf(newData)
+	oldValue := reflect.ValueOf(oldData)
+	if newValue.Kind() != reflect.Ptr || newValue.IsNil() {
+		return ret
+	}
+	newValue = newValue.Elem()
+	hasOld := oldValue.Kind() == reflect.Ptr && !oldValue.IsNil()
+	if hasOld {
+		oldValue = oldValue.Elem()
+	}
+
+	valueType := newValue.Type()
+	for i := 0; i < valueType.NumField(); i++ {
+		field := strings.Split(valueType.Field(i).Tag.Get("json"), ",")[0]
+		newField := newValue.Field(i)
+
+		if newField.Kind() == reflect.Slice {
+			var added bool
+			if strings.HasPrefix(field, "added_") {
+				field = strings.TrimPrefix(field, "added_")
+				added = true
+			} else if strings.HasPrefix(field, "removed_") {
+				field = strings.TrimPrefix(field, "removed_")
+			} else {
+				continue
+			}
+
+			for j := 0; j < newField.Len(); j++ {
+				value := formatEditValue(newField.Index(j))
+				change := &EntityFieldChange{Edit: edit, Field: field}
+				if added {
+					change.NewValue = value
+				} else {
+					change.OldValue = value
+				}
+				ret = append(ret, change)
+			}
+			continue
+		}
+
+		var oldField reflect.Value
+		if hasOld {
+			oldField = oldValue.Field(i)
+		}
+
+		if newField.IsNil() && (!oldField.IsValid() || oldField.IsNil()) {
+			continue
+		}
+
+		change := &EntityFieldChange{
+			Edit:     edit,
+			Field:    field,
+			NewValue: formatEditValue(newField),
+		}
+		if oldField.IsValid() {
+			change.OldValue = formatEditValue(oldField)
+		}
+		ret = append(ret, change)
+	}
+
+	return ret
+}
+
+func formatEditValue(v reflect.Value) *string {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+
+		switch t := v.Interface().(type) {
+		case *URL:
+			return &t.URL
+		case *BodyModification:
+			ret := bodyModString(t)
+			return &ret
+		}
+
+		v = v.Elem()
+	}
+
+	ret := fmt.Sprint(v.Interface())
+	return &ret
+}
@@ -0,0 +1,138 @@
+package models
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+)
+
+func TestUndoPerformerEdit(t *testing.T) {
+	oldName := "Old Name"
+	newName := "New Name"
+	country := "Country"
+	description := "heart"
+
+	performer := Performer{
+		Name:    newName,
+		Country: sql.NullString{String: country, Valid: true},
+		Snapshot: &PerformerSnapshot{
+			Aliases: []string{"kept", "added"},
+			Urls:    []*URL{{URL: "http://example.org/added", Type: "home"}},
+			Tattoos: []*BodyModification{{Location: "arm", Description: &description}},
+		},
+	}
+
+	performer.UndoPerformerEdit(PerformerEditData{
+		New: &PerformerEdit{
+			Name:           &newName,
+			Country:        &country,
+			AddedAliases:   []string{"added"},
+			RemovedAliases: []string{"removed"},
+			AddedUrls:      []*URL{{URL: "http://example.org/added", Type: "home"}},
+			RemovedUrls:    []*URL{{URL: "http://example.org/removed", Type: "home"}},
+			AddedTattoos:   []*BodyModification{{Location: "arm", Description: &description}},
+		},
+		Old: &PerformerEdit{
+			Name: &oldName,
+		},
+	})
+
+	if performer.Name != oldName {
+		t.Errorf("Name: expected %s, got %s", oldName, performer.Name)
+	}
+	if performer.Country.Valid {
+		t.Errorf("Country: expected null, got %s", performer.Country.String)
+	}
+	if expected := []string{"kept", "removed"}; !reflect.DeepEqual(performer.Snapshot.Aliases, expected) {
+		t.Errorf("Aliases: expected %v, got %v", expected, performer.Snapshot.Aliases)
+	}
+	if len(performer.Snapshot.Urls) != 1 || performer.Snapshot.Urls[0].URL != "http://example.org/removed" {
+		t.Errorf("Urls: expected only the removed url, got %v", performer.Snapshot.Urls)
+	}
+	if len(performer.Snapshot.Tattoos) != 0 {
+		t.Errorf("Tattoos: expected none, got %d", len(performer.Snapshot.Tattoos))
+	}
+}
+
+func TestUndoTagEdit(t *testing.T) {
+	oldName := "old"
+	newName := "new"
+	description := "description"
+
+	tag := Tag{
+		Name:        newName,
+		Description: sql.NullString{String: description, Valid: true},
+		Snapshot:    &TagSnapshot{Aliases: []string{"alias"}},
+	}
+
+	tag.UndoTagEdit(TagEditData{
+		New: &TagEdit{
+			Name:         &newName,
+			Description:  &description,
+			AddedAliases: []string{"alias"},
+		},
+		Old: &TagEdit{
+			Name: &oldName,
+		},
+	})
+
+	if tag.Name != oldName {
+		t.Errorf("Name: expected %s, got %s", oldName, tag.Name)
+	}
+	if tag.Description.Valid {
+		t.Errorf("Description: expected null, got %s", tag.Description.String)
+	}
+	if len(tag.Snapshot.Aliases) != 0 {
+		t.Errorf("Aliases: expected none, got %v", tag.Snapshot.Aliases)
+	}
+}
+
+func TestEditFieldChanges(t *testing.T) {
+	oldName := "old"
+	newName := "new"
+	var height int64 = 170
+
+	changes := EditFieldChanges(nil, &PerformerEdit{
+		Name:           &newName,
+		Height:         &height,
+		AddedAliases:   []string{"added"},
+		RemovedUrls:    []*URL{{URL: "http://example.org", Type: "home"}},
+		RemovedAliases: nil,
+	}, &PerformerEdit{
+		Name: &oldName,
+	})
+
+	str := func(s *string) string {
+		if s == nil {
+			return "<nil>"
+		}
+		return *s
+	}
+
+	var got []string
+	for _, c := range changes {
+		got = append(got, c.Field+": "+str(c.OldValue)+" -> "+str(c.NewValue))
+	}
+
+	expected := []string{
+		"name: old -> new",
+		"aliases: <nil> -> added",
+		"urls: http://example.org -> <nil>",
+		"height: <nil> -> 170",
+	}
+
+	if len(got) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+	for _, e := range expected {
+		found := false
+		for _, g := range got {
+			if g == e {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("expected change %q in %v", e, got)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"database/sql"
+	"sort"
 
 	"github.com/gofrs/uuid"
 
@@ -48,6 +49,9 @@ type PerformerScene struct {
 	PerformerID uuid.UUID      `db:"performer_id" json:"performer_id"`
 	As          sql.NullString `db:"as" json:"as"`
 	SceneID     uuid.UUID      `db:"scene_id" json:"scene_id"`
+	Role        sql.NullString `db:"role" json:"role"`
+	Position    sql.NullInt64  `db:"position" json:"position"`
+	Credited    bool           `db:"credited" json:"credited"`
 }
 
 type PerformersScenes []*PerformerScene
@@ -62,6 +66,20 @@ func (p *PerformersScenes) Add(o interface{}) {
 	*p = append(*p, o.(*PerformerScene))
 }
 
+// Sort orders the appearances by cast position. Appearances without a
+// position are placed last.
+func (p PerformersScenes) Sort() {
+	sort.SliceStable(p, func(i, j int) bool {
+		if !p[i].Position.Valid {
+			return false
+		}
+		if !p[j].Position.Valid {
+			return true
+		}
+		return p[i].Position.Int64 < p[j].Position.Int64
+	})
+}
+
 type SceneTag struct {
 	SceneID uuid.UUID `db:"scene_id" json:"scene_id"`
 	TagID   uuid.UUID `db:"tag_id" json:"tag_id"`
@@ -138,6 +156,10 @@ type StudioImage struct {
 	ImageID  uuid.UUID `db:"image_id" json:"image_id"`
 }
 
+func (p StudioImage) ID() string {
+	return p.ImageID.String()
+}
+
 type StudiosImages []*StudioImage
 
 func (p StudiosImages) Each(fn func(interface{})) {
@@ -150,9 +172,26 @@ func (p *StudiosImages) Add(o interface{}) {
 	*p = append(*p, o.(*StudioImage))
 }
 
+func (p StudiosImages) EachPtr(fn func(interface{})) {
+	for _, v := range p {
+		fn(v)
+	}
+}
+
+func (p *StudiosImages) Remove(id string) {
+	for i, v := range *p {
+		if (*v).ID() == id {
+			(*p)[i] = (*p)[len(*p)-1]
+			*p = (*p)[:len(*p)-1]
+			break
+		}
+	}
+}
+
 type URL struct {
-	URL  string `json:"url"`
-	Type string `json:"type"`
+	URL    string  `json:"url"`
+	Type   string  `json:"type"`
+	SiteID *string `json:"site_id,omitempty"`
 }
 
 type URLInput = URL
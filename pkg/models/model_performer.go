@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"fmt"
 	"github.com/gofrs/uuid"
+	"strconv"
 	"time"
 
 	"github.com/stashapp/stash-box/pkg/database"
@@ -62,6 +63,10 @@ type Performer struct {
 	CreatedAt         SQLiteTimestamp `db:"created_at" json:"created_at"`
 	UpdatedAt         SQLiteTimestamp `db:"updated_at" json:"updated_at"`
 	Deleted           bool            `db:"deleted" json:"deleted"`
+
+	// Snapshot holds the historical state of the joined fields when the
+	// performer was reconstructed at a point in time.
+	Snapshot *PerformerSnapshot `db:"-" json:"-"`
 }
 
 func (Performer) GetTable() database.Table {
@@ -178,15 +183,17 @@ func CreatePerformerAliases(performerId uuid.UUID, aliases []string) PerformerAl
 }
 
 type PerformerUrl struct {
-	PerformerID uuid.UUID `db:"performer_id" json:"performer_id"`
-	URL         string    `db:"url" json:"url"`
-	Type        string    `db:"type" json:"type"`
+	PerformerID uuid.UUID     `db:"performer_id" json:"performer_id"`
+	URL         string        `db:"url" json:"url"`
+	Type        string        `db:"type" json:"type"`
+	SiteID      uuid.NullUUID `db:"site_id" json:"site_id"`
 }
 
 func (p *PerformerUrl) ToURL() URL {
 	url := URL{
-		URL:  p.URL,
-		Type: p.Type,
+		URL:    p.URL,
+		Type:   p.Type,
+		SiteID: siteIDString(p.SiteID),
 	}
 	return url
 }
@@ -231,6 +238,7 @@ func CreatePerformerUrls(performerId uuid.UUID, urls []*URL) PerformerUrls {
 			PerformerID: performerId,
 			URL:         urlInput.URL,
 			Type:        urlInput.Type,
+			SiteID:      urlSiteID(urlInput),
 		})
 	}
 
@@ -569,3 +577,71 @@ func (p *Performer) ValidateModifyEdit(edit PerformerEditData) error {
 
 	return nil
 }
+
+// PerformerCostar is a performer who shares scenes with another performer.
+type PerformerCostar struct {
+	PerformerID uuid.UUID `db:"performer_id" json:"performer_id"`
+	SceneCount  int       `db:"scene_count" json:"scene_count"`
+}
+
+// PerformerConnection is a chain of shared scenes linking two performers.
+// SceneIDs[i] is a scene featuring both PerformerIDs[i] and
+// PerformerIDs[i+1].
+type PerformerConnection struct {
+	PerformerIDs []uuid.UUID
+	SceneIDs     []uuid.UUID
+}
+
+// PerformerStats summarises the career of a performer from their
+// non-deleted scenes.
+type PerformerStats struct {
+	ScenesPerYear         []*PerformerYearCount
+	Studios               []*PerformerStudioCount
+	CommonTags            []*PerformerTagCount
+	FirstSceneDate        SQLiteDate
+	LastSceneDate         SQLiteDate
+	CareerInconsistencies []CareerInconsistencyEnum
+}
+
+type PerformerYearCount struct {
+	PerformerID uuid.UUID `db:"performer_id" json:"performer_id"`
+	Year        int       `db:"year" json:"year"`
+	SceneCount  int       `db:"scene_count" json:"scene_count"`
+}
+
+type PerformerStudioCount struct {
+	PerformerID uuid.UUID `db:"performer_id" json:"performer_id"`
+	StudioID    uuid.UUID `db:"studio_id" json:"studio_id"`
+	SceneCount  int       `db:"scene_count" json:"scene_count"`
+}
+
+type PerformerTagCount struct {
+	PerformerID uuid.UUID `db:"performer_id" json:"performer_id"`
+	TagID       uuid.UUID `db:"tag_id" json:"tag_id"`
+	SceneCount  int       `db:"scene_count" json:"scene_count"`
+}
+
+func sqliteDateYear(date SQLiteDate) (int, bool) {
+	if !date.Valid || len(date.String) < 4 {
+		return 0, false
+	}
+
+	year, err := strconv.Atoi(date.String[0:4])
+	return year, err == nil
+}
+
+// CheckCareer returns the ways in which the scene dates disagree with the
+// career years of the performer.
+func (s PerformerStats) CheckCareer(p Performer) []CareerInconsistencyEnum {
+	ret := []CareerInconsistencyEnum{}
+
+	if year, ok := sqliteDateYear(s.FirstSceneDate); ok && p.CareerStartYear.Valid && int64(year) < p.CareerStartYear.Int64 {
+		ret = append(ret, CareerInconsistencyEnumSceneBeforeCareerStart)
+	}
+
+	if year, ok := sqliteDateYear(s.LastSceneDate); ok && p.CareerEndYear.Valid && int64(year) > p.CareerEndYear.Int64 {
+		ret = append(ret, CareerInconsistencyEnumSceneAfterCareerEnd)
+	}
+
+	return ret
+}
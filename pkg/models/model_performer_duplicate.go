@@ -0,0 +1,152 @@
+package models
+
+import (
+	"database/sql"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/database"
+)
+
+const (
+	performerDuplicateTable = "performer_duplicates"
+)
+
+var (
+	performerDuplicateDBTable = database.NewTable(performerDuplicateTable, func() interface{} {
+		return &PerformerDuplicate{}
+	})
+
+	performerDuplicateCandidateTable = database.NewTable(performerDuplicateTable, func() interface{} {
+		return &PerformerDuplicateCandidate{}
+	})
+)
+
+// MinPerformerDuplicateScore is the score below which candidate pairs are
+// not stored for review.
+const MinPerformerDuplicateScore = 0.5
+
+// PerformerDuplicate is a pair of performers that are likely to be the same
+// person. PerformerID is always the lesser of the two ids.
+type PerformerDuplicate struct {
+	ID             uuid.UUID       `db:"id" json:"id"`
+	PerformerID    uuid.UUID       `db:"performer_id" json:"performer_id"`
+	DuplicateID    uuid.UUID       `db:"duplicate_id" json:"duplicate_id"`
+	Score          float64         `db:"score" json:"score"`
+	NameSimilarity float64         `db:"name_similarity" json:"name_similarity"`
+	BirthdateMatch sql.NullBool    `db:"birthdate_match" json:"birthdate_match"`
+	SharedUrls     int             `db:"shared_urls" json:"shared_urls"`
+	SharedScenes   int             `db:"shared_scenes" json:"shared_scenes"`
+	Status         string          `db:"status" json:"status"`
+	EditID         uuid.NullUUID   `db:"edit_id,omitempty" json:"edit_id"`
+	CreatedAt      SQLiteTimestamp `db:"created_at" json:"created_at"`
+	UpdatedAt      SQLiteTimestamp `db:"updated_at" json:"updated_at"`
+}
+
+func (PerformerDuplicate) GetTable() database.Table {
+	return performerDuplicateDBTable
+}
+
+func (p PerformerDuplicate) GetID() uuid.UUID {
+	return p.ID
+}
+
+type PerformerDuplicates []*PerformerDuplicate
+
+func (p PerformerDuplicates) Each(fn func(interface{})) {
+	for _, v := range p {
+		fn(*v)
+	}
+}
+
+func (p *PerformerDuplicates) Add(o interface{}) {
+	*p = append(*p, o.(*PerformerDuplicate))
+}
+
+// PerformerDuplicateCandidate holds the signals gathered for a pair of
+// performers before scoring.
+type PerformerDuplicateCandidate struct {
+	PerformerID                uuid.UUID      `db:"performer_id"`
+	DuplicateID                uuid.UUID      `db:"duplicate_id"`
+	NameSimilarity             float64        `db:"name_similarity"`
+	SharedUrls                 int            `db:"shared_urls"`
+	SharedScenes               int            `db:"shared_scenes"`
+	PerformerBirthdate         SQLiteDate     `db:"performer_birthdate"`
+	PerformerBirthdateAccuracy sql.NullString `db:"performer_birthdate_accuracy"`
+	DuplicateBirthdate         SQLiteDate     `db:"duplicate_birthdate"`
+	DuplicateBirthdateAccuracy sql.NullString `db:"duplicate_birthdate_accuracy"`
+}
+
+type PerformerDuplicateCandidates []*PerformerDuplicateCandidate
+
+func (p *PerformerDuplicateCandidates) Add(o interface{}) {
+	*p = append(*p, o.(*PerformerDuplicateCandidate))
+}
+
+// BirthdateMatch returns whether the birthdates of the pair agree at the
+// precision of the less accurate of the two. It is not valid if either
+// birthdate is unknown.
+func (c PerformerDuplicateCandidate) BirthdateMatch() sql.NullBool {
+	if !c.PerformerBirthdate.Valid || !c.DuplicateBirthdate.Valid {
+		return sql.NullBool{}
+	}
+
+	accuracy := coarserDateAccuracy(
+		DateAccuracyEnum(c.PerformerBirthdateAccuracy.String),
+		DateAccuracyEnum(c.DuplicateBirthdateAccuracy.String),
+	)
+
+	a := TruncateFuzzyDate(c.PerformerBirthdate.String, accuracy)
+	b := TruncateFuzzyDate(c.DuplicateBirthdate.String, accuracy)
+	return sql.NullBool{Bool: a == b, Valid: true}
+}
+
+// Score combines the signals of the pair into a value between 0 and 1.
+func (c PerformerDuplicateCandidate) Score() float64 {
+	score := 0.6 * c.NameSimilarity
+
+	if match := c.BirthdateMatch(); match.Valid {
+		if match.Bool {
+			score += 0.2
+		} else {
+			score -= 0.4
+		}
+	}
+
+	if c.SharedUrls > 0 {
+		score += 0.3
+	}
+
+	if c.SharedScenes > 0 {
+		score += 0.1
+	}
+
+	if score < 0 {
+		return 0
+	}
+	if score > 1 {
+		return 1
+	}
+	return score
+}
+
+func dateAccuracyRank(accuracy DateAccuracyEnum) int {
+	switch accuracy {
+	case DateAccuracyEnumYear:
+		return 0
+	case DateAccuracyEnumMonth:
+		return 1
+	default:
+		return 2
+	}
+}
+
+func coarserDateAccuracy(a DateAccuracyEnum, b DateAccuracyEnum) DateAccuracyEnum {
+	if dateAccuracyRank(a) < dateAccuracyRank(b) {
+		return a
+	}
+	if !b.IsValid() {
+		return DateAccuracyEnumDay
+	}
+	return b
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"database/sql"
+	"testing"
+)
+
+func birthdateCandidate(a string, aAccuracy DateAccuracyEnum, b string, bAccuracy DateAccuracyEnum) PerformerDuplicateCandidate {
+	return PerformerDuplicateCandidate{
+		PerformerBirthdate:         SQLiteDate{String: a, Valid: a != ""},
+		PerformerBirthdateAccuracy: sql.NullString{String: aAccuracy.String(), Valid: a != ""},
+		DuplicateBirthdate:         SQLiteDate{String: b, Valid: b != ""},
+		DuplicateBirthdateAccuracy: sql.NullString{String: bAccuracy.String(), Valid: b != ""},
+	}
+}
+
+func TestPerformerDuplicateBirthdateMatch(t *testing.T) {
+	tests := []struct {
+		candidate PerformerDuplicateCandidate
+		expected  sql.NullBool
+	}{
+		{birthdateCandidate("1990-05-12", DateAccuracyEnumDay, "1990-05-12", DateAccuracyEnumDay), sql.NullBool{Bool: true, Valid: true}},
+		{birthdateCandidate("1990-05-12", DateAccuracyEnumDay, "1990-05-13", DateAccuracyEnumDay), sql.NullBool{Bool: false, Valid: true}},
+		{birthdateCandidate("1990-05-12", DateAccuracyEnumDay, "1990-01-01", DateAccuracyEnumYear), sql.NullBool{Bool: true, Valid: true}},
+		{birthdateCandidate("1990-05-01", DateAccuracyEnumMonth, "1990-05-20", DateAccuracyEnumDay), sql.NullBool{Bool: true, Valid: true}},
+		{birthdateCandidate("1990-05-01", DateAccuracyEnumMonth, "1990-06-01", DateAccuracyEnumMonth), sql.NullBool{Bool: false, Valid: true}},
+		{birthdateCandidate("1991-01-01", DateAccuracyEnumYear, "1990-05-12", DateAccuracyEnumDay), sql.NullBool{Bool: false, Valid: true}},
+		{birthdateCandidate("1990-05-12", DateAccuracyEnumDay, "", ""), sql.NullBool{}},
+	}
+
+	for _, tt := range tests {
+		if got := tt.candidate.BirthdateMatch(); got != tt.expected {
+			t.Errorf("BirthdateMatch(%s, %s) = %v, want %v", tt.candidate.PerformerBirthdate.String, tt.candidate.DuplicateBirthdate.String, got, tt.expected)
+		}
+	}
+}
+
+func TestPerformerDuplicateScore(t *testing.T) {
+	sameName := PerformerDuplicateCandidate{NameSimilarity: 1}
+	if score := sameName.Score(); score < MinPerformerDuplicateScore {
+		t.Errorf("identical names scored %v, want at least %v", score, MinPerformerDuplicateScore)
+	}
+
+	conflicting := birthdateCandidate("1990-05-12", DateAccuracyEnumDay, "1985-02-01", DateAccuracyEnumDay)
+	conflicting.NameSimilarity = 1
+	if score := conflicting.Score(); score >= MinPerformerDuplicateScore {
+		t.Errorf("identical names with conflicting birthdates scored %v, want below %v", score, MinPerformerDuplicateScore)
+	}
+
+	sharedURL := PerformerDuplicateCandidate{NameSimilarity: 0.4, SharedUrls: 1}
+	if score := sharedURL.Score(); score < MinPerformerDuplicateScore {
+		t.Errorf("similar names with a shared URL scored %v, want at least %v", score, MinPerformerDuplicateScore)
+	}
+
+	everything := birthdateCandidate("1990-05-12", DateAccuracyEnumDay, "1990-05-12", DateAccuracyEnumDay)
+	everything.NameSimilarity = 1
+	everything.SharedUrls = 3
+	everything.SharedScenes = 2
+	if score := everything.Score(); score != 1 {
+		t.Errorf("all signals scored %v, want 1", score)
+	}
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+)
+
+func TestPerformerStatsCheckCareer(t *testing.T) {
+	stats := PerformerStats{
+		FirstSceneDate: SQLiteDate{String: "2005-03-01", Valid: true},
+		LastSceneDate:  SQLiteDate{String: "2012-11-20", Valid: true},
+	}
+
+	tests := []struct {
+		start    sql.NullInt64
+		end      sql.NullInt64
+		expected []CareerInconsistencyEnum
+	}{
+		{sql.NullInt64{}, sql.NullInt64{}, []CareerInconsistencyEnum{}},
+		{sql.NullInt64{Int64: 2005, Valid: true}, sql.NullInt64{Int64: 2012, Valid: true}, []CareerInconsistencyEnum{}},
+		{sql.NullInt64{Int64: 2006, Valid: true}, sql.NullInt64{}, []CareerInconsistencyEnum{CareerInconsistencyEnumSceneBeforeCareerStart}},
+		{sql.NullInt64{}, sql.NullInt64{Int64: 2011, Valid: true}, []CareerInconsistencyEnum{CareerInconsistencyEnumSceneAfterCareerEnd}},
+		{sql.NullInt64{Int64: 2006, Valid: true}, sql.NullInt64{Int64: 2011, Valid: true}, []CareerInconsistencyEnum{CareerInconsistencyEnumSceneBeforeCareerStart, CareerInconsistencyEnumSceneAfterCareerEnd}},
+	}
+
+	for _, tt := range tests {
+		p := Performer{
+			CareerStartYear: tt.start,
+			CareerEndYear:   tt.end,
+		}
+		if got := stats.CheckCareer(p); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("CheckCareer(%v, %v) = %v, want %v", tt.start, tt.end, got, tt.expected)
+		}
+	}
+
+	if got := (PerformerStats{}).CheckCareer(Performer{CareerStartYear: sql.NullInt64{Int64: 2006, Valid: true}}); len(got) != 0 {
+		t.Errorf("CheckCareer without scene dates = %v, want none", got)
+	}
+}
@@ -28,16 +28,17 @@ var (
 )
 
 type Scene struct {
-	ID        uuid.UUID       `db:"id" json:"id"`
-	Title     sql.NullString  `db:"title" json:"title"`
-	Details   sql.NullString  `db:"details" json:"details"`
-	Date      SQLiteDate      `db:"date" json:"date"`
-	StudioID  uuid.NullUUID   `db:"studio_id,omitempty" json:"studio_id"`
-	CreatedAt SQLiteTimestamp `db:"created_at" json:"created_at"`
-	UpdatedAt SQLiteTimestamp `db:"updated_at" json:"updated_at"`
-	Duration  sql.NullInt64   `db:"duration" json:"duration"`
-	Director  sql.NullString  `db:"director" json:"director"`
-	Deleted   bool            `db:"deleted" json:"deleted"`
+	ID           uuid.UUID       `db:"id" json:"id"`
+	Title        sql.NullString  `db:"title" json:"title"`
+	Details      sql.NullString  `db:"details" json:"details"`
+	Date         SQLiteDate      `db:"date" json:"date"`
+	DateAccuracy sql.NullString  `db:"date_accuracy" json:"date_accuracy"`
+	StudioID     uuid.NullUUID   `db:"studio_id,omitempty" json:"studio_id"`
+	CreatedAt    SQLiteTimestamp `db:"created_at" json:"created_at"`
+	UpdatedAt    SQLiteTimestamp `db:"updated_at" json:"updated_at"`
+	Duration     sql.NullInt64   `db:"duration" json:"duration"`
+	Director     sql.NullString  `db:"director" json:"director"`
+	Deleted      bool            `db:"deleted" json:"deleted"`
 }
 
 func (Scene) GetTable() database.Table {
@@ -68,15 +69,17 @@ type SceneFingerprint struct {
 }
 
 type SceneUrl struct {
-	SceneID uuid.UUID `db:"scene_id" json:"scene_id"`
-	URL     string    `db:"url" json:"url"`
-	Type    string    `db:"type" json:"type"`
+	SceneID uuid.UUID     `db:"scene_id" json:"scene_id"`
+	URL     string        `db:"url" json:"url"`
+	Type    string        `db:"type" json:"type"`
+	SiteID  uuid.NullUUID `db:"site_id" json:"site_id"`
 }
 
 func (p *SceneUrl) ToURL() URL {
 	url := URL{
-		URL:  p.URL,
-		Type: p.Type,
+		URL:    p.URL,
+		Type:   p.Type,
+		SiteID: siteIDString(p.SiteID),
 	}
 	return url
 }
@@ -101,6 +104,7 @@ func CreateSceneUrls(sceneId uuid.UUID, urls []*URLInput) SceneUrls {
 			SceneID: sceneId,
 			URL:     urlInput.URL,
 			Type:    urlInput.Type,
+			SiteID:  urlSiteID(urlInput),
 		})
 	}
 
@@ -181,37 +185,55 @@ func CreateSceneImages(sceneID uuid.UUID, imageIds []string) ScenesImages {
 	return imageJoins
 }
 
-func CreateScenePerformers(sceneID uuid.UUID, appearances []*PerformerAppearanceInput) PerformersScenes {
+func CreateScenePerformers(sceneID uuid.UUID, appearances []*PerformerAppearanceInput) (PerformersScenes, error) {
 	var performerJoins PerformersScenes
 	for _, a := range appearances {
-		performerID, _ := uuid.FromString(a.PerformerID)
+		performerID, err := uuid.FromString(a.PerformerID)
+		if err != nil {
+			return nil, err
+		}
 		performerJoin := &PerformerScene{
 			SceneID:     sceneID,
 			PerformerID: performerID,
+			Credited:    a.Credited == nil || *a.Credited,
 		}
 
 		if a.As != nil {
 			performerJoin.As = sql.NullString{Valid: true, String: *a.As}
 		}
 
+		if a.Role != nil {
+			performerJoin.Role = sql.NullString{Valid: true, String: *a.Role}
+		}
+
+		if a.Position != nil {
+			performerJoin.Position = sql.NullInt64{Valid: true, Int64: int64(*a.Position)}
+		}
+
 		performerJoins = append(performerJoins, performerJoin)
 	}
 
-	return performerJoins
+	return performerJoins, nil
 }
 
 func (p *Scene) IsEditTarget() {
 }
 
-func (p *Scene) setDate(date string) {
-	p.Date = SQLiteDate{String: date, Valid: true}
+func (p *Scene) setDate(date string, accuracy *DateAccuracyEnum) {
+	dateAccuracy := DateAccuracyEnumDay
+	if accuracy != nil && accuracy.IsValid() {
+		dateAccuracy = *accuracy
+	}
+
+	p.Date = SQLiteDate{String: TruncateFuzzyDate(date, dateAccuracy), Valid: true}
+	p.DateAccuracy = sql.NullString{String: dateAccuracy.String(), Valid: true}
 }
 
 func (p *Scene) CopyFromCreateInput(input SceneCreateInput) {
 	CopyFull(p, input)
 
 	if input.Date != nil {
-		p.setDate(*input.Date)
+		p.setDate(*input.Date, input.DateAccuracy)
 	}
 }
 
@@ -219,6 +241,22 @@ func (p *Scene) CopyFromUpdateInput(input SceneUpdateInput) {
 	CopyFull(p, input)
 
 	if input.Date != nil {
-		p.setDate(*input.Date)
+		p.setDate(*input.Date, input.DateAccuracy)
+	} else if input.DateAccuracy != nil && p.Date.Valid {
+		p.setDate(p.Date.String, input.DateAccuracy)
 	}
 }
+
+func (p Scene) ResolveDateAccuracy() *DateAccuracyEnum {
+	if !p.Date.Valid {
+		return nil
+	}
+
+	// dates set before accuracy was recorded are treated as day accurate
+	ret := DateAccuracyEnumDay
+	if p.DateAccuracy.Valid && DateAccuracyEnum(p.DateAccuracy.String).IsValid() {
+		ret = DateAccuracyEnum(p.DateAccuracy.String)
+	}
+
+	return &ret
+}
@@ -0,0 +1,141 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gofrs/uuid"
+	"github.com/lib/pq"
+
+	"github.com/stashapp/stash-box/pkg/database"
+)
+
+const (
+	siteTable = "sites"
+)
+
+var (
+	siteDBTable = database.NewTable(siteTable, func() interface{} {
+		return &Site{}
+	})
+)
+
+type Site struct {
+	ID          uuid.UUID       `db:"id" json:"id"`
+	Name        string          `db:"name" json:"name"`
+	Description sql.NullString  `db:"description" json:"description"`
+	URL         sql.NullString  `db:"url" json:"url"`
+	Regex       sql.NullString  `db:"regex" json:"regex"`
+	ValidTypes  pq.StringArray  `db:"valid_types" json:"valid_types"`
+	Icon        sql.NullString  `db:"icon" json:"icon"`
+	CreatedAt   SQLiteTimestamp `db:"created_at" json:"created_at"`
+	UpdatedAt   SQLiteTimestamp `db:"updated_at" json:"updated_at"`
+}
+
+func (Site) GetTable() database.Table {
+	return siteDBTable
+}
+
+func (p Site) GetID() uuid.UUID {
+	return p.ID
+}
+
+type Sites []*Site
+
+func (p Sites) Each(fn func(interface{})) {
+	for _, v := range p {
+		fn(v)
+	}
+}
+
+func (p *Sites) Add(o interface{}) {
+	*p = append(*p, o.(*Site))
+}
+
+func (p *Site) CopyFromCreateInput(input SiteCreateInput) {
+	CopyFull(p, input)
+	p.setValidTypes(input.ValidTypes)
+}
+
+func (p *Site) CopyFromUpdateInput(input SiteUpdateInput) {
+	CopyFull(p, input)
+	if input.ValidTypes != nil {
+		p.setValidTypes(input.ValidTypes)
+	}
+}
+
+func (p *Site) setValidTypes(validTypes []ValidSiteTypeEnum) {
+	p.ValidTypes = pq.StringArray{}
+	for _, t := range validTypes {
+		p.ValidTypes = append(p.ValidTypes, t.String())
+	}
+}
+
+// IsValidFor returns whether urls of the site may be attached to entities
+// of the given type.
+func (p Site) IsValidFor(validType ValidSiteTypeEnum) bool {
+	for _, t := range p.ValidTypes {
+		if t == validType.String() {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateRegex returns an error if the site's url pattern is not a valid
+// regular expression.
+func (p Site) ValidateRegex() error {
+	if !p.Regex.Valid || p.Regex.String == "" {
+		return nil
+	}
+
+	if _, err := regexp.Compile(p.Regex.String); err != nil {
+		return fmt.Errorf("invalid regex for site %s: %s", p.Name, err.Error())
+	}
+	return nil
+}
+
+// ValidateURL returns an error if the site does not apply to the entity
+// type, or if the url does not match the site's url pattern.
+func (p Site) ValidateURL(url string, validType ValidSiteTypeEnum) error {
+	if !p.IsValidFor(validType) {
+		return fmt.Errorf("site %s is not valid for %s urls", p.Name, strings.ToLower(validType.String()))
+	}
+
+	if !p.Regex.Valid || p.Regex.String == "" {
+		return nil
+	}
+
+	re, err := regexp.Compile(p.Regex.String)
+	if err != nil {
+		return fmt.Errorf("invalid regex for site %s: %s", p.Name, err.Error())
+	}
+
+	if !re.MatchString(url) {
+		return fmt.Errorf("url %s does not match the pattern for site %s", url, p.Name)
+	}
+	return nil
+}
+
+func urlSiteID(url *URL) uuid.NullUUID {
+	if url.SiteID == nil {
+		return uuid.NullUUID{}
+	}
+
+	id, err := uuid.FromString(*url.SiteID)
+	if err != nil {
+		return uuid.NullUUID{}
+	}
+	return uuid.NullUUID{UUID: id, Valid: true}
+}
+
+func siteIDString(id uuid.NullUUID) *string {
+	if !id.Valid {
+		return nil
+	}
+
+	ret := id.UUID.String()
+	return &ret
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/lib/pq"
+)
+
+func TestSiteValidateURL(t *testing.T) {
+	site := Site{
+		Name:       "Example",
+		Regex:      sql.NullString{String: `^https?://(www\.)?example\.org/`, Valid: true},
+		ValidTypes: pq.StringArray{ValidSiteTypeEnumPerformer.String()},
+	}
+
+	tests := []struct {
+		url       string
+		validType ValidSiteTypeEnum
+		valid     bool
+	}{
+		{"https://example.org/performer", ValidSiteTypeEnumPerformer, true},
+		{"http://www.example.org/performer", ValidSiteTypeEnumPerformer, true},
+		{"https://example.com/performer", ValidSiteTypeEnumPerformer, false},
+		{"https://example.org/scene", ValidSiteTypeEnumScene, false},
+	}
+
+	for _, tt := range tests {
+		err := site.ValidateURL(tt.url, tt.validType)
+		if (err == nil) != tt.valid {
+			t.Errorf("ValidateURL(%s, %s): expected valid %t, got error %v", tt.url, tt.validType, tt.valid, err)
+		}
+	}
+
+	site.Regex = sql.NullString{}
+	if err := site.ValidateURL("anything", ValidSiteTypeEnumPerformer); err != nil {
+		t.Errorf("ValidateURL without regex: unexpected error %s", err.Error())
+	}
+
+	site.Regex = sql.NullString{String: "(", Valid: true}
+	if err := site.ValidateRegex(); err == nil {
+		t.Error("ValidateRegex: expected error for invalid regex")
+	}
+}
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"fmt"
+
 	"github.com/gofrs/uuid"
 
 	"github.com/stashapp/stash-box/pkg/database"
@@ -19,6 +21,10 @@ var (
 	studioUrlTable = database.NewTableJoin(studioTable, "studio_urls", studioJoinKey, func() interface{} {
 		return &StudioUrl{}
 	})
+
+	studioAliasTable = database.NewTableJoin(studioTable, "studio_aliases", studioJoinKey, func() interface{} {
+		return &StudioAlias{}
+	})
 )
 
 type Studio struct {
@@ -51,15 +57,21 @@ func (p *Studios) Add(o interface{}) {
 }
 
 type StudioUrl struct {
-	StudioID uuid.UUID `db:"studio_id" json:"studio_id"`
-	URL      string    `db:"url" json:"url"`
-	Type     string    `db:"type" json:"type"`
+	StudioID uuid.UUID     `db:"studio_id" json:"studio_id"`
+	URL      string        `db:"url" json:"url"`
+	Type     string        `db:"type" json:"type"`
+	SiteID   uuid.NullUUID `db:"site_id" json:"site_id"`
+}
+
+func (p StudioUrl) ID() string {
+	return p.URL + p.Type
 }
 
 func (p *StudioUrl) ToURL() URL {
 	url := URL{
-		URL:  p.URL,
-		Type: p.Type,
+		URL:    p.URL,
+		Type:   p.Type,
+		SiteID: siteIDString(p.SiteID),
 	}
 	return url
 }
@@ -72,10 +84,35 @@ func (p StudioUrls) Each(fn func(interface{})) {
 	}
 }
 
+func (p StudioUrls) EachPtr(fn func(interface{})) {
+	for _, v := range p {
+		fn(v)
+	}
+}
+
 func (p *StudioUrls) Add(o interface{}) {
 	*p = append(*p, (o.(*StudioUrl)))
 }
 
+func (p *StudioUrls) Remove(id string) {
+	for i, v := range *p {
+		if (*v).ID() == id {
+			(*p)[i] = (*p)[len(*p)-1]
+			*p = (*p)[:len(*p)-1]
+			break
+		}
+	}
+}
+
+func (p StudioUrls) ToURLs() []*URL {
+	var ret []*URL
+	for _, v := range p {
+		url := v.ToURL()
+		ret = append(ret, &url)
+	}
+	return ret
+}
+
 func CreateStudioUrls(studioId uuid.UUID, urls []*URLInput) StudioUrls {
 	var ret StudioUrls
 
@@ -84,12 +121,69 @@ func CreateStudioUrls(studioId uuid.UUID, urls []*URLInput) StudioUrls {
 			StudioID: studioId,
 			URL:      urlInput.URL,
 			Type:     urlInput.Type,
+			SiteID:   urlSiteID(urlInput),
 		})
 	}
 
 	return ret
 }
 
+type StudioAlias struct {
+	StudioID uuid.UUID `db:"studio_id" json:"studio_id"`
+	Alias    string    `db:"alias" json:"alias"`
+}
+
+func (p StudioAlias) ID() string {
+	return p.Alias
+}
+
+type StudioAliases []*StudioAlias
+
+func (p StudioAliases) Each(fn func(interface{})) {
+	for _, v := range p {
+		fn(*v)
+	}
+}
+
+func (p StudioAliases) EachPtr(fn func(interface{})) {
+	for _, v := range p {
+		fn(v)
+	}
+}
+
+func (p *StudioAliases) Add(o interface{}) {
+	*p = append(*p, o.(*StudioAlias))
+}
+
+func (p *StudioAliases) Remove(id string) {
+	for i, v := range *p {
+		if (*v).ID() == id {
+			(*p)[i] = (*p)[len(*p)-1]
+			*p = (*p)[:len(*p)-1]
+			break
+		}
+	}
+}
+
+func (p StudioAliases) ToAliases() []string {
+	var ret []string
+	for _, v := range p {
+		ret = append(ret, v.Alias)
+	}
+
+	return ret
+}
+
+func CreateStudioAliases(studioID uuid.UUID, aliases []string) StudioAliases {
+	var ret StudioAliases
+
+	for _, alias := range aliases {
+		ret = append(ret, &StudioAlias{StudioID: studioID, Alias: alias})
+	}
+
+	return ret
+}
+
 func (p *Studio) IsEditTarget() {
 }
 
@@ -130,3 +224,25 @@ func CreateStudioImages(studioID uuid.UUID, imageIds []string) StudiosImages {
 
 	return imageJoins
 }
+
+func (p *Studio) CopyFromStudioEdit(input StudioEdit, old StudioEdit) {
+	if input.Name != nil {
+		p.Name = *input.Name
+	}
+	if input.ParentID != nil {
+		p.ParentStudioID = parseNullUUID(input.ParentID)
+	} else if old.ParentID != nil {
+		p.ParentStudioID = uuid.NullUUID{}
+	}
+}
+
+func (p *Studio) ValidateModifyEdit(edit StudioEditData) error {
+	if edit.Old.Name != nil && *edit.Old.Name != p.Name {
+		return fmt.Errorf("Invalid name. Expected '%v' but was '%v'", *edit.Old.Name, p.Name)
+	}
+	if edit.Old.ParentID != nil && *edit.Old.ParentID != nullUUIDString(p.ParentStudioID) {
+		return fmt.Errorf("Invalid parent studio. Expected '%v' but was '%v'", *edit.Old.ParentID, nullUUIDString(p.ParentStudioID))
+	}
+
+	return nil
+}
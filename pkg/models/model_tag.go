@@ -23,6 +23,10 @@ var (
 		return &TagAlias{}
 	})
 
+	tagParentTable = database.NewTableJoin(tagTable, "tag_parents", tagJoinKey, func() interface{} {
+		return &TagParent{}
+	})
+
 	tagRedirectTable = database.NewTableJoin(tagTable, "tag_redirects", "source_id", func() interface{} {
 		return &TagRedirect{}
 	})
@@ -36,6 +40,10 @@ type Tag struct {
 	CreatedAt   SQLiteTimestamp `db:"created_at" json:"created_at"`
 	UpdatedAt   SQLiteTimestamp `db:"updated_at" json:"updated_at"`
 	Deleted     bool            `db:"deleted" json:"deleted"`
+
+	// Snapshot holds the historical state of the joined fields when the tag
+	// was reconstructed at a point in time.
+	Snapshot *TagSnapshot `db:"-" json:"-"`
 }
 
 func (Tag) GetTable() database.Table {
@@ -140,6 +148,65 @@ func CreateTagAliases(tagId uuid.UUID, aliases []string) []*TagAlias {
 	return ret
 }
 
+type TagParent struct {
+	TagID    uuid.UUID `db:"tag_id" json:"tag_id"`
+	ParentID uuid.UUID `db:"parent_id" json:"parent_id"`
+}
+
+func (p TagParent) ID() string {
+	return p.ParentID.String()
+}
+
+type TagParents []*TagParent
+
+func (p TagParents) Each(fn func(interface{})) {
+	for _, v := range p {
+		fn(*v)
+	}
+}
+
+func (p TagParents) EachPtr(fn func(interface{})) {
+	for _, v := range p {
+		fn(v)
+	}
+}
+
+func (p *TagParents) Add(o interface{}) {
+	*p = append(*p, o.(*TagParent))
+}
+
+func (p *TagParents) Remove(id string) {
+	for i, v := range *p {
+		if (*v).ID() == id {
+			(*p)[i] = (*p)[len(*p)-1]
+			*p = (*p)[:len(*p)-1]
+			break
+		}
+	}
+}
+
+func (p TagParents) ToParentIDs() []string {
+	ret := []string{}
+	for _, v := range p {
+		ret = append(ret, v.ParentID.String())
+	}
+
+	return ret
+}
+
+func CreateTagParents(tagID uuid.UUID, parentIDs []string) TagParents {
+	var ret TagParents
+
+	for _, id := range parentIDs {
+		parentID, err := uuid.FromString(id)
+		if err == nil {
+			ret = append(ret, &TagParent{TagID: tagID, ParentID: parentID})
+		}
+	}
+
+	return ret
+}
+
 func (p *Tag) IsEditTarget() {
 }
 
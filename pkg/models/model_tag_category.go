@@ -47,6 +47,14 @@ func (p *TagCategories) Add(o interface{}) {
 	*p = append(*p, o.(*TagCategory))
 }
 
+// TagCategoryUsage counts the non-deleted tags in a category and the
+// non-deleted scenes tagged with them.
+type TagCategoryUsage struct {
+	CategoryID uuid.UUID `db:"category_id" json:"category_id"`
+	TagCount   int       `db:"tag_count" json:"tag_count"`
+	SceneCount int       `db:"scene_count" json:"scene_count"`
+}
+
 func (p *TagCategory) CopyFromCreateInput(input TagCategoryCreateInput) {
 	CopyFull(p, input)
 }
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUserModerationActionIsActiveSuspension(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name   string
+		action UserModerationAction
+		active bool
+	}{
+		{"indefinite", UserModerationAction{Action: UserModerationActionEnumSuspend.String()}, true},
+		{"unexpired", UserModerationAction{
+			Action: UserModerationActionEnumSuspend.String(),
+			Until:  NullSQLiteTimestamp{Timestamp: now.Add(time.Hour), Valid: true},
+		}, true},
+		{"expired", UserModerationAction{
+			Action: UserModerationActionEnumSuspend.String(),
+			Until:  NullSQLiteTimestamp{Timestamp: now, Valid: true},
+		}, false},
+		{"unsuspend", UserModerationAction{Action: UserModerationActionEnumUnsuspend.String()}, false},
+	}
+
+	for _, tt := range tests {
+		if active := tt.action.IsActiveSuspension(now); active != tt.active {
+			t.Errorf("%s: IsActiveSuspension = %t, want %t", tt.name, active, tt.active)
+		}
+	}
+}
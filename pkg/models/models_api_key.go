@@ -0,0 +1,99 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/database"
+)
+
+const (
+	apiKeyTable   = "api_keys"
+	apiKeyJoinKey = "api_key_id"
+)
+
+var (
+	apiKeyDBTable = database.NewTable(apiKeyTable, func() interface{} {
+		return &APIKey{}
+	})
+
+	apiKeyRolesTable = database.NewTableJoin(apiKeyTable, "api_key_roles", apiKeyJoinKey, func() interface{} {
+		return &APIKeyRole{}
+	})
+)
+
+// APIKey is a named API key of a user. Only the hash of the key is stored.
+type APIKey struct {
+	ID         uuid.UUID           `db:"id" json:"id"`
+	UserID     uuid.UUID           `db:"user_id" json:"user_id"`
+	Name       string              `db:"name" json:"name"`
+	KeyHash    string              `db:"key_hash" json:"key_hash"`
+	ExpiresAt  NullSQLiteTimestamp `db:"expires_at" json:"expires_at"`
+	LastUsedAt NullSQLiteTimestamp `db:"last_used_at" json:"last_used_at"`
+	CreatedAt  SQLiteTimestamp     `db:"created_at" json:"created_at"`
+}
+
+func (APIKey) GetTable() database.Table {
+	return apiKeyDBTable
+}
+
+func (p APIKey) GetID() uuid.UUID {
+	return p.ID
+}
+
+// IsExpired returns true if the key has an expiry time before t.
+func (p APIKey) IsExpired(t time.Time) bool {
+	return p.ExpiresAt.Valid && !p.ExpiresAt.Timestamp.After(t)
+}
+
+type APIKeys []*APIKey
+
+func (p APIKeys) Each(fn func(interface{})) {
+	for _, v := range p {
+		fn(*v)
+	}
+}
+
+func (p *APIKeys) Add(o interface{}) {
+	*p = append(*p, o.(*APIKey))
+}
+
+type APIKeyRole struct {
+	APIKeyID uuid.UUID `db:"api_key_id" json:"api_key_id"`
+	Role     string    `db:"role" json:"role"`
+}
+
+type APIKeyRoles []*APIKeyRole
+
+func (p APIKeyRoles) Each(fn func(interface{})) {
+	for _, v := range p {
+		fn(*v)
+	}
+}
+
+func (p *APIKeyRoles) Add(o interface{}) {
+	*p = append(*p, o.(*APIKeyRole))
+}
+
+func (p APIKeyRoles) ToRoles() []RoleEnum {
+	var ret []RoleEnum
+	for _, v := range p {
+		ret = append(ret, RoleEnum(v.Role))
+	}
+
+	return ret
+}
+
+func CreateAPIKeyRoles(apiKeyID uuid.UUID, roles []RoleEnum) APIKeyRoles {
+	var ret APIKeyRoles
+
+	for _, role := range roles {
+		ret = append(ret, &APIKeyRole{
+			APIKeyID: apiKeyID,
+			Role:     role.String(),
+		})
+	}
+
+	return ret
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"database/sql"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/database"
+)
+
+const (
+	auditLogTable = "audit_log"
+)
+
+var (
+	auditLogDBTable = database.NewTable(auditLogTable, func() interface{} {
+		return &AuditLogEntry{}
+	})
+)
+
+// AuditLogEntry records a privileged action. Entries cannot be changed once
+// created.
+type AuditLogEntry struct {
+	ID         uuid.UUID       `db:"id" json:"id"`
+	UserID     uuid.NullUUID   `db:"user_id" json:"user_id"`
+	UserName   string          `db:"user_name" json:"user_name"`
+	Action     string          `db:"action" json:"action"`
+	TargetType string          `db:"target_type" json:"target_type"`
+	TargetID   uuid.UUID       `db:"target_id" json:"target_id"`
+	Before     sql.NullString  `db:"before" json:"before"`
+	After      sql.NullString  `db:"after" json:"after"`
+	IP         string          `db:"ip" json:"ip"`
+	CreatedAt  SQLiteTimestamp `db:"created_at" json:"created_at"`
+}
+
+func (AuditLogEntry) GetTable() database.Table {
+	return auditLogDBTable
+}
+
+func (p AuditLogEntry) GetID() uuid.UUID {
+	return p.ID
+}
+
+type AuditLogEntries []*AuditLogEntry
+
+func (p AuditLogEntries) Each(fn func(interface{})) {
+	for _, v := range p {
+		fn(*v)
+	}
+}
+
+func (p *AuditLogEntries) Add(o interface{}) {
+	*p = append(*p, o.(*AuditLogEntry))
+}
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/database"
+)
+
+const (
+	notificationTable = "notifications"
+)
+
+var (
+	notificationDBTable = database.NewTable(notificationTable, func() interface{} {
+		return &Notification{}
+	})
+
+	userNotificationPreferencesTable = database.NewTableJoin(userTable, "user_notification_preferences", userJoinKey, func() interface{} {
+		return &UserNotificationPreference{}
+	})
+)
+
+// Notification is an event sent to a user.
+type Notification struct {
+	ID        uuid.UUID           `db:"id" json:"id"`
+	UserID    uuid.UUID           `db:"user_id" json:"user_id"`
+	Type      string              `db:"type" json:"type"`
+	EditID    uuid.NullUUID       `db:"edit_id" json:"edit_id"`
+	ActorID   uuid.NullUUID       `db:"actor_id" json:"actor_id"`
+	ReadAt    NullSQLiteTimestamp `db:"read_at" json:"read_at"`
+	EmailedAt NullSQLiteTimestamp `db:"emailed_at" json:"emailed_at"`
+	CreatedAt SQLiteTimestamp     `db:"created_at" json:"created_at"`
+}
+
+func (Notification) GetTable() database.Table {
+	return notificationDBTable
+}
+
+func (p Notification) GetID() uuid.UUID {
+	return p.ID
+}
+
+type Notifications []*Notification
+
+func (p Notifications) Each(fn func(interface{})) {
+	for _, v := range p {
+		fn(*v)
+	}
+}
+
+func (p *Notifications) Add(o interface{}) {
+	*p = append(*p, o.(*Notification))
+}
+
+// UserNotificationPreference sets whether a user receives notifications of
+// a type, and whether they are included in the email digest.
+type UserNotificationPreference struct {
+	UserID  uuid.UUID `db:"user_id" json:"user_id"`
+	Type    string    `db:"type" json:"type"`
+	Enabled bool      `db:"enabled" json:"enabled"`
+	Email   bool      `db:"email" json:"email"`
+}
+
+type UserNotificationPreferences []*UserNotificationPreference
+
+func (p UserNotificationPreferences) Each(fn func(interface{})) {
+	for _, v := range p {
+		fn(*v)
+	}
+}
+
+func (p *UserNotificationPreferences) Add(o interface{}) {
+	*p = append(*p, o.(*UserNotificationPreference))
+}
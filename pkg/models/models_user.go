@@ -1,6 +1,7 @@
 package models
 
 import (
+	"database/sql"
 	"fmt"
 
 	"github.com/gofrs/uuid"
@@ -22,6 +23,10 @@ var (
 	userRolesTable = database.NewTableJoin(userTable, "user_roles", userJoinKey, func() interface{} {
 		return &UserRole{}
 	})
+
+	userRecoveryCodesTable = database.NewTableJoin(userTable, "user_recovery_codes", userJoinKey, func() interface{} {
+		return &UserRecoveryCode{}
+	})
 )
 
 type User struct {
@@ -36,6 +41,24 @@ type User struct {
 	LastAPICall  SQLiteTimestamp `db:"last_api_call" json:"last_api_call"`
 	CreatedAt    SQLiteTimestamp `db:"created_at" json:"created_at"`
 	UpdatedAt    SQLiteTimestamp `db:"updated_at" json:"updated_at"`
+
+	SuccessfulEdits   int `db:"successful_edits" json:"successful_edits"`
+	UnsuccessfulEdits int `db:"unsuccessful_edits" json:"unsuccessful_edits"`
+	SuccessfulVotes   int `db:"successful_votes" json:"successful_votes"`
+	UnsuccessfulVotes int `db:"unsuccessful_votes" json:"unsuccessful_votes"`
+
+	TOTPSecret   sql.NullString `db:"totp_secret" json:"totp_secret"`
+	TOTPEnabled  bool           `db:"totp_enabled" json:"totp_enabled"`
+	TOTPLastStep int64          `db:"totp_last_step" json:"totp_last_step"`
+
+	// TOTPFailedAttempts counts the invalid codes entered since the last
+	// valid code or lockout.
+	TOTPFailedAttempts int                 `db:"totp_failed_attempts" json:"totp_failed_attempts"`
+	TOTPLockedUntil    NullSQLiteTimestamp `db:"totp_locked_until" json:"totp_locked_until"`
+
+	// SuspensionID is the moderation action suspending the user, which may
+	// have ended.
+	SuspensionID uuid.NullUUID `db:"suspension_id" json:"suspension_id"`
 }
 
 func (User) GetTable() database.Table {
@@ -50,6 +73,7 @@ func (p *User) RemoveSensitiveFields() {
 	p.PasswordHash = ""
 	p.Email = ""
 	p.APIKey = ""
+	p.TOTPSecret = sql.NullString{}
 	p.APICalls = -1
 	p.InviteTokens = -1
 }
@@ -66,6 +90,20 @@ func (p *Users) Add(o interface{}) {
 	*p = append(*p, o.(*User))
 }
 
+// UserAPICalls is the number of API calls made by a user in the bucket
+// period starting at Bucket.
+type UserAPICalls struct {
+	UserID uuid.UUID       `db:"user_id" json:"user_id"`
+	Bucket SQLiteTimestamp `db:"bucket" json:"bucket"`
+	Calls  int             `db:"calls" json:"calls"`
+}
+
+// UserAPICallCount is the total number of API calls made by a user.
+type UserAPICallCount struct {
+	UserID   uuid.UUID `db:"user_id" json:"user_id"`
+	APICalls int       `db:"api_calls" json:"api_calls"`
+}
+
 type UserRole struct {
 	UserID uuid.UUID `db:"user_id" json:"user_id"`
 	Role   string    `db:"role" json:"role"`
@@ -92,6 +130,24 @@ func (p UserRoles) ToRoles() []RoleEnum {
 	return ret
 }
 
+// UserRecoveryCode is the hash of a two-factor authentication recovery code.
+type UserRecoveryCode struct {
+	UserID   uuid.UUID `db:"user_id" json:"user_id"`
+	CodeHash string    `db:"code_hash" json:"code_hash"`
+}
+
+type UserRecoveryCodes []*UserRecoveryCode
+
+func (p UserRecoveryCodes) Each(fn func(interface{})) {
+	for _, v := range p {
+		fn(*v)
+	}
+}
+
+func (p *UserRecoveryCodes) Add(o interface{}) {
+	*p = append(*p, o.(*UserRecoveryCode))
+}
+
 func CreateUserRoles(userId uuid.UUID, roles []RoleEnum) UserRoles {
 	var ret UserRoles
 
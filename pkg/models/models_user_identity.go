@@ -0,0 +1,37 @@
+package models
+
+import (
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/database"
+)
+
+const (
+	userIdentityTable = "user_identities"
+)
+
+var (
+	userIdentityDBTable = database.NewTable(userIdentityTable, func() interface{} {
+		return &UserIdentity{}
+	})
+)
+
+// UserIdentity links a user to their identity at an OpenID Connect issuer.
+type UserIdentity struct {
+	Issuer    string          `db:"issuer" json:"issuer"`
+	Subject   string          `db:"subject" json:"subject"`
+	UserID    uuid.UUID       `db:"user_id" json:"user_id"`
+	CreatedAt SQLiteTimestamp `db:"created_at" json:"created_at"`
+}
+
+type UserIdentities []*UserIdentity
+
+func (p UserIdentities) Each(fn func(interface{})) {
+	for _, v := range p {
+		fn(*v)
+	}
+}
+
+func (p *UserIdentities) Add(o interface{}) {
+	*p = append(*p, o.(*UserIdentity))
+}
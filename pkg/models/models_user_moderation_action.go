@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/database"
+)
+
+const (
+	userModerationActionTable = "user_moderation_actions"
+)
+
+var (
+	userModerationActionDBTable = database.NewTable(userModerationActionTable, func() interface{} {
+		return &UserModerationAction{}
+	})
+)
+
+// UserModerationAction is an entry in the moderation history of a user.
+type UserModerationAction struct {
+	ID          uuid.UUID           `db:"id" json:"id"`
+	UserID      uuid.UUID           `db:"user_id" json:"user_id"`
+	ModeratorID uuid.NullUUID       `db:"moderator_id" json:"moderator_id"`
+	Action      string              `db:"action" json:"action"`
+	Reason      string              `db:"reason" json:"reason"`
+	Until       NullSQLiteTimestamp `db:"until" json:"until"`
+	CreatedAt   SQLiteTimestamp     `db:"created_at" json:"created_at"`
+}
+
+func (UserModerationAction) GetTable() database.Table {
+	return userModerationActionDBTable
+}
+
+func (p UserModerationAction) GetID() uuid.UUID {
+	return p.ID
+}
+
+// IsActiveSuspension returns true if the action is a suspension that has not
+// ended by t. Suspensions without an end time never end.
+func (p UserModerationAction) IsActiveSuspension(t time.Time) bool {
+	return p.Action == UserModerationActionEnumSuspend.String() && (!p.Until.Valid || p.Until.Timestamp.After(t))
+}
+
+type UserModerationActions []*UserModerationAction
+
+func (p UserModerationActions) Each(fn func(interface{})) {
+	for _, v := range p {
+		fn(*v)
+	}
+}
+
+func (p *UserModerationActions) Add(o interface{}) {
+	*p = append(*p, o.(*UserModerationAction))
+}
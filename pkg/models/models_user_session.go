@@ -0,0 +1,51 @@
+package models
+
+import (
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/database"
+)
+
+const (
+	userSessionTable = "user_sessions"
+)
+
+var (
+	userSessionDBTable = database.NewTable(userSessionTable, func() interface{} {
+		return &UserSession{}
+	})
+)
+
+// UserSession is a login session of a user. Only the hash of the session
+// token is stored.
+type UserSession struct {
+	ID           uuid.UUID       `db:"id" json:"id"`
+	UserID       uuid.UUID       `db:"user_id" json:"user_id"`
+	TokenHash    string          `db:"token_hash" json:"token_hash"`
+	Device       string          `db:"device" json:"device"`
+	IP           string          `db:"ip" json:"ip"`
+	UserAgent    string          `db:"user_agent" json:"user_agent"`
+	CreatedAt    SQLiteTimestamp `db:"created_at" json:"created_at"`
+	LastActiveAt SQLiteTimestamp `db:"last_active_at" json:"last_active_at"`
+	ExpiresAt    SQLiteTimestamp `db:"expires_at" json:"expires_at"`
+}
+
+func (UserSession) GetTable() database.Table {
+	return userSessionDBTable
+}
+
+func (p UserSession) GetID() uuid.UUID {
+	return p.ID
+}
+
+type UserSessions []*UserSession
+
+func (p UserSessions) Each(fn func(interface{})) {
+	for _, v := range p {
+		fn(*v)
+	}
+}
+
+func (p *UserSessions) Add(o interface{}) {
+	*p = append(*p, o.(*UserSession))
+}
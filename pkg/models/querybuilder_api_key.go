@@ -0,0 +1,80 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/stashapp/stash-box/pkg/database"
+)
+
+type APIKeyQueryBuilder struct {
+	dbi database.DBI
+}
+
+func NewAPIKeyQueryBuilder(tx *sqlx.Tx) APIKeyQueryBuilder {
+	return APIKeyQueryBuilder{
+		dbi: database.DBIWithTxn(tx),
+	}
+}
+
+func (qb *APIKeyQueryBuilder) toModel(ro interface{}) *APIKey {
+	if ro != nil {
+		return ro.(*APIKey)
+	}
+
+	return nil
+}
+
+func (qb *APIKeyQueryBuilder) Create(newKey APIKey) (*APIKey, error) {
+	ret, err := qb.dbi.Insert(newKey)
+	return qb.toModel(ret), err
+}
+
+func (qb *APIKeyQueryBuilder) CreateRoles(newJoins APIKeyRoles) error {
+	return qb.dbi.InsertJoins(apiKeyRolesTable, &newJoins)
+}
+
+func (qb *APIKeyQueryBuilder) Destroy(id uuid.UUID) error {
+	return qb.dbi.Delete(id, apiKeyDBTable)
+}
+
+func (qb *APIKeyQueryBuilder) Find(id uuid.UUID) (*APIKey, error) {
+	ret, err := qb.dbi.Find(id, apiKeyDBTable)
+	return qb.toModel(ret), err
+}
+
+func (qb *APIKeyQueryBuilder) FindByHash(hash string) (*APIKey, error) {
+	query := "SELECT * FROM " + apiKeyTable + " WHERE key_hash = ?"
+	args := []interface{}{hash}
+	output := APIKeys{}
+	if err := qb.dbi.RawQuery(apiKeyDBTable, query, args, &output); err != nil || len(output) < 1 {
+		return nil, err
+	}
+	return output[0], nil
+}
+
+func (qb *APIKeyQueryBuilder) FindByUserID(userID uuid.UUID) (APIKeys, error) {
+	query := "SELECT * FROM " + apiKeyTable + " WHERE user_id = ? ORDER BY created_at, name"
+	args := []interface{}{userID}
+	output := APIKeys{}
+	if err := qb.dbi.RawQuery(apiKeyDBTable, query, args, &output); err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+func (qb *APIKeyQueryBuilder) GetRoles(id uuid.UUID) (APIKeyRoles, error) {
+	joins := APIKeyRoles{}
+	err := qb.dbi.FindJoins(apiKeyRolesTable, id, &joins)
+
+	return joins, err
+}
+
+// UpdateLastUsed sets the last used time of the key.
+func (qb *APIKeyQueryBuilder) UpdateLastUsed(id uuid.UUID, t time.Time) error {
+	query := "UPDATE " + apiKeyTable + " SET last_used_at = ? WHERE id = ?"
+	args := []interface{}{SQLiteTimestamp{Timestamp: t}, id}
+	return qb.dbi.RawQuery(apiKeyDBTable, query, args, nil)
+}
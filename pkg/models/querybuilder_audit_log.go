@@ -0,0 +1,93 @@
+package models
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/stashapp/stash-box/pkg/database"
+)
+
+type AuditLogQueryBuilder struct {
+	dbi database.DBI
+}
+
+func NewAuditLogQueryBuilder(tx *sqlx.Tx) AuditLogQueryBuilder {
+	return AuditLogQueryBuilder{
+		dbi: database.DBIWithTxn(tx),
+	}
+}
+
+func (qb *AuditLogQueryBuilder) toModel(ro interface{}) *AuditLogEntry {
+	if ro != nil {
+		return ro.(*AuditLogEntry)
+	}
+
+	return nil
+}
+
+func (qb *AuditLogQueryBuilder) Create(newEntry AuditLogEntry) (*AuditLogEntry, error) {
+	ret, err := qb.dbi.Insert(newEntry)
+	return qb.toModel(ret), err
+}
+
+func (qb *AuditLogQueryBuilder) Query(filter *AuditLogFilterType, findFilter *QuerySpec) (AuditLogEntries, int, error) {
+	if filter == nil {
+		filter = &AuditLogFilterType{}
+	}
+	if findFilter == nil {
+		findFilter = &QuerySpec{}
+	}
+
+	query := database.NewQueryBuilder(auditLogDBTable)
+
+	if q := filter.UserID; q != nil && *q != "" {
+		userID, err := uuid.FromString(*q)
+		if err != nil {
+			return nil, 0, err
+		}
+		query.Eq("audit_log.user_id", userID)
+	}
+
+	if q := filter.Action; q != nil && *q != "" {
+		query.Eq("audit_log.action", *q)
+	}
+
+	if q := filter.TargetType; q != nil {
+		query.Eq("audit_log.target_type", q.String())
+	}
+
+	if q := filter.TargetID; q != nil && *q != "" {
+		targetID, err := uuid.FromString(*q)
+		if err != nil {
+			return nil, 0, err
+		}
+		query.Eq("audit_log.target_id", targetID)
+	}
+
+	if q := filter.CreatedAfter; q != nil {
+		query.AddWhere("audit_log.created_at >= ?")
+		query.AddArg(SQLiteTimestamp{Timestamp: *q})
+	}
+
+	if q := filter.CreatedBefore; q != nil {
+		query.AddWhere("audit_log.created_at < ?")
+		query.AddArg(SQLiteTimestamp{Timestamp: *q})
+	}
+
+	query.SortAndPagination = qb.getAuditLogSort(findFilter) + getPagination(findFilter)
+	var entries AuditLogEntries
+
+	countResult, err := qb.dbi.Query(*query, &entries)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return entries, countResult, nil
+}
+
+func (qb *AuditLogQueryBuilder) getAuditLogSort(findFilter *QuerySpec) string {
+	sort := findFilter.GetSort("created_at")
+	direction := findFilter.GetDirection()
+	return getSort(sort, direction, auditLogTable, nil)
+}
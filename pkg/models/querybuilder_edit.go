@@ -3,10 +3,12 @@ package models
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 
 	"github.com/gofrs/uuid"
 	"github.com/jmoiron/sqlx"
 	"github.com/stashapp/stash-box/pkg/database"
+	"github.com/stashapp/stash-box/pkg/utils"
 )
 
 type EditQueryBuilder struct {
@@ -54,6 +56,14 @@ func (qb *EditQueryBuilder) CreateEditPerformer(newJoin EditPerformer) error {
 	return qb.dbi.InsertJoin(editPerformerTable, newJoin, false)
 }
 
+func (qb *EditQueryBuilder) CreateEditStudio(newJoin EditStudio) error {
+	return qb.dbi.InsertJoin(editStudioTable, newJoin, false)
+}
+
+func (qb *EditQueryBuilder) CreateEditGroup(newJoin EditGroup) error {
+	return qb.dbi.InsertJoin(editGroupTable, newJoin, false)
+}
+
 func (qb *EditQueryBuilder) FindTagID(id uuid.UUID) (*uuid.UUID, error) {
 	joins := EditTags{}
 	err := qb.dbi.FindJoins(editTagTable, id, &joins)
@@ -78,6 +88,164 @@ func (qb *EditQueryBuilder) FindPerformerID(id uuid.UUID) (*uuid.UUID, error) {
 	return &joins[0].PerformerID, nil
 }
 
+func (qb *EditQueryBuilder) FindStudioID(id uuid.UUID) (*uuid.UUID, error) {
+	joins := EditStudios{}
+	err := qb.dbi.FindJoins(editStudioTable, id, &joins)
+	if err != nil {
+		return nil, err
+	}
+	if len(joins) == 0 {
+		return nil, errors.New("studio edit not found")
+	}
+	return &joins[0].StudioID, nil
+}
+
+func (qb *EditQueryBuilder) FindGroupID(id uuid.UUID) (*uuid.UUID, error) {
+	joins := EditGroups{}
+	err := qb.dbi.FindJoins(editGroupTable, id, &joins)
+	if err != nil {
+		return nil, err
+	}
+	if len(joins) == 0 {
+		return nil, errors.New("group edit not found")
+	}
+	return &joins[0].GroupID, nil
+}
+
+// findTargetIDs returns the id of the performer, studio or group targeted by
+// each of the edits. Edits of other target types are not included.
+func (qb *EditQueryBuilder) findTargetIDs(ids []uuid.UUID) (map[uuid.UUID]uuid.UUID, error) {
+	ret := make(map[uuid.UUID]uuid.UUID)
+
+	performers := EditPerformers{}
+	if err := qb.dbi.FindAllJoins(editPerformerTable, ids, &performers); err != nil {
+		return nil, err
+	}
+	for _, join := range performers {
+		ret[join.EditID] = join.PerformerID
+	}
+
+	studios := EditStudios{}
+	if err := qb.dbi.FindAllJoins(editStudioTable, ids, &studios); err != nil {
+		return nil, err
+	}
+	for _, join := range studios {
+		ret[join.EditID] = join.StudioID
+	}
+
+	groups := EditGroups{}
+	if err := qb.dbi.FindAllJoins(editGroupTable, ids, &groups); err != nil {
+		return nil, err
+	}
+	for _, join := range groups {
+		ret[join.EditID] = join.GroupID
+	}
+
+	return ret, nil
+}
+
+// GetAllURLWarnings returns the warnings of each of the edits: one for each
+// entity, other than the target and merge sources of the edit, that already
+// has a URL added by the edit. Only pending performer, studio and group edits
+// can have warnings, since tags have no URLs.
+func (qb *EditQueryBuilder) GetAllURLWarnings(ids []uuid.UUID) ([][]*EditWarning, []error) {
+	query := `
+		SELECT edits.* FROM edits
+		WHERE id IN (?)
+	`
+	query, args, _ := sqlx.In(query, ids)
+	edits, err := qb.queryEdits(query, args)
+	if err != nil {
+		return nil, utils.DuplicateError(err, len(ids))
+	}
+
+	targetIDs, err := qb.findTargetIDs(ids)
+	if err != nil {
+		return nil, utils.DuplicateError(err, len(ids))
+	}
+
+	addedURLs := make(map[uuid.UUID][]*URL)
+	excluded := make(map[uuid.UUID]map[uuid.UUID]bool)
+	var urls []string
+	for _, edit := range edits {
+		editURLs, mergeSources, err := getURLWarningInput(edit)
+		if err != nil {
+			return nil, utils.DuplicateError(err, len(ids))
+		}
+
+		addedURLs[edit.ID] = editURLs
+		excluded[edit.ID] = make(map[uuid.UUID]bool)
+		if targetID, found := targetIDs[edit.ID]; found {
+			excluded[edit.ID][targetID] = true
+		}
+		for _, id := range mergeSources {
+			excluded[edit.ID][uuid.FromStringOrNil(id)] = true
+		}
+		for _, url := range editURLs {
+			urls = append(urls, url.URL)
+		}
+	}
+
+	matches, err := findMatchedURLs(qb.dbi, urls)
+	if err != nil {
+		return nil, utils.DuplicateError(err, len(ids))
+	}
+	matchesByURL := make(map[string][]*MatchedURL)
+	for _, match := range matches {
+		matchesByURL[match.URL] = append(matchesByURL[match.URL], match)
+	}
+
+	result := make([][]*EditWarning, len(ids))
+	for i, id := range ids {
+		result[i] = []*EditWarning{}
+		for _, url := range addedURLs[id] {
+			urlString := url.URL
+			for _, match := range matchesByURL[urlString] {
+				if excluded[id][match.ID] {
+					continue
+				}
+				result[i] = append(result[i], &EditWarning{
+					Type:    EditWarningTypeEnumURLAlreadyAttached,
+					Message: fmt.Sprintf("URL %s is already attached to %s %s", urlString, match.TargetType, match.Name),
+					URL:     &urlString,
+				})
+			}
+		}
+	}
+	return result, nil
+}
+
+// getURLWarningInput returns the URLs added by the edit and the ids of its
+// merge sources, or nothing if the edit cannot have URL warnings.
+func getURLWarningInput(edit *Edit) ([]*URL, []string, error) {
+	if edit.Status != VoteStatusEnumPending.String() || edit.Operation == OperationEnumDestroy.String() {
+		return nil, nil, nil
+	}
+
+	switch edit.TargetType {
+	case TargetTypeEnumPerformer.String():
+		data, err := edit.GetPerformerData()
+		if err != nil || data.New == nil {
+			return nil, nil, err
+		}
+		return data.New.AddedUrls, data.MergeSources, nil
+	case TargetTypeEnumStudio.String():
+		data, err := edit.GetStudioData()
+		if err != nil || data.New == nil {
+			return nil, nil, err
+		}
+		return data.New.AddedUrls, data.MergeSources, nil
+	case TargetTypeEnumGroup.String():
+		data, err := edit.GetGroupData()
+		if err != nil || data.New == nil {
+			return nil, nil, err
+		}
+		return data.New.AddedUrls, data.MergeSources, nil
+	}
+
+	return nil, nil, nil
+}
+
 // func (qb *SceneQueryBuilder) FindByStudioID(sceneID int) ([]*Scene, error) {
 // 	query := `
 // 		SELECT scenes.* FROM scenes
@@ -149,6 +317,12 @@ func (qb *EditQueryBuilder) Query(editFilter *EditFilterType, findFilter *QueryS
 			query.AddWhere("(" + editPerformerTable.Name() + ".performer_id = ? OR " + editDBTable.Name() + ".data->'merge_sources' @> ?)")
 			jsonID, _ := json.Marshal(*q)
 			query.AddArg(*q, jsonID)
+		} else if *editFilter.TargetType == "STUDIO" {
+			query.AddJoin(editStudioTable.Table, editStudioTable.Name()+".edit_id = edits.id")
+			query.Eq(editStudioTable.Name()+".studio_id", *q)
+		} else if *editFilter.TargetType == "GROUP" {
+			query.AddJoin(editGroupTable.Table, editGroupTable.Name()+".edit_id = edits.id")
+			query.Eq(editGroupTable.Name()+".group_id", *q)
 		} else {
 			panic("TargetType is not yet supported: " + *editFilter.TargetType)
 		}
@@ -209,6 +383,34 @@ func (qb *EditQueryBuilder) GetComments(id uuid.UUID) (EditComments, error) {
 	return joins, err
 }
 
+// CreateVote stores the vote, replacing any earlier vote of the same user on
+// the edit, and updates the vote count of the edit.
+func (qb *EditQueryBuilder) CreateVote(newJoin VoteComment) error {
+	query := "DELETE FROM edit_votes WHERE edit_id = ? AND user_id = ?"
+	args := []interface{}{newJoin.EditID, newJoin.UserID}
+	if err := qb.dbi.RawQuery(editVoteTable.Table, query, args, nil); err != nil {
+		return err
+	}
+
+	if err := qb.dbi.InsertJoin(editVoteTable, newJoin, false); err != nil {
+		return err
+	}
+
+	query = `UPDATE edits SET votes = (
+			SELECT COUNT(*) FILTER (WHERE type = ?) - COUNT(*) FILTER (WHERE type = ?)
+			FROM edit_votes WHERE edit_id = ?
+		) WHERE id = ?`
+	args = []interface{}{VoteTypeEnumAccept.String(), VoteTypeEnumReject.String(), newJoin.EditID, newJoin.EditID}
+	return qb.dbi.RawQuery(editDBTable, query, args, nil)
+}
+
+func (qb *EditQueryBuilder) GetVotes(id uuid.UUID) (VoteComments, error) {
+	joins := VoteComments{}
+	err := qb.dbi.FindJoins(editVoteTable, id, &joins)
+
+	return joins, err
+}
+
 func (qb *EditQueryBuilder) FindByTagID(id uuid.UUID) ([]*Edit, error) {
 	query := `
         SELECT edits.* FROM edits
@@ -228,3 +430,48 @@ func (qb *EditQueryBuilder) FindByPerformerID(id uuid.UUID) ([]*Edit, error) {
 	args := []interface{}{id}
 	return qb.queryEdits(query, args)
 }
+
+func (qb *EditQueryBuilder) FindByGroupID(id uuid.UUID) ([]*Edit, error) {
+	query := `
+        SELECT edits.* FROM edits
+        JOIN group_edits
+        ON group_edits.edit_id = edits.id
+        WHERE group_edits.group_id = ?`
+	args := []interface{}{id}
+	return qb.queryEdits(query, args)
+}
+
+// FindAppliedByPerformerID returns the applied edits that modified the
+// performer or merged it into another performer, in the order they were
+// applied.
+func (qb *EditQueryBuilder) FindAppliedByPerformerID(id uuid.UUID) ([]*Edit, error) {
+	query := `
+        SELECT edits.* FROM edits
+        WHERE edits.applied = TRUE
+        AND edits.target_type = ?
+        AND (
+            EXISTS (SELECT 1 FROM performer_edits WHERE performer_edits.edit_id = edits.id AND performer_edits.performer_id = ?)
+            OR edits.data->'merge_sources' @> ?
+        )
+        ORDER BY edits.updated_at, edits.created_at`
+	jsonID, _ := json.Marshal(id.String())
+	args := []interface{}{TargetTypeEnumPerformer.String(), id, jsonID}
+	return qb.queryEdits(query, args)
+}
+
+// FindAppliedByTagID returns the applied edits that modified the tag or
+// merged it into another tag, in the order they were applied.
+func (qb *EditQueryBuilder) FindAppliedByTagID(id uuid.UUID) ([]*Edit, error) {
+	query := `
+        SELECT edits.* FROM edits
+        WHERE edits.applied = TRUE
+        AND edits.target_type = ?
+        AND (
+            EXISTS (SELECT 1 FROM tag_edits WHERE tag_edits.edit_id = edits.id AND tag_edits.tag_id = ?)
+            OR edits.data->'merge_sources' @> ?
+        )
+        ORDER BY edits.updated_at, edits.created_at`
+	jsonID, _ := json.Marshal(id.String())
+	args := []interface{}{TargetTypeEnumTag.String(), id, jsonID}
+	return qb.queryEdits(query, args)
+}
@@ -0,0 +1,377 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/stashapp/stash-box/pkg/database"
+	"github.com/stashapp/stash-box/pkg/utils"
+)
+
+type GroupQueryBuilder struct {
+	dbi database.DBI
+}
+
+func NewGroupQueryBuilder(tx *sqlx.Tx) GroupQueryBuilder {
+	return GroupQueryBuilder{
+		dbi: database.DBIWithTxn(tx),
+	}
+}
+
+func (qb *GroupQueryBuilder) toModel(ro interface{}) *Group {
+	if ro != nil {
+		return ro.(*Group)
+	}
+
+	return nil
+}
+
+func (qb *GroupQueryBuilder) Create(newGroup Group) (*Group, error) {
+	ret, err := qb.dbi.Insert(newGroup)
+	return qb.toModel(ret), err
+}
+
+func (qb *GroupQueryBuilder) Update(updatedGroup Group) (*Group, error) {
+	ret, err := qb.dbi.Update(updatedGroup, true)
+	return qb.toModel(ret), err
+}
+
+func (qb *GroupQueryBuilder) Destroy(id uuid.UUID) error {
+	return qb.dbi.Delete(id, groupDBTable)
+}
+
+func (qb *GroupQueryBuilder) SoftDelete(group Group) (*Group, error) {
+	// Delete joins
+	if err := qb.dbi.DeleteJoins(groupUrlTable, group.ID); err != nil {
+		return nil, err
+	}
+	if err := qb.dbi.DeleteJoins(groupSceneTable, group.ID); err != nil {
+		return nil, err
+	}
+
+	ret, err := qb.dbi.SoftDelete(group)
+	return qb.toModel(ret), err
+}
+
+func (qb *GroupQueryBuilder) CreateUrls(newJoins GroupUrls) error {
+	return qb.dbi.InsertJoins(groupUrlTable, &newJoins)
+}
+
+func (qb *GroupQueryBuilder) UpdateUrls(groupID uuid.UUID, updatedJoins GroupUrls) error {
+	return qb.dbi.ReplaceJoins(groupUrlTable, groupID, &updatedJoins)
+}
+
+func (qb *GroupQueryBuilder) CreateScenes(newJoins GroupScenes) error {
+	return qb.dbi.InsertJoins(groupSceneTable, &newJoins)
+}
+
+func (qb *GroupQueryBuilder) UpdateScenes(groupID uuid.UUID, updatedJoins GroupScenes) error {
+	return qb.dbi.ReplaceJoins(groupSceneTable, groupID, &updatedJoins)
+}
+
+func (qb *GroupQueryBuilder) Find(id uuid.UUID) (*Group, error) {
+	ret, err := qb.dbi.Find(id, groupDBTable)
+	return qb.toModel(ret), err
+}
+
+func (qb *GroupQueryBuilder) FindByIds(ids []uuid.UUID) ([]*Group, []error) {
+	query := "SELECT groups.* FROM groups WHERE id IN (?)"
+	query, args, _ := sqlx.In(query, ids)
+	groups, err := qb.queryGroups(query, args)
+	if err != nil {
+		return nil, utils.DuplicateError(err, len(ids))
+	}
+
+	m := make(map[uuid.UUID]*Group)
+	for _, group := range groups {
+		m[group.ID] = group
+	}
+
+	result := make([]*Group, len(ids))
+	for i, id := range ids {
+		result[i] = m[id]
+	}
+	return result, nil
+}
+
+func (qb *GroupQueryBuilder) Count() (int, error) {
+	return runCountQuery(buildCountQuery("SELECT groups.id FROM groups"), nil)
+}
+
+func (qb *GroupQueryBuilder) Query(groupFilter *GroupFilterType, findFilter *QuerySpec) ([]*Group, int) {
+	if groupFilter == nil {
+		groupFilter = &GroupFilterType{}
+	}
+	if findFilter == nil {
+		findFilter = &QuerySpec{}
+	}
+
+	query := database.NewQueryBuilder(groupDBTable)
+	query.Eq("deleted", false)
+
+	if q := groupFilter.Title; q != nil && *q != "" {
+		searchColumns := []string{"groups.title"}
+		clause, thisArgs := getSearchBinding(searchColumns, *q, false, false)
+		query.AddWhere(clause)
+		query.AddArg(thisArgs...)
+	}
+
+	handleDateCriterion("groups.date", groupFilter.Date, query)
+
+	if q := groupFilter.Studios; q != nil && len(q.Value) > 0 {
+		column := "groups.studio_id"
+		if q.Modifier == CriterionModifierEquals {
+			query.Eq(column, q.Value[0])
+		} else if q.Modifier == CriterionModifierNotEquals {
+			query.NotEq(column, q.Value[0])
+		} else if q.Modifier == CriterionModifierIsNull {
+			query.IsNull(column)
+		} else if q.Modifier == CriterionModifierNotNull {
+			query.IsNotNull(column)
+		} else if q.Modifier == CriterionModifierIncludes {
+			query.AddWhere(column + " IN " + getInBinding(len(q.Value)))
+			for _, studioID := range q.Value {
+				query.AddArg(studioID)
+			}
+		} else if q.Modifier == CriterionModifierExcludes {
+			query.AddWhere(column + " NOT IN " + getInBinding(len(q.Value)))
+			for _, studioID := range q.Value {
+				query.AddArg(studioID)
+			}
+		} else {
+			panic("unsupported modifier " + q.Modifier + " for groups.studio_id")
+		}
+	}
+
+	if q := groupFilter.SceneID; q != nil && *q != "" {
+		query.AddWhere("EXISTS (SELECT 1 FROM group_scenes WHERE group_scenes.group_id = groups.id AND group_scenes.scene_id = ?)")
+		query.AddArg(*q)
+	}
+
+	query.SortAndPagination = qb.getGroupSort(findFilter) + getPagination(findFilter)
+
+	var groups Groups
+	countResult, err := qb.dbi.Query(*query, &groups)
+
+	if err != nil {
+		// TODO
+		panic(err)
+	}
+
+	return groups, countResult
+}
+
+func (qb *GroupQueryBuilder) getGroupSort(findFilter *QuerySpec) string {
+	var sort string
+	var direction string
+	var secondary *string
+	if findFilter == nil {
+		sort = "title"
+		direction = "ASC"
+	} else {
+		sort = findFilter.GetSort("title")
+		direction = findFilter.GetDirection()
+	}
+	if sort != "title" {
+		title := "title"
+		secondary = &title
+	}
+	return getSort(sort, direction, "groups", secondary)
+}
+
+// FindByURL returns the non-deleted groups with a URL matching the provided
+// URL once both are normalized.
+func (qb *GroupQueryBuilder) FindByURL(url string) (Groups, error) {
+	query := `
+		SELECT groups.* FROM groups
+		WHERE groups.deleted = FALSE
+		AND EXISTS (
+			SELECT 1 FROM group_urls
+			WHERE group_urls.group_id = groups.id
+			AND normalize_url(group_urls.url) = normalize_url(?)
+		)
+		ORDER BY groups.title`
+	args := []interface{}{url}
+	return qb.queryGroups(query, args)
+}
+
+func (qb *GroupQueryBuilder) queryGroups(query string, args []interface{}) (Groups, error) {
+	output := Groups{}
+	err := qb.dbi.RawQuery(groupDBTable, query, args, &output)
+	return output, err
+}
+
+func (qb *GroupQueryBuilder) GetUrls(id uuid.UUID) (GroupUrls, error) {
+	joins := GroupUrls{}
+	err := qb.dbi.FindJoins(groupUrlTable, id, &joins)
+
+	return joins, err
+}
+
+func (qb *GroupQueryBuilder) GetAllUrls(ids []uuid.UUID) ([][]*URL, []error) {
+	joins := GroupUrls{}
+	err := qb.dbi.FindAllJoins(groupUrlTable, ids, &joins)
+	if err != nil {
+		return nil, utils.DuplicateError(err, len(ids))
+	}
+
+	m := make(map[uuid.UUID][]*URL)
+	for _, join := range joins {
+		url := join.ToURL()
+		m[join.GroupID] = append(m[join.GroupID], &url)
+	}
+
+	result := make([][]*URL, len(ids))
+	for i, id := range ids {
+		result[i] = m[id]
+	}
+	return result, nil
+}
+
+func (qb *GroupQueryBuilder) GetScenes(id uuid.UUID) (GroupScenes, error) {
+	joins := GroupScenes{}
+	err := qb.dbi.FindJoins(groupSceneTable, id, &joins)
+	joins.Sort()
+
+	return joins, err
+}
+
+func (qb *GroupQueryBuilder) GetAllScenes(ids []uuid.UUID) ([]GroupScenes, []error) {
+	joins := GroupScenes{}
+	err := qb.dbi.FindAllJoins(groupSceneTable, ids, &joins)
+	if err != nil {
+		return nil, utils.DuplicateError(err, len(ids))
+	}
+
+	m := make(map[uuid.UUID]GroupScenes)
+	for _, join := range joins {
+		m[join.GroupID] = append(m[join.GroupID], join)
+	}
+
+	result := make([]GroupScenes, len(ids))
+	for i, id := range ids {
+		result[i] = m[id]
+		result[i].Sort()
+	}
+	return result, nil
+}
+
+// GetAllSceneGroups returns the group memberships of each of the provided
+// scene ids.
+func (qb *GroupQueryBuilder) GetAllSceneGroups(ids []uuid.UUID) ([]GroupScenes, []error) {
+	joins := GroupScenes{}
+	err := qb.dbi.FindAllJoins(sceneGroupTable, ids, &joins)
+	if err != nil {
+		return nil, utils.DuplicateError(err, len(ids))
+	}
+
+	m := make(map[uuid.UUID]GroupScenes)
+	for _, join := range joins {
+		m[join.SceneID] = append(m[join.SceneID], join)
+	}
+
+	result := make([]GroupScenes, len(ids))
+	for i, id := range ids {
+		result[i] = m[id]
+	}
+	return result, nil
+}
+
+func (qb *GroupQueryBuilder) ApplyEdit(edit Edit, operation OperationEnum, group *Group) (*Group, error) {
+	data, err := edit.GetGroupData()
+	if err != nil {
+		return nil, err
+	}
+
+	switch operation {
+	case OperationEnumCreate:
+		now := time.Now()
+		UUID, err := uuid.NewV4()
+		if err != nil {
+			return nil, err
+		}
+		newGroup := Group{
+			ID:        UUID,
+			CreatedAt: SQLiteTimestamp{Timestamp: now},
+			UpdatedAt: SQLiteTimestamp{Timestamp: now},
+		}
+		if data.New.Title == nil {
+			return nil, errors.New("Missing group title")
+		}
+
+		newGroup.CopyFromGroupEdit(*data.New, GroupEdit{})
+
+		group, err = qb.Create(newGroup)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(data.New.AddedUrls) > 0 {
+			urls := CreateGroupUrls(UUID, data.New.AddedUrls)
+			if err := qb.CreateUrls(urls); err != nil {
+				return nil, err
+			}
+		}
+
+		if len(data.New.AddedScenes) > 0 {
+			scenes := CreateGroupScenes(UUID, data.New.AddedScenes)
+			if err := qb.CreateScenes(scenes); err != nil {
+				return nil, err
+			}
+		}
+
+		return group, nil
+	case OperationEnumDestroy:
+		return qb.SoftDelete(*group)
+	case OperationEnumModify:
+		return qb.ApplyModifyEdit(group, data)
+	default:
+		return nil, errors.New("Unsupported operation: " + operation.String())
+	}
+}
+
+func (qb *GroupQueryBuilder) ApplyModifyEdit(group *Group, data *GroupEditData) (*Group, error) {
+	if err := group.ValidateModifyEdit(*data); err != nil {
+		return nil, err
+	}
+
+	group.CopyFromGroupEdit(*data.New, *data.Old)
+	group.UpdatedAt = SQLiteTimestamp{Timestamp: time.Now()}
+	updatedGroup, err := qb.Update(*group)
+	if err != nil {
+		return nil, err
+	}
+
+	currentUrls, err := qb.GetUrls(updatedGroup.ID)
+	if err != nil {
+		return nil, err
+	}
+	newUrls := CreateGroupUrls(updatedGroup.ID, data.New.AddedUrls)
+	oldUrls := CreateGroupUrls(updatedGroup.ID, data.New.RemovedUrls)
+
+	if err := ProcessSlice(&currentUrls, &newUrls, &oldUrls); err != nil {
+		return nil, err
+	}
+	if err := qb.UpdateUrls(updatedGroup.ID, currentUrls); err != nil {
+		return nil, err
+	}
+
+	currentScenes, err := qb.GetScenes(updatedGroup.ID)
+	if err != nil {
+		return nil, err
+	}
+	newScenes := CreateGroupScenes(updatedGroup.ID, data.New.AddedScenes)
+	oldScenes := CreateGroupScenes(updatedGroup.ID, data.New.RemovedScenes)
+
+	if err := ProcessSlice(&currentScenes, &newScenes, &oldScenes); err != nil {
+		return nil, err
+	}
+	if err := qb.UpdateScenes(updatedGroup.ID, currentScenes); err != nil {
+		return nil, err
+	}
+
+	return updatedGroup, nil
+}
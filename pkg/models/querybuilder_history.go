@@ -0,0 +1,230 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// editAppliedAt returns the time the edit was applied. Edits are not
+// modified after they are applied, so the update time is used.
+func editAppliedAt(edit *Edit) time.Time {
+	return edit.UpdatedAt.Timestamp
+}
+
+func isMergeSource(mergeSources []string, id uuid.UUID) bool {
+	return containsString(mergeSources, id.String())
+}
+
+// FindPerformerAsOf reconstructs the state of the performer at the given
+// time by reverting the edits applied to it since. Returns nil if the
+// performer did not exist at that time. Changes made outside of edits are
+// not reverted.
+func FindPerformerAsOf(tx *sqlx.Tx, id uuid.UUID, asOf time.Time) (*Performer, error) {
+	pqb := NewPerformerQueryBuilder(tx)
+	performer, err := pqb.Find(id)
+	if err != nil || performer == nil {
+		return nil, err
+	}
+
+	if performer.CreatedAt.Timestamp.After(asOf) {
+		return nil, nil
+	}
+
+	eqb := NewEditQueryBuilder(tx)
+	edits, err := eqb.FindAppliedByPerformerID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, err := getPerformerSnapshot(tx, id)
+	if err != nil {
+		return nil, err
+	}
+	performer.Snapshot = snapshot
+
+	reverted := false
+	for i := len(edits) - 1; i >= 0; i-- {
+		edit := edits[i]
+		if !editAppliedAt(edit).After(asOf) {
+			if reverted {
+				performer.UpdatedAt = SQLiteTimestamp{Timestamp: editAppliedAt(edit)}
+			}
+			break
+		}
+
+		data, err := edit.GetPerformerData()
+		if err != nil {
+			return nil, err
+		}
+
+		switch OperationEnum(edit.Operation) {
+		case OperationEnumCreate:
+			return nil, nil
+		case OperationEnumDestroy:
+			performer.Deleted = false
+		case OperationEnumMerge:
+			if isMergeSource(data.MergeSources, id) {
+				performer.Deleted = false
+			} else {
+				performer.UndoPerformerEdit(*data)
+			}
+		case OperationEnumModify:
+			performer.UndoPerformerEdit(*data)
+		}
+
+		reverted = true
+		performer.UpdatedAt = performer.CreatedAt
+	}
+
+	return performer, nil
+}
+
+func getPerformerSnapshot(tx *sqlx.Tx, id uuid.UUID) (*PerformerSnapshot, error) {
+	pqb := NewPerformerQueryBuilder(tx)
+
+	aliases, err := pqb.GetAliases(id)
+	if err != nil {
+		return nil, err
+	}
+	urls, err := pqb.GetUrls(id)
+	if err != nil {
+		return nil, err
+	}
+	tattoos, err := pqb.GetTattoos(id)
+	if err != nil {
+		return nil, err
+	}
+	piercings, err := pqb.GetPiercings(id)
+	if err != nil {
+		return nil, err
+	}
+
+	iqb := NewImageQueryBuilder(tx)
+	images, err := iqb.FindByPerformerID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	imageIDs := []string{}
+	for _, image := range images {
+		imageIDs = append(imageIDs, image.ID.String())
+	}
+
+	return &PerformerSnapshot{
+		Aliases:   aliases.ToAliases(),
+		Urls:      urls,
+		Tattoos:   tattoos.ToBodyModifications(),
+		Piercings: piercings.ToBodyModifications(),
+		ImageIDs:  imageIDs,
+	}, nil
+}
+
+// FindTagAsOf reconstructs the state of the tag at the given time by
+// reverting the edits applied to it since. Returns nil if the tag did not
+// exist at that time. Changes made outside of edits are not reverted.
+func FindTagAsOf(tx *sqlx.Tx, id uuid.UUID, asOf time.Time) (*Tag, error) {
+	tqb := NewTagQueryBuilder(tx)
+	tag, err := tqb.Find(id)
+	if err != nil || tag == nil {
+		return nil, err
+	}
+
+	if tag.CreatedAt.Timestamp.After(asOf) {
+		return nil, nil
+	}
+
+	eqb := NewEditQueryBuilder(tx)
+	edits, err := eqb.FindAppliedByTagID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	aliases, err := tqb.GetAliases(id)
+	if err != nil {
+		return nil, err
+	}
+	tag.Snapshot = &TagSnapshot{Aliases: aliases}
+
+	reverted := false
+	for i := len(edits) - 1; i >= 0; i-- {
+		edit := edits[i]
+		if !editAppliedAt(edit).After(asOf) {
+			if reverted {
+				tag.UpdatedAt = SQLiteTimestamp{Timestamp: editAppliedAt(edit)}
+			}
+			break
+		}
+
+		data, err := edit.GetTagData()
+		if err != nil {
+			return nil, err
+		}
+
+		switch OperationEnum(edit.Operation) {
+		case OperationEnumCreate:
+			return nil, nil
+		case OperationEnumDestroy:
+			tag.Deleted = false
+		case OperationEnumMerge:
+			if isMergeSource(data.MergeSources, id) {
+				tag.Deleted = false
+			} else {
+				tag.UndoTagEdit(*data)
+			}
+		case OperationEnumModify:
+			tag.UndoTagEdit(*data)
+		}
+
+		reverted = true
+		tag.UpdatedAt = tag.CreatedAt
+	}
+
+	return tag, nil
+}
+
+// GetPerformerHistory returns the field changes made to the performer by
+// applied edits, most recent first.
+func GetPerformerHistory(tx *sqlx.Tx, id uuid.UUID) ([]*EntityFieldChange, error) {
+	eqb := NewEditQueryBuilder(tx)
+	edits, err := eqb.FindAppliedByPerformerID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := []*EntityFieldChange{}
+	for i := len(edits) - 1; i >= 0; i-- {
+		edit := edits[i]
+		data, err := edit.GetPerformerData()
+		if err != nil {
+			return nil, err
+		}
+
+		switch OperationEnum(edit.Operation) {
+		case OperationEnumDestroy:
+			ret = append(ret, deletedFieldChange(edit))
+		case OperationEnumMerge:
+			if isMergeSource(data.MergeSources, id) {
+				ret = append(ret, deletedFieldChange(edit))
+			} else {
+				ret = append(ret, EditFieldChanges(edit, data.New, data.Old)...)
+			}
+		default:
+			ret = append(ret, EditFieldChanges(edit, data.New, data.Old)...)
+		}
+	}
+
+	return ret, nil
+}
+
+func deletedFieldChange(edit *Edit) *EntityFieldChange {
+	oldValue := "false"
+	newValue := "true"
+	return &EntityFieldChange{
+		Edit:     edit,
+		Field:    "deleted",
+		OldValue: &oldValue,
+		NewValue: &newValue,
+	}
+}
@@ -110,7 +110,18 @@ func (qb *ImageQueryBuilder) FindUnused() ([]*Image, error) {
 		scene_images.scene_id IS NULL AND 
 		performer_images.performer_id IS NULL AND
 		studio_images IS NULL AND
-		edit_images IS NULL LIMIT 1000
+		edit_images IS NULL AND
+		NOT EXISTS (
+			SELECT 1 FROM groups
+			WHERE groups.front_image_id = images.id OR groups.back_image_id = images.id
+		) AND
+		NOT EXISTS (
+			SELECT 1 FROM edits
+			WHERE status = 'PENDING' AND (
+				(data#>>'{new_data,front_image_id}')::uuid = images.id OR
+				(data#>>'{new_data,back_image_id}')::uuid = images.id
+			)
+		) LIMIT 1000
 	`
 	args := []interface{}{}
 
@@ -137,6 +148,14 @@ func (qb *ImageQueryBuilder) IsUnused(imageID uuid.UUID) (bool, error) {
 	query.AddWhere("performer_images.performer_id IS NULL")
 	query.AddWhere("studio_images.studio_id IS NULL")
 	query.AddWhere("edit_images.image_id IS NULL")
+	query.AddWhere("NOT EXISTS (SELECT 1 FROM groups WHERE groups.front_image_id = images.id OR groups.back_image_id = images.id)")
+	query.AddWhere(`NOT EXISTS (
+		SELECT 1 FROM edits
+		WHERE status = 'PENDING' AND (
+			(data#>>'{new_data,front_image_id}')::uuid = images.id OR
+			(data#>>'{new_data,back_image_id}')::uuid = images.id
+		)
+	)`)
 
 	count, err := qb.dbi.Count(*query)
 	if err != nil {
@@ -0,0 +1,137 @@
+package models
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/stashapp/stash-box/pkg/database"
+)
+
+type NotificationQueryBuilder struct {
+	dbi database.DBI
+}
+
+func NewNotificationQueryBuilder(tx *sqlx.Tx) NotificationQueryBuilder {
+	return NotificationQueryBuilder{
+		dbi: database.DBIWithTxn(tx),
+	}
+}
+
+func (qb *NotificationQueryBuilder) toModel(ro interface{}) *Notification {
+	if ro != nil {
+		return ro.(*Notification)
+	}
+
+	return nil
+}
+
+func (qb *NotificationQueryBuilder) Create(newNotification Notification) (*Notification, error) {
+	ret, err := qb.dbi.Insert(newNotification)
+	return qb.toModel(ret), err
+}
+
+// Query returns the notifications of the user, most recent first by default.
+func (qb *NotificationQueryBuilder) Query(userID uuid.UUID, unreadOnly bool, findFilter *QuerySpec) (Notifications, int, error) {
+	if findFilter == nil {
+		findFilter = &QuerySpec{}
+	}
+
+	query := database.NewQueryBuilder(notificationDBTable)
+	query.Eq("notifications.user_id", userID)
+
+	if unreadOnly {
+		query.AddWhere("notifications.read_at IS NULL")
+	}
+
+	query.SortAndPagination = qb.getNotificationSort(findFilter) + getPagination(findFilter)
+	var notifications Notifications
+
+	countResult, err := qb.dbi.Query(*query, &notifications)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return notifications, countResult, nil
+}
+
+func (qb *NotificationQueryBuilder) getNotificationSort(findFilter *QuerySpec) string {
+	sort := findFilter.GetSort("created_at")
+	direction := findFilter.GetDirection()
+	return getSort(sort, direction, notificationTable, nil)
+}
+
+func (qb *NotificationQueryBuilder) CountUnread(userID uuid.UUID) (int, error) {
+	query := "SELECT notifications.id FROM notifications WHERE user_id = ? AND read_at IS NULL"
+	args := []interface{}{userID}
+	return runCountQuery(buildCountQuery(query), args)
+}
+
+// MarkRead sets the unread notifications of the user with the provided ids as
+// read. All unread notifications of the user are marked if ids is nil.
+// Returns the number of notifications marked.
+func (qb *NotificationQueryBuilder) MarkRead(userID uuid.UUID, ids []uuid.UUID, t SQLiteTimestamp) (int, error) {
+	query := "UPDATE notifications SET read_at = ? WHERE user_id = ? AND read_at IS NULL"
+	args := []interface{}{t, userID}
+
+	if ids != nil {
+		if len(ids) == 0 {
+			return 0, nil
+		}
+
+		var err error
+		query, args, err = sqlx.In(query+" AND id IN (?)", t, userID, ids)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	output := Notifications{}
+	if err := qb.dbi.RawQuery(notificationDBTable, query+" RETURNING *", args, &output); err != nil {
+		return 0, err
+	}
+
+	return len(output), nil
+}
+
+// FindForDigest returns the unread notifications that have not been emailed,
+// and whose recipients have enabled emails for their type. The notifications
+// are grouped by recipient, oldest first.
+func (qb *NotificationQueryBuilder) FindForDigest() (Notifications, error) {
+	query := `
+        SELECT notifications.* FROM notifications
+        JOIN user_notification_preferences
+        ON user_notification_preferences.user_id = notifications.user_id
+        AND user_notification_preferences.type = notifications.type
+        WHERE user_notification_preferences.email = TRUE
+        AND notifications.read_at IS NULL
+        AND notifications.emailed_at IS NULL
+        ORDER BY notifications.user_id, notifications.created_at`
+	output := Notifications{}
+	if err := qb.dbi.RawQuery(notificationDBTable, query, nil, &output); err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+func (qb *NotificationQueryBuilder) MarkEmailed(ids []uuid.UUID, t SQLiteTimestamp) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	query, args, err := sqlx.In("UPDATE notifications SET emailed_at = ? WHERE id IN (?)", t, ids)
+	if err != nil {
+		return err
+	}
+	return qb.dbi.RawQuery(notificationDBTable, query, args, nil)
+}
+
+func (qb *NotificationQueryBuilder) GetPreferences(userID uuid.UUID) (UserNotificationPreferences, error) {
+	joins := UserNotificationPreferences{}
+	err := qb.dbi.FindJoins(userNotificationPreferencesTable, userID, &joins)
+
+	return joins, err
+}
+
+func (qb *NotificationQueryBuilder) UpdatePreferences(userID uuid.UUID, updatedJoins UserNotificationPreferences) error {
+	return qb.dbi.ReplaceJoins(userNotificationPreferencesTable, userID, &updatedJoins)
+}
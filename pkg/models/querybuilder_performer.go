@@ -1,6 +1,7 @@
 package models
 
 import (
+	"database/sql"
 	"errors"
 	"strconv"
 	"time"
@@ -114,6 +115,22 @@ func (qb *PerformerQueryBuilder) FindByIds(ids []uuid.UUID) ([]*Performer, []err
 	return result, nil
 }
 
+// FindByURL returns the non-deleted performers with a URL matching the
+// provided URL once both are normalized.
+func (qb *PerformerQueryBuilder) FindByURL(url string) (Performers, error) {
+	query := `
+		SELECT performers.* FROM performers
+		WHERE performers.deleted = FALSE
+		AND EXISTS (
+			SELECT 1 FROM performer_urls
+			WHERE performer_urls.performer_id = performers.id
+			AND normalize_url(performer_urls.url) = normalize_url(?)
+		)
+		ORDER BY performers.name`
+	args := []interface{}{url}
+	return qb.queryPerformers(query, args)
+}
+
 func (qb *PerformerQueryBuilder) FindBySceneID(sceneID uuid.UUID) (Performers, error) {
 	query := `
 		SELECT performers.* FROM performers
@@ -163,6 +180,57 @@ func (qb *PerformerQueryBuilder) FindByAlias(name string) (Performers, error) {
 	return qb.queryPerformers(query, args)
 }
 
+// FindRedirectTarget returns the performer that the deleted performer was
+// merged into, or nil if it was not merged.
+func (qb *PerformerQueryBuilder) FindRedirectTarget(id uuid.UUID) (*Performer, error) {
+	query := `SELECT performers.* FROM performers
+		JOIN performer_redirects ON performer_redirects.target_id = performers.id
+		WHERE performer_redirects.source_id = ?`
+
+	args := []interface{}{id}
+	results, err := qb.queryPerformers(query, args)
+	if err != nil || len(results) < 1 {
+		return nil, err
+	}
+	return results[0], nil
+}
+
+// ResolveName returns the distinct performers with the name, or with the
+// alias if no performer has the name. Redirects of merged performers are
+// followed.
+func (qb *PerformerQueryBuilder) ResolveName(name string) (Performers, error) {
+	performers, err := qb.FindByName(name)
+	if err != nil {
+		return nil, err
+	}
+	if len(performers) == 0 {
+		performers, err = qb.FindByAlias(name)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var ret Performers
+	found := make(map[uuid.UUID]bool)
+	for _, performer := range performers {
+		if performer.Deleted {
+			performer, err = qb.FindRedirectTarget(performer.ID)
+			if err != nil {
+				return nil, err
+			}
+			if performer == nil {
+				continue
+			}
+		}
+		if !found[performer.ID] {
+			found[performer.ID] = true
+			ret = append(ret, performer)
+		}
+	}
+
+	return ret, nil
+}
+
 func (qb *PerformerQueryBuilder) Count() (int, error) {
 	return runCountQuery(buildCountQuery("SELECT performers.id FROM performers"), nil)
 }
@@ -387,8 +455,9 @@ func (qb *PerformerQueryBuilder) GetUrls(id uuid.UUID) ([]*URL, error) {
 	urls := make([]*URL, len(joins))
 	for i, u := range joins {
 		url := URL{
-			URL:  u.URL,
-			Type: u.Type,
+			URL:    u.URL,
+			Type:   u.Type,
+			SiteID: siteIDString(u.SiteID),
 		}
 		urls[i] = &url
 	}
@@ -406,8 +475,9 @@ func (qb *PerformerQueryBuilder) GetAllUrls(ids []uuid.UUID) ([][]*URL, []error)
 	m := make(map[uuid.UUID][]*URL)
 	for _, join := range joins {
 		url := URL{
-			URL:  join.URL,
-			Type: join.Type,
+			URL:    join.URL,
+			Type:   join.Type,
+			SiteID: siteIDString(join.SiteID),
 		}
 		m[join.PerformerID] = append(m[join.PerformerID], &url)
 	}
@@ -499,6 +569,328 @@ func (qb *PerformerQueryBuilder) SearchPerformers(term string, limit int) (Perfo
 	return qb.queryPerformers(query, args)
 }
 
+// FindCostars returns the performers appearing in the most non-deleted scenes
+// with the provided performer, ordered by the number of shared scenes.
+func (qb *PerformerQueryBuilder) FindCostars(performerID uuid.UUID, limit int) ([]*PerformerCostar, error) {
+	query := `
+		SELECT costars.performer_id, COUNT(DISTINCT costars.scene_id) AS scene_count
+		FROM scene_performers
+		JOIN scenes ON scenes.id = scene_performers.scene_id
+		JOIN scene_performers costars ON costars.scene_id = scene_performers.scene_id
+		JOIN performers ON performers.id = costars.performer_id
+		WHERE scene_performers.performer_id = ?
+		AND costars.performer_id != scene_performers.performer_id
+		AND scenes.deleted = FALSE
+		AND performers.deleted = FALSE
+		GROUP BY costars.performer_id, performers.name
+		ORDER BY scene_count DESC, performers.name ASC
+		LIMIT ?`
+
+	var result []*PerformerCostar
+	query = database.DB.Rebind(query)
+	if err := database.DB.Select(&result, query, performerID, limit); err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// performerConnectionChunkSize is the number of ids bound in each query of
+// the connection search, well below the bind parameter limit of postgres.
+const performerConnectionChunkSize = 1000
+
+// maxPerformerConnectionVisited bounds the number of performers the
+// connection search may visit before giving up.
+const maxPerformerConnectionVisited = 20000
+
+// ErrPerformerConnectionTooLarge is returned when a connection search
+// visits too many performers.
+var ErrPerformerConnectionTooLarge = errors.New("performer connection search is too large, try a lower max_depth")
+
+// FindConnection returns the shortest chain of non-deleted scenes linking
+// performer a to performer b through non-deleted performers, following at
+// most maxDepth scenes. Returns nil if the performers are not connected
+// within maxDepth.
+func (qb *PerformerQueryBuilder) FindConnection(a uuid.UUID, b uuid.UUID, maxDepth int) (*PerformerConnection, error) {
+	return findPerformerConnection(qb, a, b, maxDepth)
+}
+
+// performerConnectionSource provides the scene appearances searched by
+// findPerformerConnection.
+type performerConnectionSource interface {
+	// findSceneAppearances returns the appearances of the performers in
+	// non-deleted scenes.
+	findSceneAppearances(performerIDs []uuid.UUID) (PerformersScenes, error)
+	// findSceneCostars returns the appearances of non-deleted performers in
+	// the scenes.
+	findSceneCostars(sceneIDs []uuid.UUID) (PerformersScenes, error)
+}
+
+// findPerformerConnection searches the performers breadth first, one level
+// of scenes at a time, so each performer and scene is only visited once.
+// Each level is queried in chunks, and the search fails with
+// ErrPerformerConnectionTooLarge once too many performers are visited.
+func findPerformerConnection(src performerConnectionSource, a uuid.UUID, b uuid.UUID, maxDepth int) (*PerformerConnection, error) {
+	if a == b {
+		return &PerformerConnection{
+			PerformerIDs: []uuid.UUID{a},
+		}, nil
+	}
+
+	reachedFrom := make(map[uuid.UUID]performerConnectionStep)
+	visitedPerformers := map[uuid.UUID]bool{a: true}
+	visitedScenes := map[uuid.UUID]bool{}
+
+	frontier := []uuid.UUID{a}
+	for depth := 0; depth < maxDepth && len(frontier) > 0; depth++ {
+		// the frontier performer each new scene is reached from
+		sceneFrom := make(map[uuid.UUID]uuid.UUID)
+		var sceneIDs []uuid.UUID
+		err := forEachUUIDChunk(frontier, func(ids []uuid.UUID) error {
+			appearances, err := src.findSceneAppearances(ids)
+			if err != nil {
+				return err
+			}
+
+			for _, appearance := range appearances {
+				if visitedScenes[appearance.SceneID] {
+					continue
+				}
+				visitedScenes[appearance.SceneID] = true
+				sceneFrom[appearance.SceneID] = appearance.PerformerID
+				sceneIDs = append(sceneIDs, appearance.SceneID)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		frontier = nil
+		err = forEachUUIDChunk(sceneIDs, func(ids []uuid.UUID) error {
+			costars, err := src.findSceneCostars(ids)
+			if err != nil {
+				return err
+			}
+
+			for _, costar := range costars {
+				if visitedPerformers[costar.PerformerID] {
+					continue
+				}
+				visitedPerformers[costar.PerformerID] = true
+				reachedFrom[costar.PerformerID] = performerConnectionStep{
+					performerID: sceneFrom[costar.SceneID],
+					sceneID:     costar.SceneID,
+				}
+				frontier = append(frontier, costar.PerformerID)
+			}
+
+			if len(visitedPerformers) > maxPerformerConnectionVisited {
+				return ErrPerformerConnectionTooLarge
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if visitedPerformers[b] {
+			return buildPerformerConnection(a, b, reachedFrom), nil
+		}
+	}
+
+	return nil, nil
+}
+
+// forEachUUIDChunk calls fn with the ids in chunks of at most
+// performerConnectionChunkSize.
+func forEachUUIDChunk(ids []uuid.UUID, fn func([]uuid.UUID) error) error {
+	for start := 0; start < len(ids); start += performerConnectionChunkSize {
+		end := start + performerConnectionChunkSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+		if err := fn(ids[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (qb *PerformerQueryBuilder) findSceneAppearances(performerIDs []uuid.UUID) (PerformersScenes, error) {
+	query := `
+		SELECT scene_performers.* FROM scene_performers
+		JOIN scenes ON scenes.id = scene_performers.scene_id
+		WHERE scene_performers.performer_id IN (?)
+		AND scenes.deleted = FALSE`
+	return qb.queryPerformersScenes(query, performerIDs)
+}
+
+func (qb *PerformerQueryBuilder) findSceneCostars(sceneIDs []uuid.UUID) (PerformersScenes, error) {
+	query := `
+		SELECT scene_performers.* FROM scene_performers
+		JOIN performers ON performers.id = scene_performers.performer_id
+		WHERE scene_performers.scene_id IN (?)
+		AND performers.deleted = FALSE`
+	return qb.queryPerformersScenes(query, sceneIDs)
+}
+
+func (qb *PerformerQueryBuilder) queryPerformersScenes(query string, ids []uuid.UUID) (PerformersScenes, error) {
+	query, args, err := sqlx.In(query, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	var output PerformersScenes
+	err = qb.dbi.RawQuery(scenePerformerTable.Table, query, args, &output)
+	return output, err
+}
+
+// performerConnectionStep is the performer and scene a performer was reached
+// from while searching for a connection.
+type performerConnectionStep struct {
+	performerID uuid.UUID
+	sceneID     uuid.UUID
+}
+
+// buildPerformerConnection walks the steps back from performer b to
+// performer a.
+func buildPerformerConnection(a uuid.UUID, b uuid.UUID, reachedFrom map[uuid.UUID]performerConnectionStep) *PerformerConnection {
+	performerIDs := []uuid.UUID{b}
+	var sceneIDs []uuid.UUID
+	for id := b; id != a; {
+		step := reachedFrom[id]
+		id = step.performerID
+		performerIDs = append(performerIDs, id)
+		sceneIDs = append(sceneIDs, step.sceneID)
+	}
+
+	// reverse to run from a to b
+	for i, j := 0, len(performerIDs)-1; i < j; i, j = i+1, j-1 {
+		performerIDs[i], performerIDs[j] = performerIDs[j], performerIDs[i]
+	}
+	for i, j := 0, len(sceneIDs)-1; i < j; i, j = i+1, j-1 {
+		sceneIDs[i], sceneIDs[j] = sceneIDs[j], sceneIDs[i]
+	}
+
+	return &PerformerConnection{
+		PerformerIDs: performerIDs,
+		SceneIDs:     sceneIDs,
+	}
+}
+
+// performerStatsTagLimit is the number of most common tags returned in the
+// stats of each performer.
+const performerStatsTagLimit = 10
+
+// GetAllStats returns the career statistics of each of the provided
+// performer ids.
+func (qb *PerformerQueryBuilder) GetAllStats(ids []uuid.UUID) ([]*PerformerStats, []error) {
+	m := make(map[uuid.UUID]*PerformerStats)
+	for _, id := range ids {
+		m[id] = &PerformerStats{
+			ScenesPerYear: []*PerformerYearCount{},
+			Studios:       []*PerformerStudioCount{},
+			CommonTags:    []*PerformerTagCount{},
+		}
+	}
+
+	var years []*PerformerYearCount
+	query := `
+		SELECT scene_performers.performer_id, CAST(EXTRACT(YEAR FROM scenes.date) AS INTEGER) AS year, COUNT(*) AS scene_count
+		FROM scene_performers
+		JOIN scenes ON scenes.id = scene_performers.scene_id
+		WHERE scene_performers.performer_id IN (?)
+		AND scenes.deleted = FALSE
+		AND scenes.date IS NOT NULL
+		GROUP BY scene_performers.performer_id, year
+		ORDER BY year ASC`
+	if err := qb.selectStats(&years, query, ids); err != nil {
+		return nil, utils.DuplicateError(err, len(ids))
+	}
+	for _, year := range years {
+		m[year.PerformerID].ScenesPerYear = append(m[year.PerformerID].ScenesPerYear, year)
+	}
+
+	var studios []*PerformerStudioCount
+	query = `
+		SELECT scene_performers.performer_id, scenes.studio_id, COUNT(*) AS scene_count
+		FROM scene_performers
+		JOIN scenes ON scenes.id = scene_performers.scene_id
+		WHERE scene_performers.performer_id IN (?)
+		AND scenes.deleted = FALSE
+		AND scenes.studio_id IS NOT NULL
+		GROUP BY scene_performers.performer_id, scenes.studio_id
+		ORDER BY scene_count DESC, scenes.studio_id`
+	if err := qb.selectStats(&studios, query, ids); err != nil {
+		return nil, utils.DuplicateError(err, len(ids))
+	}
+	for _, studio := range studios {
+		m[studio.PerformerID].Studios = append(m[studio.PerformerID].Studios, studio)
+	}
+
+	var tags []*PerformerTagCount
+	query = `
+		SELECT performer_id, tag_id, scene_count FROM (
+			SELECT scene_performers.performer_id, scene_tags.tag_id, COUNT(*) AS scene_count,
+				ROW_NUMBER() OVER (PARTITION BY scene_performers.performer_id ORDER BY COUNT(*) DESC, scene_tags.tag_id) AS rank
+			FROM scene_performers
+			JOIN scenes ON scenes.id = scene_performers.scene_id
+			JOIN scene_tags ON scene_tags.scene_id = scenes.id
+			WHERE scene_performers.performer_id IN (?)
+			AND scenes.deleted = FALSE
+			GROUP BY scene_performers.performer_id, scene_tags.tag_id
+		) T
+		WHERE rank <= ` + strconv.Itoa(performerStatsTagLimit) + `
+		ORDER BY scene_count DESC, tag_id`
+	if err := qb.selectStats(&tags, query, ids); err != nil {
+		return nil, utils.DuplicateError(err, len(ids))
+	}
+	for _, tag := range tags {
+		m[tag.PerformerID].CommonTags = append(m[tag.PerformerID].CommonTags, tag)
+	}
+
+	var dates []struct {
+		PerformerID uuid.UUID  `db:"performer_id"`
+		First       SQLiteDate `db:"first"`
+		Last        SQLiteDate `db:"last"`
+	}
+	query = `
+		SELECT scene_performers.performer_id, MIN(scenes.date) AS first, MAX(scenes.date) AS last
+		FROM scene_performers
+		JOIN scenes ON scenes.id = scene_performers.scene_id
+		WHERE scene_performers.performer_id IN (?)
+		AND scenes.deleted = FALSE
+		AND scenes.date IS NOT NULL
+		GROUP BY scene_performers.performer_id`
+	if err := qb.selectStats(&dates, query, ids); err != nil {
+		return nil, utils.DuplicateError(err, len(ids))
+	}
+	for _, date := range dates {
+		m[date.PerformerID].FirstSceneDate = date.First
+		m[date.PerformerID].LastSceneDate = date.Last
+	}
+
+	result := make([]*PerformerStats, len(ids))
+	for i, id := range ids {
+		result[i] = m[id]
+	}
+	return result, nil
+}
+
+func (qb *PerformerQueryBuilder) selectStats(output interface{}, query string, ids []uuid.UUID) error {
+	query, args, err := sqlx.In(query, ids)
+	if err != nil {
+		return err
+	}
+
+	query = database.DB.Rebind(query)
+	if err := database.DB.Select(output, query, args...); err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	return nil
+}
+
 func (qb *PerformerQueryBuilder) DeleteScenePerformers(id uuid.UUID) error {
 	// Delete scene_performers joins
 	return qb.dbi.DeleteJoins(performerSceneTable, id)
@@ -0,0 +1,255 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/stashapp/stash-box/pkg/database"
+)
+
+type PerformerDuplicateQueryBuilder struct {
+	dbi database.DBI
+}
+
+func NewPerformerDuplicateQueryBuilder(tx *sqlx.Tx) PerformerDuplicateQueryBuilder {
+	return PerformerDuplicateQueryBuilder{
+		dbi: database.DBIWithTxn(tx),
+	}
+}
+
+func (qb *PerformerDuplicateQueryBuilder) toModel(ro interface{}) *PerformerDuplicate {
+	if ro != nil {
+		return ro.(*PerformerDuplicate)
+	}
+
+	return nil
+}
+
+func (qb *PerformerDuplicateQueryBuilder) Update(updatedDuplicate PerformerDuplicate) (*PerformerDuplicate, error) {
+	ret, err := qb.dbi.Update(updatedDuplicate, true)
+	return qb.toModel(ret), err
+}
+
+func (qb *PerformerDuplicateQueryBuilder) Find(id uuid.UUID) (*PerformerDuplicate, error) {
+	ret, err := qb.dbi.Find(id, performerDuplicateDBTable)
+	return qb.toModel(ret), err
+}
+
+func (qb *PerformerDuplicateQueryBuilder) Query(filter *PerformerDuplicateFilterType, findFilter *QuerySpec) (PerformerDuplicates, int) {
+	if filter == nil {
+		filter = &PerformerDuplicateFilterType{}
+	}
+	if findFilter == nil {
+		findFilter = &QuerySpec{}
+	}
+
+	query := database.NewQueryBuilder(performerDuplicateDBTable)
+
+	if q := filter.Status; q != nil {
+		query.Eq("performer_duplicates.status", q.String())
+	}
+
+	if q := filter.PerformerID; q != nil {
+		query.AddWhere("(performer_duplicates.performer_id = ? OR performer_duplicates.duplicate_id = ?)")
+		query.AddArg(*q, *q)
+	}
+
+	if q := filter.MinScore; q != nil {
+		query.AddWhere("performer_duplicates.score >= ?")
+		query.AddArg(*q)
+	}
+
+	query.SortAndPagination = qb.getPerformerDuplicateSort(findFilter) + getPagination(findFilter)
+
+	var duplicates PerformerDuplicates
+	countResult, err := qb.dbi.Query(*query, &duplicates)
+
+	if err != nil {
+		// TODO
+		panic(err)
+	}
+
+	return duplicates, countResult
+}
+
+func (qb *PerformerDuplicateQueryBuilder) getPerformerDuplicateSort(findFilter *QuerySpec) string {
+	sort := findFilter.GetSort("score")
+	direction := "DESC"
+	if findFilter.Direction != nil {
+		direction = findFilter.GetDirection()
+	}
+
+	var secondary *string
+	if sort != "score" {
+		score := "score"
+		secondary = &score
+	}
+	return getSort(sort, direction, performerDuplicateTable, secondary)
+}
+
+// performerDuplicateBatchSize is the number of performers whose candidate
+// pairs are found in each batch of RefreshBatch.
+const performerDuplicateBatchSize = 1000
+
+// findBatch returns the first and last ids of the batch of non-deleted
+// performers ordered by id following the provided id, or from the first
+// performer if nil. Returns nil if there are no more performers.
+func (qb *PerformerDuplicateQueryBuilder) findBatch(after *uuid.UUID) (*uuid.UUID, *uuid.UUID, error) {
+	query := `
+		SELECT performers.* FROM performers
+		WHERE deleted = FALSE`
+	var args []interface{}
+	if after != nil {
+		query += " AND id > ?"
+		args = append(args, *after)
+	}
+	query += " ORDER BY id LIMIT ?"
+	args = append(args, performerDuplicateBatchSize)
+
+	var output Performers
+	if err := qb.dbi.RawQuery(performerDBTable, query, args, &output); err != nil {
+		return nil, nil, err
+	}
+	if len(output) == 0 {
+		return nil, nil, nil
+	}
+
+	return &output[0].ID, &output[len(output)-1].ID, nil
+}
+
+// FindCandidates returns the pairs of non-deleted performers whose names or
+// aliases are similar, or who share a URL, where the lesser performer id is
+// between first and last. The names of those performers are matched
+// against the trigram indexes of the names and aliases of the greater ids.
+func (qb *PerformerDuplicateQueryBuilder) FindCandidates(first uuid.UUID, last uuid.UUID) (PerformerDuplicateCandidates, error) {
+	query := `
+		WITH batch_names AS (
+			SELECT id AS performer_id, name FROM performers
+			WHERE id BETWEEN ? AND ?
+			AND deleted = FALSE
+			UNION ALL
+			SELECT performer_id, alias FROM performer_aliases
+			WHERE performer_id BETWEEN ? AND ?
+		), name_matches AS (
+			SELECT a.performer_id AS a_id, b.id AS b_id, similarity(a.name, b.name) AS similarity
+			FROM batch_names a
+			JOIN performers b ON b.name % a.name AND b.id > a.performer_id
+			UNION ALL
+			SELECT a.performer_id, b.performer_id, similarity(a.name, b.alias)
+			FROM batch_names a
+			JOIN performer_aliases b ON b.alias % a.name AND b.performer_id > a.performer_id
+		), name_pairs AS (
+			SELECT a_id AS performer_id, b_id AS duplicate_id, MAX(similarity) AS name_similarity
+			FROM name_matches
+			GROUP BY a_id, b_id
+		), url_pairs AS (
+			SELECT a.performer_id, b.performer_id AS duplicate_id, COUNT(*) AS shared_urls
+			FROM performer_urls a
+			JOIN performer_urls b ON a.url = b.url AND a.performer_id < b.performer_id
+			WHERE a.performer_id BETWEEN ? AND ?
+			GROUP BY a.performer_id, b.performer_id
+		), pairs AS (
+			SELECT performer_id, duplicate_id FROM name_pairs
+			UNION
+			SELECT performer_id, duplicate_id FROM url_pairs
+		)
+		SELECT pairs.performer_id, pairs.duplicate_id,
+			COALESCE(name_pairs.name_similarity, similarity(performer.name, duplicate.name)) AS name_similarity,
+			COALESCE(url_pairs.shared_urls, 0) AS shared_urls,
+			(
+				SELECT COUNT(*) FROM scene_performers a
+				JOIN scene_performers b ON a.scene_id = b.scene_id
+				JOIN scenes ON scenes.id = a.scene_id
+				WHERE a.performer_id = pairs.performer_id
+				AND b.performer_id = pairs.duplicate_id
+				AND scenes.deleted = FALSE
+			) AS shared_scenes,
+			performer.birthdate AS performer_birthdate,
+			performer.birthdate_accuracy AS performer_birthdate_accuracy,
+			duplicate.birthdate AS duplicate_birthdate,
+			duplicate.birthdate_accuracy AS duplicate_birthdate_accuracy
+		FROM pairs
+		JOIN performers performer ON performer.id = pairs.performer_id
+		JOIN performers duplicate ON duplicate.id = pairs.duplicate_id
+		LEFT JOIN name_pairs ON name_pairs.performer_id = pairs.performer_id AND name_pairs.duplicate_id = pairs.duplicate_id
+		LEFT JOIN url_pairs ON url_pairs.performer_id = pairs.performer_id AND url_pairs.duplicate_id = pairs.duplicate_id
+		WHERE performer.deleted = FALSE
+		AND duplicate.deleted = FALSE`
+	args := []interface{}{first, last, first, last, first, last}
+
+	var output PerformerDuplicateCandidates
+	err := qb.dbi.RawQuery(performerDuplicateCandidateTable, query, args, &output)
+	return output, err
+}
+
+// Upsert stores the scored candidate. Existing pairs are only rescored if
+// they are still pending review.
+func (qb *PerformerDuplicateQueryBuilder) Upsert(candidate PerformerDuplicateCandidate, now time.Time) error {
+	id, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO performer_duplicates (id, performer_id, duplicate_id, score, name_similarity, birthdate_match, shared_urls, shared_scenes, status, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (performer_id, duplicate_id) DO UPDATE
+		SET score = EXCLUDED.score,
+			name_similarity = EXCLUDED.name_similarity,
+			birthdate_match = EXCLUDED.birthdate_match,
+			shared_urls = EXCLUDED.shared_urls,
+			shared_scenes = EXCLUDED.shared_scenes,
+			updated_at = EXCLUDED.updated_at
+		WHERE performer_duplicates.status = ?`
+	timestamp := SQLiteTimestamp{Timestamp: now}
+	args := []interface{}{
+		id, candidate.PerformerID, candidate.DuplicateID, candidate.Score(), candidate.NameSimilarity,
+		candidate.BirthdateMatch(), candidate.SharedUrls, candidate.SharedScenes,
+		PerformerDuplicateStatusEnumPending.String(), timestamp, timestamp,
+		PerformerDuplicateStatusEnumPending.String(),
+	}
+	return qb.dbi.RawQuery(performerDuplicateDBTable, query, args, nil)
+}
+
+// DestroyStalePending removes pending pairs that were not refreshed since
+// the provided time.
+func (qb *PerformerDuplicateQueryBuilder) DestroyStalePending(since time.Time) error {
+	q := database.NewDeleteQueryBuilder(performerDuplicateDBTable)
+	q.AddWhere("status = ?")
+	q.AddWhere("updated_at < ?")
+	q.AddArg(PerformerDuplicateStatusEnumPending.String(), SQLiteTimestamp{Timestamp: since})
+	return qb.dbi.DeleteQuery(*q)
+}
+
+// RefreshBatch rescores the candidate pairs of the batch of performers
+// following the provided id, or the first batch if nil, storing those
+// scoring at least MinPerformerDuplicateScore. Returns the number of pairs
+// stored, and the last performer id of the batch, or nil once there are no
+// more performers.
+func (qb *PerformerDuplicateQueryBuilder) RefreshBatch(after *uuid.UUID, now time.Time) (int, *uuid.UUID, error) {
+	first, last, err := qb.findBatch(after)
+	if err != nil || first == nil {
+		return 0, nil, err
+	}
+
+	candidates, err := qb.FindCandidates(*first, *last)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	count := 0
+	for _, candidate := range candidates {
+		if candidate.Score() < MinPerformerDuplicateScore {
+			continue
+		}
+
+		if err := qb.Upsert(*candidate, now); err != nil {
+			return 0, nil, err
+		}
+		count++
+	}
+
+	return count, last, nil
+}
@@ -0,0 +1,111 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gofrs/uuid"
+)
+
+// fakeConnectionSource is an in-memory graph of scene appearances that
+// fails the test if a query binds more than performerConnectionChunkSize ids.
+type fakeConnectionSource struct {
+	t           *testing.T
+	appearances PerformersScenes
+}
+
+func (s *fakeConnectionSource) filter(ids []uuid.UUID, key func(*PerformerScene) uuid.UUID) PerformersScenes {
+	if len(ids) > performerConnectionChunkSize {
+		s.t.Fatalf("query bound %d ids, want at most %d", len(ids), performerConnectionChunkSize)
+	}
+
+	set := make(map[uuid.UUID]bool)
+	for _, id := range ids {
+		set[id] = true
+	}
+
+	var ret PerformersScenes
+	for _, appearance := range s.appearances {
+		if set[key(appearance)] {
+			ret = append(ret, appearance)
+		}
+	}
+	return ret
+}
+
+func (s *fakeConnectionSource) findSceneAppearances(performerIDs []uuid.UUID) (PerformersScenes, error) {
+	return s.filter(performerIDs, func(p *PerformerScene) uuid.UUID { return p.PerformerID }), nil
+}
+
+func (s *fakeConnectionSource) findSceneCostars(sceneIDs []uuid.UUID) (PerformersScenes, error) {
+	return s.filter(sceneIDs, func(p *PerformerScene) uuid.UUID { return p.SceneID }), nil
+}
+
+func (s *fakeConnectionSource) appear(sceneID uuid.UUID, performerIDs ...uuid.UUID) {
+	for _, id := range performerIDs {
+		s.appearances = append(s.appearances, &PerformerScene{
+			PerformerID: id,
+			SceneID:     sceneID,
+		})
+	}
+}
+
+func newUUIDs(n int) []uuid.UUID {
+	ret := make([]uuid.UUID, n)
+	for i := range ret {
+		ret[i] = uuid.Must(uuid.NewV4())
+	}
+	return ret
+}
+
+func TestFindPerformerConnectionLargeFrontier(t *testing.T) {
+	src := &fakeConnectionSource{t: t}
+	a := uuid.Must(uuid.NewV4())
+	b := uuid.Must(uuid.NewV4())
+
+	// a shares a scene with more performers than fit in one query, and only
+	// the last of them shares a scene with b
+	costars := newUUIDs(performerConnectionChunkSize*2 + 500)
+	first := uuid.Must(uuid.NewV4())
+	src.appear(first, append([]uuid.UUID{a}, costars...)...)
+	for _, costar := range costars[:len(costars)-1] {
+		src.appear(uuid.Must(uuid.NewV4()), costar, uuid.Must(uuid.NewV4()))
+	}
+	last := costars[len(costars)-1]
+	second := uuid.Must(uuid.NewV4())
+	src.appear(second, last, b)
+
+	connection, err := findPerformerConnection(src, a, b, 3)
+	if err != nil {
+		t.Fatalf("findPerformerConnection: %s", err)
+	}
+	if connection == nil {
+		t.Fatal("Expected connection")
+	}
+
+	expectedPerformers := []uuid.UUID{a, last, b}
+	if !reflect.DeepEqual(connection.PerformerIDs, expectedPerformers) {
+		t.Errorf("PerformerIDs: got %v want %v", connection.PerformerIDs, expectedPerformers)
+	}
+	expectedScenes := []uuid.UUID{first, second}
+	if !reflect.DeepEqual(connection.SceneIDs, expectedScenes) {
+		t.Errorf("SceneIDs: got %v want %v", connection.SceneIDs, expectedScenes)
+	}
+
+	connection, err = findPerformerConnection(src, a, b, 1)
+	if err != nil || connection != nil {
+		t.Errorf("Expected no connection within depth 1, got %v, %v", connection, err)
+	}
+}
+
+func TestFindPerformerConnectionTooLarge(t *testing.T) {
+	src := &fakeConnectionSource{t: t}
+	a := uuid.Must(uuid.NewV4())
+	b := uuid.Must(uuid.NewV4())
+
+	src.appear(uuid.Must(uuid.NewV4()), append([]uuid.UUID{a}, newUUIDs(maxPerformerConnectionVisited)...)...)
+
+	if _, err := findPerformerConnection(src, a, b, 3); err != ErrPerformerConnectionTooLarge {
+		t.Errorf("Expected ErrPerformerConnectionTooLarge, got %v", err)
+	}
+}
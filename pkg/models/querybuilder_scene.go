@@ -1,6 +1,7 @@
 package models
 
 import (
+	"fmt"
 	"strconv"
 
 	"github.com/gofrs/uuid"
@@ -62,6 +63,26 @@ func (qb *SceneQueryBuilder) Find(id uuid.UUID) (*Scene, error) {
 	return qb.toModel(ret), err
 }
 
+func (qb *SceneQueryBuilder) FindByIds(ids []uuid.UUID) ([]*Scene, []error) {
+	query := "SELECT scenes.* FROM scenes WHERE id IN (?)"
+	query, args, _ := sqlx.In(query, ids)
+	scenes, err := qb.queryScenes(query, args)
+	if err != nil {
+		return nil, utils.DuplicateError(err, len(ids))
+	}
+
+	m := make(map[uuid.UUID]*Scene)
+	for _, scene := range scenes {
+		m[scene.ID] = scene
+	}
+
+	result := make([]*Scene, len(ids))
+	for i, id := range ids {
+		result[i] = m[id]
+	}
+	return result, nil
+}
+
 func (qb *SceneQueryBuilder) FindByFingerprint(algorithm FingerprintAlgorithm, hash string) ([]*Scene, error) {
 	query := `
 		SELECT scenes.* FROM scenes
@@ -138,7 +159,7 @@ func (qb *SceneQueryBuilder) Count() (int, error) {
 	return runCountQuery(buildCountQuery("SELECT scenes.id FROM scenes"), nil)
 }
 
-func (qb *SceneQueryBuilder) Query(sceneFilter *SceneFilterType, findFilter *QuerySpec) ([]*Scene, int) {
+func (qb *SceneQueryBuilder) Query(sceneFilter *SceneFilterType, findFilter *QuerySpec) ([]*Scene, int, error) {
 	if sceneFilter == nil {
 		sceneFilter = &SceneFilterType{}
 	}
@@ -169,6 +190,8 @@ func (qb *SceneQueryBuilder) Query(sceneFilter *SceneFilterType, findFilter *Que
 		query.AddArg(thisArgs...)
 	}
 
+	handleFuzzyDateCriterion("scenes.date", "scenes.date_accuracy", sceneFilter.Date, query)
+
 	if q := sceneFilter.Studios; q != nil && len(q.Value) > 0 {
 		column := "scenes.studio_id"
 		if q.Modifier == CriterionModifierEquals {
@@ -190,10 +213,15 @@ func (qb *SceneQueryBuilder) Query(sceneFilter *SceneFilterType, findFilter *Que
 				query.AddArg(studioID)
 			}
 		} else {
-			panic("unsupported modifier " + q.Modifier + " for scnes.studio_id")
+			return nil, 0, unsupportedModifierError(q.Modifier, "scenes.studio_id")
 		}
 	}
 
+	if sceneFilter.Network != nil {
+		query.AddWhere("scenes.studio_id IN (" + studioNetworkQuery + ")")
+		query.AddArg(*sceneFilter.Network)
+	}
+
 	if sceneFilter.ParentStudio != nil {
 		query.Body += "LEFT JOIN studios ON scenes.studio_id = studios.id"
 		query.AddWhere("(studios.parent_studio_id = ? OR studios.id = ?)")
@@ -202,7 +230,10 @@ func (qb *SceneQueryBuilder) Query(sceneFilter *SceneFilterType, findFilter *Que
 
 	if q := sceneFilter.Performers; q != nil && len(q.Value) > 0 {
 		query.AddJoin(scenePerformerTable.Table, scenePerformerTable.Name()+".scene_id = scenes.id")
-		whereClause, havingClause := getMultiCriterionClause(scenePerformerTable, performerJoinKey, q)
+		whereClause, havingClause, err := getMultiCriterionClause(scenePerformerTable, performerJoinKey, q)
+		if err != nil {
+			return nil, 0, err
+		}
 		query.AddWhere(whereClause)
 		query.AddHaving(havingClause)
 
@@ -213,18 +244,52 @@ func (qb *SceneQueryBuilder) Query(sceneFilter *SceneFilterType, findFilter *Que
 
 	if q := sceneFilter.Tags; q != nil && len(q.Value) > 0 {
 		query.AddJoin(sceneTagTable.Table, sceneTagTable.Name()+".scene_id = scenes.id")
-		whereClause, havingClause := getMultiCriterionClause(sceneTagTable, tagJoinKey, q)
-		query.AddWhere(whereClause)
-		query.AddHaving(havingClause)
+		if q.Modifier == CriterionModifierIncludesChildren {
+			// includes any of the provided ids or their descendants
+			inBinding := getInBinding(len(q.Value))
+			query.AddWhere("(" + sceneTagTable.Name() + ".tag_id IN " + inBinding + " OR " + sceneTagTable.Name() + ".tag_id IN (" + tagDescendantsQuery(len(q.Value)) + "))")
+			for _, tagID := range q.Value {
+				query.AddArg(tagID)
+			}
+		} else {
+			whereClause, havingClause, err := getMultiCriterionClause(sceneTagTable, tagJoinKey, q)
+			if err != nil {
+				return nil, 0, err
+			}
+			query.AddWhere(whereClause)
+			query.AddHaving(havingClause)
+		}
 
 		for _, tagID := range q.Value {
 			query.AddArg(tagID)
 		}
 	}
 
+	if q := sceneFilter.TagCategory; q != nil && len(q.Value) > 0 {
+		clause, err := getTagCategoryClause(q)
+		if err != nil {
+			return nil, 0, err
+		}
+		query.AddWhere(clause)
+		for _, categoryID := range q.Value {
+			query.AddArg(categoryID)
+		}
+	}
+
+	if q := sceneFilter.Alias; q != nil {
+		clause, thisArgs := getSceneAliasClause(q)
+		if clause != "" {
+			query.AddWhere(clause)
+			query.AddArg(thisArgs...)
+		}
+	}
+
 	if q := sceneFilter.Fingerprints; q != nil && len(q.Value) > 0 {
 		query.AddJoin(sceneFingerprintTable.Table, sceneFingerprintTable.Name()+".scene_id = scenes.id")
-		whereClause, havingClause := getMultiCriterionClause(sceneFingerprintTable, "hash", q)
+		whereClause, havingClause, err := getMultiCriterionClause(sceneFingerprintTable, "hash", q)
+		if err != nil {
+			return nil, 0, err
+		}
 		query.AddWhere(whereClause)
 		query.AddHaving(havingClause)
 
@@ -241,14 +306,61 @@ func (qb *SceneQueryBuilder) Query(sceneFilter *SceneFilterType, findFilter *Que
 	countResult, err := qb.dbi.Query(*query, &scenes)
 
 	if err != nil {
-		// TODO
-		panic(err)
+		return nil, 0, err
 	}
 
-	return scenes, countResult
+	return scenes, countResult, nil
+}
+
+func unsupportedModifierError(modifier CriterionModifier, column string) error {
+	return fmt.Errorf("unsupported modifier %s for %s", modifier, column)
 }
 
-func getMultiCriterionClause(joinTable database.TableJoin, joinTableField string, criterion *MultiIDCriterionInput) (string, string) {
+// getSceneAliasClause matches scenes where a performer appears under the
+// alias, or plays the role, given by the criterion.
+func getSceneAliasClause(criterion *StringCriterionInput) (string, []interface{}) {
+	subquery := `SELECT 1 FROM scene_performers
+		WHERE scene_performers.scene_id = scenes.id AND `
+
+	switch criterion.Modifier {
+	case CriterionModifierEquals:
+		clause, args := getSearchBinding([]string{`scene_performers."as"`, "scene_performers.role"}, criterion.Value, false, true)
+		return "EXISTS (" + subquery + clause + ")", args
+	case CriterionModifierNotEquals:
+		clause, args := getSearchBinding([]string{`scene_performers."as"`, "scene_performers.role"}, criterion.Value, false, true)
+		return "NOT EXISTS (" + subquery + clause + ")", args
+	case CriterionModifierIsNull:
+		return "NOT EXISTS (" + subquery + `(scene_performers."as" IS NOT NULL OR scene_performers.role IS NOT NULL))`, nil
+	case CriterionModifierNotNull:
+		return "EXISTS (" + subquery + `(scene_performers."as" IS NOT NULL OR scene_performers.role IS NOT NULL))`, nil
+	}
+
+	return "", nil
+}
+
+func getTagCategoryClause(criterion *MultiIDCriterionInput) (string, error) {
+	categoryTags := `
+		SELECT tags.category_id FROM scene_tags
+		JOIN tags ON tags.id = scene_tags.tag_id
+		WHERE scene_tags.scene_id = scenes.id
+		AND tags.category_id IN ` + getInBinding(len(criterion.Value))
+
+	switch criterion.Modifier {
+	case CriterionModifierIncludes:
+		// has a tag in any of the provided categories
+		return "EXISTS (" + categoryTags + ")", nil
+	case CriterionModifierIncludesAll:
+		// has a tag in each of the provided categories
+		return "(SELECT COUNT(DISTINCT category_id) FROM (" + categoryTags + ") T) = " + strconv.Itoa(len(criterion.Value)), nil
+	case CriterionModifierExcludes:
+		// has no tag in any of the provided categories
+		return "NOT EXISTS (" + categoryTags + ")", nil
+	default:
+		return "", unsupportedModifierError(criterion.Modifier, "tag categories")
+	}
+}
+
+func getMultiCriterionClause(joinTable database.TableJoin, joinTableField string, criterion *MultiIDCriterionInput) (string, string, error) {
 	joinTableName := joinTable.Name()
 	whereClause := ""
 	havingClause := ""
@@ -263,10 +375,10 @@ func getMultiCriterionClause(joinTable database.TableJoin, joinTableField string
 		// excludes all of the provided ids
 		whereClause = "not exists (select " + joinTableName + ".scene_id from " + joinTableName + " where " + joinTableName + ".scene_id = scenes.id and " + joinTableName + "." + joinTableField + " in " + getInBinding(len(criterion.Value)) + ")"
 	} else {
-		panic("unsupported modifier " + criterion.Modifier + " for scenes.studio_id")
+		return "", "", unsupportedModifierError(criterion.Modifier, joinTableName+"."+joinTableField)
 	}
 
-	return whereClause, havingClause
+	return whereClause, havingClause, nil
 }
 
 func (qb *SceneQueryBuilder) getSceneSort(findFilter *QuerySpec) string {
@@ -280,6 +392,15 @@ func (qb *SceneQueryBuilder) getSceneSort(findFilter *QuerySpec) string {
 		sort = findFilter.GetSort("date")
 		direction = findFilter.GetDirection()
 	}
+	if sort == "date" {
+		if direction != "ASC" && direction != "DESC" {
+			direction = "ASC"
+		}
+		// scenes sharing a start date are ordered from least to most precise
+		accuracy := "CASE scenes.date_accuracy WHEN 'YEAR' THEN 0 WHEN 'MONTH' THEN 1 ELSE 2 END"
+		return " ORDER BY scenes.date " + direction + database.GetDialect().NullsLast() +
+			", " + accuracy + " " + direction + ", scenes.title " + direction
+	}
 	if sort != "title" {
 		title := "title"
 		secondary = &title
@@ -287,6 +408,22 @@ func (qb *SceneQueryBuilder) getSceneSort(findFilter *QuerySpec) string {
 	return getSort(sort, direction, "scenes", secondary)
 }
 
+// FindByURL returns the non-deleted scenes with a URL matching the provided
+// URL once both are normalized.
+func (qb *SceneQueryBuilder) FindByURL(url string) (Scenes, error) {
+	query := `
+		SELECT scenes.* FROM scenes
+		WHERE scenes.deleted = FALSE
+		AND EXISTS (
+			SELECT 1 FROM scene_urls
+			WHERE scene_urls.scene_id = scenes.id
+			AND normalize_url(scene_urls.url) = normalize_url(?)
+		)
+		ORDER BY scenes.title`
+	args := []interface{}{url}
+	return qb.queryScenes(query, args)
+}
+
 func (qb *SceneQueryBuilder) queryScenes(query string, args []interface{}) (Scenes, error) {
 	output := Scenes{}
 	err := qb.dbi.RawQuery(sceneDBTable, query, args, &output)
@@ -322,6 +459,7 @@ func (qb *SceneQueryBuilder) GetAllFingerprints(ids []uuid.UUID) ([][]*Fingerpri
 func (qb *SceneQueryBuilder) GetPerformers(id uuid.UUID) (PerformersScenes, error) {
 	joins := PerformersScenes{}
 	err := qb.dbi.FindJoins(scenePerformerTable, id, &joins)
+	joins.Sort()
 
 	return joins, err
 }
@@ -341,6 +479,7 @@ func (qb *SceneQueryBuilder) GetAllAppearances(ids []uuid.UUID) ([]PerformersSce
 	result := make([]PerformersScenes, len(ids))
 	for i, id := range ids {
 		result[i] = m[id]
+		result[i].Sort()
 	}
 	return result, nil
 }
@@ -362,8 +501,9 @@ func (qb *SceneQueryBuilder) GetAllUrls(ids []uuid.UUID) ([][]*URL, []error) {
 	m := make(map[uuid.UUID][]*URL)
 	for _, join := range joins {
 		url := URL{
-			URL:  join.URL,
-			Type: join.Type,
+			URL:    join.URL,
+			Type:   join.Type,
+			SiteID: siteIDString(join.SiteID),
 		}
 		m[join.SceneID] = append(m[join.SceneID], &url)
 	}
@@ -0,0 +1,178 @@
+package models
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/stashapp/stash-box/pkg/database"
+	"github.com/stashapp/stash-box/pkg/utils"
+)
+
+type SiteQueryBuilder struct {
+	dbi database.DBI
+}
+
+func NewSiteQueryBuilder(tx *sqlx.Tx) SiteQueryBuilder {
+	return SiteQueryBuilder{
+		dbi: database.DBIWithTxn(tx),
+	}
+}
+
+func (qb *SiteQueryBuilder) toModel(ro interface{}) *Site {
+	if ro != nil {
+		return ro.(*Site)
+	}
+
+	return nil
+}
+
+func (qb *SiteQueryBuilder) Create(newSite Site) (*Site, error) {
+	ret, err := qb.dbi.Insert(newSite)
+	return qb.toModel(ret), err
+}
+
+func (qb *SiteQueryBuilder) Update(updatedSite Site) (*Site, error) {
+	ret, err := qb.dbi.Update(updatedSite, false)
+	return qb.toModel(ret), err
+}
+
+func (qb *SiteQueryBuilder) Destroy(id uuid.UUID) error {
+	return qb.dbi.Delete(id, siteDBTable)
+}
+
+func (qb *SiteQueryBuilder) Find(id uuid.UUID) (*Site, error) {
+	ret, err := qb.dbi.Find(id, siteDBTable)
+	return qb.toModel(ret), err
+}
+
+func (qb *SiteQueryBuilder) querySites(query string, args []interface{}) (Sites, error) {
+	var output Sites
+	err := qb.dbi.RawQuery(siteDBTable, query, args, &output)
+	return output, err
+}
+
+// FindByName returns the site with the given name, ignoring case.
+func (qb *SiteQueryBuilder) FindByName(name string) (*Site, error) {
+	query := "SELECT * FROM sites WHERE lower(name) = lower(?)"
+
+	args := []interface{}{name}
+	results, err := qb.querySites(query, args)
+	if err != nil || len(results) < 1 {
+		return nil, err
+	}
+	return results[0], nil
+}
+
+func (qb *SiteQueryBuilder) FindByIds(ids []uuid.UUID) ([]*Site, []error) {
+	query := `
+		SELECT sites.* FROM sites
+		WHERE id IN (?)
+	`
+	query, args, _ := sqlx.In(query, ids)
+	sites, err := qb.querySites(query, args)
+	if err != nil {
+		return nil, utils.DuplicateError(err, len(ids))
+	}
+
+	m := make(map[uuid.UUID]*Site)
+	for _, site := range sites {
+		m[site.ID] = site
+	}
+
+	result := make([]*Site, len(ids))
+	for i, id := range ids {
+		result[i] = m[id]
+	}
+	return result, nil
+}
+
+func (qb *SiteQueryBuilder) Query(filter *SiteFilterType, findFilter *QuerySpec) ([]*Site, int, error) {
+	if filter == nil {
+		filter = &SiteFilterType{}
+	}
+	if findFilter == nil {
+		findFilter = &QuerySpec{}
+	}
+
+	query := database.NewQueryBuilder(siteDBTable)
+
+	if q := filter.Name; q != nil && *q != "" {
+		searchColumns := []string{"sites.name"}
+		clause, thisArgs := getSearchBinding(searchColumns, *q, false, true)
+		query.AddWhere(clause)
+		query.AddArg(thisArgs...)
+	}
+
+	if q := filter.ValidType; q != nil {
+		query.AddWhere("? = ANY(sites.valid_types)")
+		query.AddArg(q.String())
+	}
+
+	query.SortAndPagination = qb.getSiteSort(findFilter) + getPagination(findFilter)
+	var sites Sites
+
+	countResult, err := qb.dbi.Query(*query, &sites)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return sites, countResult, nil
+}
+
+func (qb *SiteQueryBuilder) getSiteSort(findFilter *QuerySpec) string {
+	sort := findFilter.GetSort("name")
+	direction := findFilter.GetDirection()
+	return getSort(sort, direction, siteTable, nil)
+}
+
+// ResolveURLs links each url to its site and validates it against the site.
+// Urls may reference a site by id, in which case the url type is set to the
+// site name, or by type, which is matched against site names ignoring case.
+// Urls whose type does not match a site are left unchanged.
+func (qb *SiteQueryBuilder) ResolveURLs(urls []*URL, validType ValidSiteTypeEnum) error {
+	for _, url := range urls {
+		var site *Site
+		var err error
+
+		if url.SiteID != nil {
+			siteID, err := uuid.FromString(*url.SiteID)
+			if err != nil {
+				return err
+			}
+
+			site, err = qb.Find(siteID)
+			if err != nil {
+				return err
+			}
+			if site == nil {
+				return errors.New("site with id " + siteID.String() + " not found")
+			}
+		} else {
+			if strings.TrimSpace(url.Type) == "" {
+				return errors.New("url " + url.URL + " requires a site or type")
+			}
+
+			site, err = qb.FindByName(url.Type)
+			if err != nil {
+				return err
+			}
+			if site == nil {
+				continue
+			}
+		}
+
+		if err := site.ValidateURL(url.URL, validType); err != nil {
+			return err
+		}
+
+		siteID := site.ID.String()
+		url.SiteID = &siteID
+		url.Type = site.Name
+	}
+
+	return nil
+}
@@ -38,6 +38,91 @@ func handleStringCriterion(column string, value *StringCriterionInput, query *da
 	}
 }
 
+func handleIntCriterion(column string, value *IntCriterionInput, query *database.QueryBuilder) {
+	if value != nil {
+		if modifier := value.Modifier.String(); value.Modifier.IsValid() {
+			switch modifier {
+			case "EQUALS":
+				query.AddWhere(column + " = ?")
+				query.AddArg(value.Value)
+			case "NOT_EQUALS":
+				query.AddWhere(column + " != ?")
+				query.AddArg(value.Value)
+			case "GREATER_THAN":
+				query.AddWhere(column + " > ?")
+				query.AddArg(value.Value)
+			case "LESS_THAN":
+				query.AddWhere(column + " < ?")
+				query.AddArg(value.Value)
+			}
+		}
+	}
+}
+
+func handleDateCriterion(column string, value *DateCriterionInput, query *database.QueryBuilder) {
+	if value != nil {
+		if modifier := value.Modifier.String(); value.Modifier.IsValid() {
+			switch modifier {
+			case "EQUALS":
+				query.Eq(column, value.Value)
+			case "NOT_EQUALS":
+				query.NotEq(column, value.Value)
+			case "GREATER_THAN":
+				query.AddWhere(column + " > ?")
+				query.AddArg(value.Value)
+			case "LESS_THAN":
+				query.AddWhere(column + " < ?")
+				query.AddArg(value.Value)
+			case "IS_NULL":
+				query.IsNull(column)
+			case "NOT_NULL":
+				query.IsNotNull(column)
+			}
+		}
+	}
+}
+
+// handleFuzzyDateCriterion filters on a date column with an associated
+// accuracy column. Dates that are not day accurate are treated as the range
+// of days within their year or month, so a month accurate date equals any
+// day within that month.
+func handleFuzzyDateCriterion(column string, accuracyColumn string, value *DateCriterionInput, query *database.QueryBuilder) {
+	if value == nil || !value.Modifier.IsValid() {
+		return
+	}
+
+	clause, args := getFuzzyDateClause(column, accuracyColumn, value.Modifier, value.Value)
+	if clause != "" {
+		query.AddWhere(clause)
+		query.AddArg(args...)
+	}
+}
+
+func getFuzzyDateClause(column string, accuracyColumn string, modifier CriterionModifier, value string) (string, []interface{}) {
+	// exclusive end of the period covered by the date
+	end := "(" + column + " + CASE " + accuracyColumn +
+		" WHEN 'YEAR' THEN INTERVAL '1 year'" +
+		" WHEN 'MONTH' THEN INTERVAL '1 month'" +
+		" ELSE INTERVAL '1 day' END)"
+
+	switch modifier {
+	case CriterionModifierEquals:
+		return "(" + column + " <= ?::date AND " + end + " > ?::date)", []interface{}{value, value}
+	case CriterionModifierNotEquals:
+		return "(" + column + " > ?::date OR " + end + " <= ?::date)", []interface{}{value, value}
+	case CriterionModifierGreaterThan:
+		return column + " > ?::date", []interface{}{value}
+	case CriterionModifierLessThan:
+		return end + " <= ?::date", []interface{}{value}
+	case CriterionModifierIsNull:
+		return column + " IS NULL", nil
+	case CriterionModifierNotNull:
+		return column + " IS NOT NULL", nil
+	}
+
+	return "", nil
+}
+
 func insertObject(tx *sqlx.Tx, table string, object interface{}, ignoreConflicts bool) error {
 	ensureTx(tx)
 	fields, values := SQLGenKeysCreate(object)
@@ -1,6 +1,12 @@
 package models
 
 import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/gofrs/uuid"
 	"github.com/jmoiron/sqlx"
 	"github.com/stashapp/stash-box/pkg/database"
@@ -39,6 +45,38 @@ func (qb *StudioQueryBuilder) Destroy(id uuid.UUID) error {
 	return qb.dbi.Delete(id, studioDBTable)
 }
 
+func (qb *StudioQueryBuilder) SoftDelete(studio Studio) (*Studio, error) {
+	// Delete joins
+	if err := qb.dbi.DeleteJoins(studioAliasTable, studio.ID); err != nil {
+		return nil, err
+	}
+	if err := qb.dbi.DeleteJoins(studioUrlTable, studio.ID); err != nil {
+		return nil, err
+	}
+	if err := qb.dbi.DeleteJoins(studioImageTable, studio.ID); err != nil {
+		return nil, err
+	}
+
+	ret, err := qb.dbi.SoftDelete(studio)
+	return qb.toModel(ret), err
+}
+
+func (qb *StudioQueryBuilder) CreateAliases(newJoins StudioAliases) error {
+	return qb.dbi.InsertJoins(studioAliasTable, &newJoins)
+}
+
+func (qb *StudioQueryBuilder) UpdateAliases(studioID uuid.UUID, updatedJoins StudioAliases) error {
+	return qb.dbi.ReplaceJoins(studioAliasTable, studioID, &updatedJoins)
+}
+
+func (qb *StudioQueryBuilder) CreateImages(newJoins StudiosImages) error {
+	return qb.dbi.InsertJoins(studioImageTable, &newJoins)
+}
+
+func (qb *StudioQueryBuilder) UpdateImages(studioID uuid.UUID, updatedJoins StudiosImages) error {
+	return qb.dbi.ReplaceJoins(studioImageTable, studioID, &updatedJoins)
+}
+
 func (qb *StudioQueryBuilder) CreateUrls(newJoins StudioUrls) error {
 	return qb.dbi.InsertJoins(studioUrlTable, &newJoins)
 }
@@ -52,6 +90,26 @@ func (qb *StudioQueryBuilder) Find(id uuid.UUID) (*Studio, error) {
 	return qb.toModel(ret), err
 }
 
+func (qb *StudioQueryBuilder) FindByIds(ids []uuid.UUID) ([]*Studio, []error) {
+	query := "SELECT studios.* FROM studios WHERE id IN (?)"
+	query, args, _ := sqlx.In(query, ids)
+	studios, err := qb.queryStudios(query, args)
+	if err != nil {
+		return nil, utils.DuplicateError(err, len(ids))
+	}
+
+	m := make(map[uuid.UUID]*Studio)
+	for _, studio := range studios {
+		m[studio.ID] = studio
+	}
+
+	result := make([]*Studio, len(ids))
+	for i, id := range ids {
+		result[i] = m[id]
+	}
+	return result, nil
+}
+
 func (qb *StudioQueryBuilder) FindBySceneID(sceneID int) (Studios, error) {
 	query := `
 		SELECT studios.* FROM studios
@@ -90,6 +148,52 @@ func (qb *StudioQueryBuilder) FindByParentID(id uuid.UUID) (Studios, error) {
 	return qb.queryStudios(query, args)
 }
 
+// studioNetworkQuery selects the ids of a non-deleted studio and all
+// non-deleted studios below it. UNION discards repeated ids, so a cycle in
+// the hierarchy terminates.
+const studioNetworkQuery = `
+	WITH RECURSIVE network AS (
+		SELECT id FROM studios WHERE id = ? AND deleted = FALSE
+		UNION
+		SELECT studios.id FROM studios
+		JOIN network ON studios.parent_studio_id = network.id
+		WHERE studios.deleted = FALSE
+	)
+	SELECT id FROM network`
+
+// FindDescendants returns all studios below the studio in the hierarchy,
+// ordered by name.
+func (qb *StudioQueryBuilder) FindDescendants(id uuid.UUID) (Studios, error) {
+	query := `
+		SELECT studios.* FROM studios
+		WHERE studios.id IN (` + studioNetworkQuery + `)
+		AND studios.id != ?
+		AND studios.deleted = FALSE
+		ORDER BY studios.name`
+	args := []interface{}{id, id}
+	return qb.queryStudios(query, args)
+}
+
+// FindAncestors returns the parent studios of the studio, nearest first.
+func (qb *StudioQueryBuilder) FindAncestors(id uuid.UUID) (Studios, error) {
+	query := `
+		WITH RECURSIVE ancestors AS (
+			SELECT id, parent_studio_id, 0 AS depth, ARRAY[id] AS path
+			FROM studios WHERE id = ?
+			UNION ALL
+			SELECT studios.id, studios.parent_studio_id, ancestors.depth + 1, ancestors.path || studios.id
+			FROM studios
+			JOIN ancestors ON studios.id = ancestors.parent_studio_id
+			WHERE NOT studios.id = ANY(ancestors.path)
+		)
+		SELECT studios.* FROM studios
+		JOIN ancestors ON studios.id = ancestors.id
+		WHERE ancestors.depth > 0
+		ORDER BY ancestors.depth`
+	args := []interface{}{id}
+	return qb.queryStudios(query, args)
+}
+
 func (qb *StudioQueryBuilder) Count() (int, error) {
 	return runCountQuery(buildCountQuery("SELECT studios.id FROM studios"), nil)
 }
@@ -115,8 +219,10 @@ func (qb *StudioQueryBuilder) Query(studioFilter *StudioFilterType, findFilter *
 	if q := studioFilter.Names; q != nil && *q != "" {
 		searchColumns := []string{"studios.name", "parent_studio.name"}
 		clause, thisArgs := getSearchBinding(searchColumns, *q, false, true)
-		query.AddWhere(clause)
+		aliasClause, aliasArgs := getSearchBinding([]string{"studio_aliases.alias"}, *q, false, true)
+		query.AddWhere("(" + clause + " OR EXISTS (SELECT 1 FROM studio_aliases WHERE studio_aliases.studio_id = studios.id AND " + aliasClause + "))")
 		query.AddArg(thisArgs...)
+		query.AddArg(aliasArgs...)
 	}
 
 	if studioFilter.HasParent != nil {
@@ -127,6 +233,8 @@ func (qb *StudioQueryBuilder) Query(studioFilter *StudioFilterType, findFilter *
 		}
 	}
 
+	handleIntCriterion(studioSceneCountColumn, studioFilter.SceneCount, query)
+
 	query.SortAndPagination = qb.getStudioSort(findFilter) + getPagination(findFilter)
 	var studios Studios
 	countResult, err := qb.dbi.Query(*query, &studios)
@@ -149,15 +257,187 @@ func (qb *StudioQueryBuilder) getStudioSort(findFilter *QuerySpec) string {
 		sort = findFilter.GetSort("name")
 		direction = findFilter.GetDirection()
 	}
+	if sort == "scene_count" {
+		if direction != "ASC" && direction != "DESC" {
+			direction = "ASC"
+		}
+		return " ORDER BY " + studioSceneCountColumn + " " + direction + ", studios.name " + direction
+	}
 	return getSort(sort, direction, "studios", nil)
 }
 
+// studioSceneCountColumn counts the non-deleted scenes of the studio in the
+// current row.
+const studioSceneCountColumn = `(SELECT COUNT(*) FROM scenes
+	WHERE scenes.studio_id = studios.id AND scenes.deleted = FALSE)`
+
+// GetAllSceneCounts returns the number of non-deleted scenes of each of the
+// provided studio ids, not including the scenes of child studios.
+func (qb *StudioQueryBuilder) GetAllSceneCounts(ids []uuid.UUID) ([]int, []error) {
+	query := `
+		SELECT scenes.studio_id, COUNT(*) AS scene_count
+		FROM scenes
+		WHERE scenes.studio_id IN (?)
+		AND scenes.deleted = FALSE
+		GROUP BY scenes.studio_id`
+	return qb.selectSceneCounts(query, ids)
+}
+
+// GetAllNetworkSceneCounts returns the number of non-deleted scenes of each
+// of the provided studio ids, including the scenes of all non-deleted
+// studios below it. UNION discards repeated pairs, so a cycle in the
+// hierarchy terminates.
+func (qb *StudioQueryBuilder) GetAllNetworkSceneCounts(ids []uuid.UUID) ([]int, []error) {
+	query := `
+		WITH RECURSIVE network AS (
+			SELECT id AS root_id, id FROM studios WHERE id IN (?) AND deleted = FALSE
+			UNION
+			SELECT network.root_id, studios.id FROM studios
+			JOIN network ON studios.parent_studio_id = network.id
+			WHERE studios.deleted = FALSE
+		)
+		SELECT network.root_id AS studio_id, COUNT(*) AS scene_count
+		FROM network
+		JOIN scenes ON scenes.studio_id = network.id
+		WHERE scenes.deleted = FALSE
+		GROUP BY network.root_id`
+	return qb.selectSceneCounts(query, ids)
+}
+
+// selectSceneCounts runs a query returning the studio_id and scene_count
+// for the provided studio ids, returning the counts in the order of the ids.
+func (qb *StudioQueryBuilder) selectSceneCounts(query string, ids []uuid.UUID) ([]int, []error) {
+	var counts []struct {
+		StudioID   uuid.UUID `db:"studio_id"`
+		SceneCount int       `db:"scene_count"`
+	}
+	query, args, err := sqlx.In(query, ids)
+	if err != nil {
+		return nil, utils.DuplicateError(err, len(ids))
+	}
+	query = database.DB.Rebind(query)
+	if err := database.DB.Select(&counts, query, args...); err != nil && err != sql.ErrNoRows {
+		return nil, utils.DuplicateError(err, len(ids))
+	}
+
+	m := make(map[uuid.UUID]int)
+	for _, count := range counts {
+		m[count.StudioID] = count.SceneCount
+	}
+
+	result := make([]int, len(ids))
+	for i, id := range ids {
+		result[i] = m[id]
+	}
+	return result, nil
+}
+
+// FindByURL returns the non-deleted studios with a URL matching the provided
+// URL once both are normalized.
+func (qb *StudioQueryBuilder) FindByURL(url string) (Studios, error) {
+	query := `
+		SELECT studios.* FROM studios
+		WHERE studios.deleted = FALSE
+		AND EXISTS (
+			SELECT 1 FROM studio_urls
+			WHERE studio_urls.studio_id = studios.id
+			AND normalize_url(studio_urls.url) = normalize_url(?)
+		)
+		ORDER BY studios.name`
+	args := []interface{}{url}
+	return qb.queryStudios(query, args)
+}
+
+// SearchStudios returns the non-deleted studios with a name or alias
+// similar to the search term, most similar first.
+func (qb *StudioQueryBuilder) SearchStudios(term string, limit int) (Studios, error) {
+	query := `
+        SELECT studios.* FROM studios
+        LEFT JOIN studio_aliases ON studio_aliases.studio_id = studios.id
+        WHERE studios.deleted = FALSE
+        AND (studios.name % $1 OR studio_aliases.alias % $1)
+        GROUP BY studios.id
+        ORDER BY MAX(GREATEST(similarity(studios.name, $1), COALESCE(similarity(studio_aliases.alias, $1), 0))) DESC
+        LIMIT $2`
+	args := []interface{}{term, limit}
+	return qb.queryStudios(query, args)
+}
+
+// ValidateNames returns an error if the name or any alias of the studio
+// matches the name or an alias of another non-deleted studio, ignoring case.
+func (qb *StudioQueryBuilder) ValidateNames(id uuid.UUID, name string, aliases []string) error {
+	var names []string
+	for _, n := range append([]string{name}, aliases...) {
+		names = append(names, strings.ToLower(n))
+	}
+
+	query := `
+		SELECT studios.* FROM studios
+		WHERE studios.id != ?
+		AND studios.deleted = FALSE
+		AND (
+			lower(studios.name) IN (?)
+			OR EXISTS (
+				SELECT 1 FROM studio_aliases
+				WHERE studio_aliases.studio_id = studios.id
+				AND lower(studio_aliases.alias) IN (?)
+			)
+		)
+		ORDER BY studios.name`
+	query, args, err := sqlx.In(query, id, names, names)
+	if err != nil {
+		return err
+	}
+
+	conflicts, err := qb.queryStudios(query, args)
+	if err != nil {
+		return err
+	}
+	if len(conflicts) > 0 {
+		return fmt.Errorf("studio name or alias conflicts with the name or an alias of studio '%s'", conflicts[0].Name)
+	}
+	return nil
+}
+
 func (qb *StudioQueryBuilder) queryStudios(query string, args []interface{}) (Studios, error) {
 	var output Studios
 	err := qb.dbi.RawQuery(studioDBTable, query, args, &output)
 	return output, err
 }
 
+func (qb *StudioQueryBuilder) GetAliases(id uuid.UUID) (StudioAliases, error) {
+	joins := StudioAliases{}
+	err := qb.dbi.FindJoins(studioAliasTable, id, &joins)
+
+	return joins, err
+}
+
+func (qb *StudioQueryBuilder) GetAllAliases(ids []uuid.UUID) ([][]string, []error) {
+	joins := StudioAliases{}
+	err := qb.dbi.FindAllJoins(studioAliasTable, ids, &joins)
+	if err != nil {
+		return nil, utils.DuplicateError(err, len(ids))
+	}
+
+	m := make(map[uuid.UUID][]string)
+	for _, join := range joins {
+		m[join.StudioID] = append(m[join.StudioID], join.Alias)
+	}
+
+	result := make([][]string, len(ids))
+	for i, id := range ids {
+		result[i] = m[id]
+	}
+	return result, nil
+}
+
+func (qb *StudioQueryBuilder) GetImages(id uuid.UUID) (StudiosImages, error) {
+	joins := StudiosImages{}
+	err := qb.dbi.FindJoins(studioImageTable, id, &joins)
+
+	return joins, err
+}
+
 func (qb *StudioQueryBuilder) GetUrls(id uuid.UUID) (StudioUrls, error) {
 	joins := StudioUrls{}
 	err := qb.dbi.FindJoins(studioUrlTable, id, &joins)
@@ -175,8 +455,9 @@ func (qb *StudioQueryBuilder) GetAllUrls(ids []uuid.UUID) ([][]*URL, []error) {
 	m := make(map[uuid.UUID][]*URL)
 	for _, join := range joins {
 		url := URL{
-			URL:  join.URL,
-			Type: join.Type,
+			URL:    join.URL,
+			Type:   join.Type,
+			SiteID: siteIDString(join.SiteID),
 		}
 		m[join.StudioID] = append(m[join.StudioID], &url)
 	}
@@ -187,3 +468,210 @@ func (qb *StudioQueryBuilder) GetAllUrls(ids []uuid.UUID) ([][]*URL, []error) {
 	}
 	return result, nil
 }
+
+func (qb *StudioQueryBuilder) ApplyEdit(edit Edit, operation OperationEnum, studio *Studio) (*Studio, error) {
+	data, err := edit.GetStudioData()
+	if err != nil {
+		return nil, err
+	}
+
+	switch operation {
+	case OperationEnumCreate:
+		now := time.Now()
+		UUID, err := uuid.NewV4()
+		if err != nil {
+			return nil, err
+		}
+		newStudio := Studio{
+			ID:        UUID,
+			CreatedAt: SQLiteTimestamp{Timestamp: now},
+			UpdatedAt: SQLiteTimestamp{Timestamp: now},
+		}
+		if data.New.Name == nil {
+			return nil, errors.New("Missing studio name")
+		}
+
+		newStudio.CopyFromStudioEdit(*data.New, StudioEdit{})
+
+		if err := qb.ValidateNames(newStudio.ID, newStudio.Name, data.New.AddedAliases); err != nil {
+			return nil, err
+		}
+
+		studio, err = qb.Create(newStudio)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(data.New.AddedAliases) > 0 {
+			aliases := CreateStudioAliases(UUID, data.New.AddedAliases)
+			if err := qb.CreateAliases(aliases); err != nil {
+				return nil, err
+			}
+		}
+
+		if len(data.New.AddedUrls) > 0 {
+			urls := CreateStudioUrls(UUID, data.New.AddedUrls)
+			if err := qb.CreateUrls(urls); err != nil {
+				return nil, err
+			}
+		}
+
+		if len(data.New.AddedImages) > 0 {
+			images := CreateStudioImages(UUID, data.New.AddedImages)
+			if err := qb.CreateImages(images); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := qb.updateChildStudios(studio, data.New.AddedChildStudios, nil); err != nil {
+			return nil, err
+		}
+
+		return studio, nil
+	case OperationEnumDestroy:
+		return qb.SoftDelete(*studio)
+	case OperationEnumModify:
+		return qb.ApplyModifyEdit(studio, data)
+	default:
+		return nil, errors.New("Unsupported operation: " + operation.String())
+	}
+}
+
+func (qb *StudioQueryBuilder) ApplyModifyEdit(studio *Studio, data *StudioEditData) (*Studio, error) {
+	if err := studio.ValidateModifyEdit(*data); err != nil {
+		return nil, err
+	}
+
+	studio.CopyFromStudioEdit(*data.New, *data.Old)
+	if err := qb.validateParent(studio); err != nil {
+		return nil, err
+	}
+
+	studio.UpdatedAt = SQLiteTimestamp{Timestamp: time.Now()}
+	updatedStudio, err := qb.Update(*studio)
+	if err != nil {
+		return nil, err
+	}
+
+	currentAliases, err := qb.GetAliases(updatedStudio.ID)
+	if err != nil {
+		return nil, err
+	}
+	newAliases := CreateStudioAliases(updatedStudio.ID, data.New.AddedAliases)
+	oldAliases := CreateStudioAliases(updatedStudio.ID, data.New.RemovedAliases)
+
+	if err := ProcessSlice(&currentAliases, &newAliases, &oldAliases); err != nil {
+		return nil, err
+	}
+	if err := qb.UpdateAliases(updatedStudio.ID, currentAliases); err != nil {
+		return nil, err
+	}
+
+	currentUrls, err := qb.GetUrls(updatedStudio.ID)
+	if err != nil {
+		return nil, err
+	}
+	newUrls := CreateStudioUrls(updatedStudio.ID, data.New.AddedUrls)
+	oldUrls := CreateStudioUrls(updatedStudio.ID, data.New.RemovedUrls)
+
+	if err := ProcessSlice(&currentUrls, &newUrls, &oldUrls); err != nil {
+		return nil, err
+	}
+	if err := qb.UpdateUrls(updatedStudio.ID, currentUrls); err != nil {
+		return nil, err
+	}
+
+	currentImages, err := qb.GetImages(updatedStudio.ID)
+	if err != nil {
+		return nil, err
+	}
+	newImages := CreateStudioImages(updatedStudio.ID, data.New.AddedImages)
+	oldImages := CreateStudioImages(updatedStudio.ID, data.New.RemovedImages)
+
+	if err := ProcessSlice(&currentImages, &newImages, &oldImages); err != nil {
+		return nil, err
+	}
+	if err := qb.UpdateImages(updatedStudio.ID, currentImages); err != nil {
+		return nil, err
+	}
+
+	if err := qb.updateChildStudios(updatedStudio, data.New.AddedChildStudios, data.New.RemovedChildStudios); err != nil {
+		return nil, err
+	}
+
+	if err := qb.ValidateNames(updatedStudio.ID, updatedStudio.Name, currentAliases.ToAliases()); err != nil {
+		return nil, err
+	}
+
+	return updatedStudio, nil
+}
+
+// validateParent returns an error if the parent of the studio is the studio
+// itself or one of its descendants.
+func (qb *StudioQueryBuilder) validateParent(studio *Studio) error {
+	if !studio.ParentStudioID.Valid {
+		return nil
+	}
+
+	parentID := studio.ParentStudioID.UUID
+	if parentID == studio.ID {
+		return errors.New("a studio cannot be its own parent")
+	}
+
+	descendants, err := qb.FindDescendants(studio.ID)
+	if err != nil {
+		return err
+	}
+	for _, descendant := range descendants {
+		if descendant.ID == parentID {
+			return errors.New("a studio cannot be the parent of its parent studio " + descendant.Name)
+		}
+	}
+
+	return nil
+}
+
+// updateChildStudios sets the parent of the added child studios to the
+// studio, and clears the parent of the removed child studios.
+func (qb *StudioQueryBuilder) updateChildStudios(studio *Studio, added []string, removed []string) error {
+	now := SQLiteTimestamp{Timestamp: time.Now()}
+
+	for _, id := range removed {
+		childID, _ := uuid.FromString(id)
+		child, err := qb.Find(childID)
+		if err != nil {
+			return err
+		}
+		if child == nil || child.ParentStudioID.UUID != studio.ID {
+			return errors.New("Invalid child studio removal. Studio is not a child: '" + id + "'")
+		}
+
+		child.ParentStudioID = uuid.NullUUID{}
+		child.UpdatedAt = now
+		if _, err := qb.Update(*child); err != nil {
+			return err
+		}
+	}
+
+	for _, id := range added {
+		childID, _ := uuid.FromString(id)
+		child, err := qb.Find(childID)
+		if err != nil {
+			return err
+		}
+		if child == nil {
+			return errors.New("Invalid child studio addition. Studio does not exist: '" + id + "'")
+		}
+
+		child.ParentStudioID = uuid.NullUUID{UUID: studio.ID, Valid: true}
+		if err := qb.validateParent(child); err != nil {
+			return err
+		}
+		child.UpdatedAt = now
+		if _, err := qb.Update(*child); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"database/sql"
 	"errors"
 	"time"
 
@@ -58,6 +59,15 @@ func (qb *TagQueryBuilder) SoftDelete(tag Tag) (*Tag, error) {
 	if err := qb.dbi.DeleteJoins(tagAliasTable, tag.ID); err != nil {
 		return nil, err
 	}
+	// Delete parent and child relations
+	if err := qb.dbi.DeleteJoins(tagParentTable, tag.ID); err != nil {
+		return nil, err
+	}
+	query := "DELETE FROM tag_parents WHERE parent_id = ?"
+	args := []interface{}{tag.ID}
+	if err := qb.dbi.RawQuery(tagParentTable.Table, query, args, nil); err != nil {
+		return nil, err
+	}
 	ret, err := qb.dbi.SoftDelete(tag)
 	return qb.toModel(ret), err
 }
@@ -98,6 +108,41 @@ func (qb *TagQueryBuilder) UpdateAliases(tagID uuid.UUID, updatedJoins TagAliase
 	return qb.dbi.ReplaceJoins(tagAliasTable, tagID, &updatedJoins)
 }
 
+func (qb *TagQueryBuilder) CreateParents(newJoins TagParents) error {
+	return qb.dbi.InsertJoins(tagParentTable, &newJoins)
+}
+
+func (qb *TagQueryBuilder) UpdateParents(tagID uuid.UUID, updatedJoins TagParents) error {
+	return qb.dbi.ReplaceJoins(tagParentTable, tagID, &updatedJoins)
+}
+
+// UpdateTagParents moves the parent and child relations of the old tag to
+// the new tag, skipping relations the new tag already has and relations
+// between the two tags.
+func (qb *TagQueryBuilder) UpdateTagParents(oldTagID uuid.UUID, newTagID uuid.UUID) error {
+	query := `INSERT INTO tag_parents (tag_id, parent_id)
+            SELECT ?, parent_id
+            FROM tag_parents WHERE tag_id = ? AND parent_id != ?
+            ON CONFLICT DO NOTHING`
+	args := []interface{}{newTagID, oldTagID, newTagID}
+	if err := qb.dbi.RawQuery(tagParentTable.Table, query, args, nil); err != nil {
+		return err
+	}
+
+	query = `INSERT INTO tag_parents (tag_id, parent_id)
+            SELECT tag_id, ?
+            FROM tag_parents WHERE parent_id = ? AND tag_id != ?
+            ON CONFLICT DO NOTHING`
+	if err := qb.dbi.RawQuery(tagParentTable.Table, query, args, nil); err != nil {
+		return err
+	}
+
+	// Delete any relations with the old tag
+	query = `DELETE FROM tag_parents WHERE tag_id = ? OR parent_id = ?`
+	args = []interface{}{oldTagID, oldTagID}
+	return qb.dbi.RawQuery(tagParentTable.Table, query, args, nil)
+}
+
 func (qb *TagQueryBuilder) Find(id uuid.UUID) (*Tag, error) {
 	ret, err := qb.dbi.Find(id, tagDBTable)
 	return qb.toModel(ret), err
@@ -106,7 +151,8 @@ func (qb *TagQueryBuilder) Find(id uuid.UUID) (*Tag, error) {
 func (qb *TagQueryBuilder) FindByNameOrAlias(name string) (*Tag, error) {
 	query := `SELECT tags.* FROM tags
 		left join tag_aliases on tags.id = tag_aliases.tag_id
-		WHERE tag_aliases.alias = ? OR tags.name = ?`
+		WHERE tag_aliases.alias = ? OR tags.name = ?
+		ORDER BY tags.deleted ASC`
 
 	args := []interface{}{name, name}
 	results, err := qb.queryTags(query, args)
@@ -116,6 +162,33 @@ func (qb *TagQueryBuilder) FindByNameOrAlias(name string) (*Tag, error) {
 	return results[0], nil
 }
 
+// FindRedirectTarget returns the tag that the deleted tag was merged into,
+// or nil if it was not merged.
+func (qb *TagQueryBuilder) FindRedirectTarget(id uuid.UUID) (*Tag, error) {
+	query := `SELECT tags.* FROM tags
+		JOIN tag_redirects ON tag_redirects.target_id = tags.id
+		WHERE tag_redirects.source_id = ?`
+
+	args := []interface{}{id}
+	results, err := qb.queryTags(query, args)
+	if err != nil || len(results) < 1 {
+		return nil, err
+	}
+	return results[0], nil
+}
+
+// ResolveName returns the tag with the name or alias, following the redirect
+// if the tag was merged into another tag. Active tags take precedence over
+// deleted tags with the same name. Returns nil if no tag matches.
+func (qb *TagQueryBuilder) ResolveName(name string) (*Tag, error) {
+	tag, err := qb.FindByNameOrAlias(name)
+	if err != nil || tag == nil || !tag.Deleted {
+		return tag, err
+	}
+
+	return qb.FindRedirectTarget(tag.ID)
+}
+
 func (qb *TagQueryBuilder) FindBySceneID(sceneID uuid.UUID) ([]*Tag, error) {
 	query := `
 		SELECT tags.* FROM tags
@@ -211,6 +284,210 @@ func (qb *TagQueryBuilder) FindByAlias(name string) ([]*Tag, error) {
 	return qb.queryTags(query, args)
 }
 
+// tagDescendantsQuery selects the ids of all tags below the given number of
+// tags. UNION discards repeated ids, so a cycle in the hierarchy terminates.
+func tagDescendantsQuery(count int) string {
+	return `
+	WITH RECURSIVE descendants AS (
+		SELECT tag_id AS id FROM tag_parents WHERE parent_id IN ` + getInBinding(count) + `
+		UNION
+		SELECT tag_parents.tag_id FROM tag_parents
+		JOIN descendants ON tag_parents.parent_id = descendants.id
+	)
+	SELECT id FROM descendants`
+}
+
+// FindParentIdsByIds returns the ids of the direct parents of each of the
+// tags, ordered by name.
+func (qb *TagQueryBuilder) FindParentIdsByIds(ids []uuid.UUID) ([][]uuid.UUID, []error) {
+	query := `
+		SELECT tag_parents.* FROM tag_parents
+		JOIN tags ON tags.id = tag_parents.parent_id
+		WHERE tag_parents.tag_id IN (?)
+		ORDER BY tags.name`
+	parents, err := qb.queryTagParents(query, ids)
+	if err != nil {
+		return nil, utils.DuplicateError(err, len(ids))
+	}
+
+	m := make(map[uuid.UUID][]uuid.UUID)
+	for _, parent := range parents {
+		m[parent.TagID] = append(m[parent.TagID], parent.ParentID)
+	}
+
+	result := make([][]uuid.UUID, len(ids))
+	for i, id := range ids {
+		result[i] = m[id]
+	}
+	return result, nil
+}
+
+// FindChildIdsByIds returns the ids of the direct children of each of the
+// tags, ordered by name.
+func (qb *TagQueryBuilder) FindChildIdsByIds(ids []uuid.UUID) ([][]uuid.UUID, []error) {
+	query := `
+		SELECT tag_parents.* FROM tag_parents
+		JOIN tags ON tags.id = tag_parents.tag_id
+		WHERE tag_parents.parent_id IN (?)
+		ORDER BY tags.name`
+	parents, err := qb.queryTagParents(query, ids)
+	if err != nil {
+		return nil, utils.DuplicateError(err, len(ids))
+	}
+
+	m := make(map[uuid.UUID][]uuid.UUID)
+	for _, parent := range parents {
+		m[parent.ParentID] = append(m[parent.ParentID], parent.TagID)
+	}
+
+	result := make([][]uuid.UUID, len(ids))
+	for i, id := range ids {
+		result[i] = m[id]
+	}
+	return result, nil
+}
+
+func (qb *TagQueryBuilder) queryTagParents(query string, ids []uuid.UUID) (TagParents, error) {
+	query, args, err := sqlx.In(query, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	var output TagParents
+	err = qb.dbi.RawQuery(tagParentTable.Table, query, args, &output)
+	return output, err
+}
+
+// FindDescendants returns all tags below the tag in the hierarchy, ordered
+// by name.
+func (qb *TagQueryBuilder) FindDescendants(id uuid.UUID) (Tags, error) {
+	query := `
+		SELECT tags.* FROM tags
+		WHERE tags.id IN (` + tagDescendantsQuery(1) + `)
+		ORDER BY tags.name`
+	args := []interface{}{id}
+	return qb.queryTags(query, args)
+}
+
+// FindImpliedIdsBySceneIds returns the ids of the non-deleted ancestors of
+// the tags of each of the scenes that are not tags of the scene themselves,
+// ordered by name.
+func (qb *TagQueryBuilder) FindImpliedIdsBySceneIds(ids []uuid.UUID) ([][]uuid.UUID, []error) {
+	query := `
+		WITH RECURSIVE implied (scene_id, tag_id) AS (
+			SELECT scene_tags.scene_id, tag_parents.parent_id FROM scene_tags
+			JOIN tag_parents ON tag_parents.tag_id = scene_tags.tag_id
+			WHERE scene_tags.scene_id IN (?)
+			UNION
+			SELECT implied.scene_id, tag_parents.parent_id FROM implied
+			JOIN tag_parents ON tag_parents.tag_id = implied.tag_id
+		)
+		SELECT implied.scene_id, implied.tag_id FROM implied
+		JOIN tags ON tags.id = implied.tag_id
+		WHERE tags.deleted = FALSE
+		AND NOT EXISTS (
+			SELECT 1 FROM scene_tags
+			WHERE scene_tags.scene_id = implied.scene_id
+			AND scene_tags.tag_id = implied.tag_id
+		)
+		ORDER BY tags.name`
+	query, args, err := sqlx.In(query, ids)
+	if err != nil {
+		return nil, utils.DuplicateError(err, len(ids))
+	}
+
+	var tags ScenesTags
+	if err := qb.dbi.RawQuery(sceneTagTable.Table, query, args, &tags); err != nil {
+		return nil, utils.DuplicateError(err, len(ids))
+	}
+
+	m := make(map[uuid.UUID][]uuid.UUID)
+	for _, tag := range tags {
+		m[tag.SceneID] = append(m[tag.SceneID], tag.TagID)
+	}
+
+	result := make([][]uuid.UUID, len(ids))
+	for i, id := range ids {
+		result[i] = m[id]
+	}
+	return result, nil
+}
+
+// ValidateParents returns an error if any of the parents does not exist, is
+// deleted, or is the tag itself or one of its descendants.
+func (qb *TagQueryBuilder) ValidateParents(id uuid.UUID, parentIDs []string) error {
+	if len(parentIDs) == 0 {
+		return nil
+	}
+
+	descendants, err := qb.FindDescendants(id)
+	if err != nil {
+		return err
+	}
+	descendantIDs := make(map[uuid.UUID]bool)
+	for _, descendant := range descendants {
+		descendantIDs[descendant.ID] = true
+	}
+
+	for _, parentID := range parentIDs {
+		parentUUID, err := uuid.FromString(parentID)
+		if err != nil {
+			return err
+		}
+		if parentUUID == id {
+			return errors.New("a tag cannot be its own parent")
+		}
+
+		parent, err := qb.Find(parentUUID)
+		if err != nil {
+			return err
+		}
+		if parent == nil || parent.Deleted {
+			return errors.New("parent tag not found: " + parentID)
+		}
+		if descendantIDs[parentUUID] {
+			return errors.New("a tag cannot be the parent of its parent tag " + parent.Name)
+		}
+	}
+
+	return nil
+}
+
+func (qb *TagQueryBuilder) applyParentsEdit(id uuid.UUID, edit TagEdit) error {
+	if len(edit.AddedParents) == 0 && len(edit.RemovedParents) == 0 {
+		return nil
+	}
+
+	if err := qb.ValidateParents(id, edit.AddedParents); err != nil {
+		return err
+	}
+
+	currentParents, err := qb.GetParents(id)
+	if err != nil {
+		return err
+	}
+	newParents := CreateTagParents(id, edit.AddedParents)
+	oldParents := CreateTagParents(id, edit.RemovedParents)
+
+	if err := ProcessSlice(&currentParents, &newParents, &oldParents); err != nil {
+		return err
+	}
+	return qb.UpdateParents(id, currentParents)
+}
+
+func (qb *TagQueryBuilder) validateNoCycle(id uuid.UUID) error {
+	descendants, err := qb.FindDescendants(id)
+	if err != nil {
+		return err
+	}
+	for _, descendant := range descendants {
+		if descendant.ID == id {
+			return errors.New("tag hierarchy cannot contain a cycle")
+		}
+	}
+	return nil
+}
+
 func (qb *TagQueryBuilder) Count() (int, error) {
 	return runCountQuery(buildCountQuery("SELECT tags.id FROM tags"), nil)
 }
@@ -236,6 +513,17 @@ func (qb *TagQueryBuilder) Query(tagFilter *TagFilterType, findFilter *QuerySpec
 		catID, _ := uuid.FromString(*q)
 		query.Eq("tags.category_id", catID)
 	}
+	if q := tagFilter.ParentID; q != nil && *q != "" {
+		parentID, _ := uuid.FromString(*q)
+		if tagFilter.IncludeDescendants != nil && *tagFilter.IncludeDescendants {
+			query.AddWhere("tags.id IN (" + tagDescendantsQuery(1) + ")")
+		} else {
+			query.AddWhere("EXISTS (SELECT 1 FROM tag_parents WHERE tag_parents.tag_id = tags.id AND tag_parents.parent_id = ?)")
+		}
+		query.AddArg(parentID)
+	}
+
+	handleIntCriterion(tagSceneCountColumn, tagFilter.SceneCount, query)
 
 	query.SortAndPagination = qb.getTagSort(findFilter) + getPagination(findFilter)
 	var tags Tags
@@ -259,9 +547,56 @@ func (qb *TagQueryBuilder) getTagSort(findFilter *QuerySpec) string {
 		sort = findFilter.GetSort("name")
 		direction = findFilter.GetDirection()
 	}
+	if sort == "scene_count" {
+		if direction != "ASC" && direction != "DESC" {
+			direction = "ASC"
+		}
+		return " ORDER BY " + tagSceneCountColumn + " " + direction + ", tags.name " + direction
+	}
 	return getSort(sort, direction, tagTable, nil)
 }
 
+// tagSceneCountColumn counts the non-deleted scenes of the tag in the
+// current row.
+const tagSceneCountColumn = `(SELECT COUNT(*) FROM scene_tags
+	JOIN scenes ON scenes.id = scene_tags.scene_id
+	WHERE scene_tags.tag_id = tags.id AND scenes.deleted = FALSE)`
+
+// GetAllSceneCounts returns the number of non-deleted scenes of each of the
+// provided tag ids.
+func (qb *TagQueryBuilder) GetAllSceneCounts(ids []uuid.UUID) ([]int, []error) {
+	var counts []struct {
+		TagID      uuid.UUID `db:"tag_id"`
+		SceneCount int       `db:"scene_count"`
+	}
+	query := `
+		SELECT scene_tags.tag_id, COUNT(*) AS scene_count
+		FROM scene_tags
+		JOIN scenes ON scenes.id = scene_tags.scene_id
+		WHERE scene_tags.tag_id IN (?)
+		AND scenes.deleted = FALSE
+		GROUP BY scene_tags.tag_id`
+	query, args, err := sqlx.In(query, ids)
+	if err != nil {
+		return nil, utils.DuplicateError(err, len(ids))
+	}
+	query = database.DB.Rebind(query)
+	if err := database.DB.Select(&counts, query, args...); err != nil && err != sql.ErrNoRows {
+		return nil, utils.DuplicateError(err, len(ids))
+	}
+
+	m := make(map[uuid.UUID]int)
+	for _, count := range counts {
+		m[count.TagID] = count.SceneCount
+	}
+
+	result := make([]int, len(ids))
+	for i, id := range ids {
+		result[i] = m[id]
+	}
+	return result, nil
+}
+
 func (qb *TagQueryBuilder) queryTags(query string, args []interface{}) (Tags, error) {
 	var output Tags
 	err := qb.dbi.RawQuery(tagDBTable, query, args, &output)
@@ -275,6 +610,13 @@ func (qb *TagQueryBuilder) GetRawAliases(id uuid.UUID) (TagAliases, error) {
 	return joins, err
 }
 
+func (qb *TagQueryBuilder) GetParents(id uuid.UUID) (TagParents, error) {
+	joins := TagParents{}
+	err := qb.dbi.FindJoins(tagParentTable, id, &joins)
+
+	return joins, err
+}
+
 func (qb *TagQueryBuilder) GetAliases(id uuid.UUID) ([]string, error) {
 	joins, err := qb.GetRawAliases(id)
 	return joins.ToAliases(), err
@@ -291,6 +633,12 @@ func (qb *TagQueryBuilder) MergeInto(sourceID uuid.UUID, targetID uuid.UUID) err
 	if tag.Deleted {
 		return errors.New("Merge source tag is deleted: " + sourceID.String())
 	}
+	if err := qb.UpdateTagParents(sourceID, targetID); err != nil {
+		return err
+	}
+	if err := qb.validateNoCycle(targetID); err != nil {
+		return err
+	}
 	_, err = qb.SoftDelete(*tag)
 	if err != nil {
 		return err
@@ -340,6 +688,16 @@ func (qb *TagQueryBuilder) ApplyEdit(edit Edit, operation OperationEnum, tag *Ta
 			}
 		}
 
+		if len(data.New.AddedParents) > 0 {
+			if err := qb.ValidateParents(UUID, data.New.AddedParents); err != nil {
+				return nil, err
+			}
+			parents := CreateTagParents(UUID, data.New.AddedParents)
+			if err := qb.CreateParents(parents); err != nil {
+				return nil, err
+			}
+		}
+
 		return tag, nil
 	case OperationEnumDestroy:
 		updatedTag, err := qb.SoftDelete(*tag)
@@ -370,6 +728,9 @@ func (qb *TagQueryBuilder) ApplyEdit(edit Edit, operation OperationEnum, tag *Ta
 		if err := qb.UpdateAliases(updatedTag.ID, currentAliases); err != nil {
 			return nil, err
 		}
+		if err := qb.applyParentsEdit(updatedTag.ID, *data.New); err != nil {
+			return nil, err
+		}
 
 		return updatedTag, err
 	case OperationEnumMerge:
@@ -401,6 +762,9 @@ func (qb *TagQueryBuilder) ApplyEdit(edit Edit, operation OperationEnum, tag *Ta
 		if err := qb.UpdateAliases(updatedTag.ID, currentAliases); err != nil {
 			return nil, err
 		}
+		if err := qb.applyParentsEdit(updatedTag.ID, *data.New); err != nil {
+			return nil, err
+		}
 
 		return updatedTag, nil
 	default:
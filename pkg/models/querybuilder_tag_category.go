@@ -1,6 +1,8 @@
 package models
 
 import (
+	"database/sql"
+
 	"github.com/jmoiron/sqlx"
 
 	"github.com/gofrs/uuid"
@@ -105,3 +107,32 @@ func (qb *TagCategoryQueryBuilder) getTagCategorySort(findFilter *QuerySpec) str
 	}
 	return getSort(sort, direction, tagCategoryTable, nil)
 }
+
+// GetUsage returns the usage of every tag category, optionally restricted to
+// the categories of a group, ordered by the number of scenes.
+func (qb *TagCategoryQueryBuilder) GetUsage(group *TagGroupEnum) ([]*TagCategoryUsage, error) {
+	query := `
+		SELECT tag_categories.id AS category_id,
+			COUNT(DISTINCT tags.id) AS tag_count,
+			COUNT(DISTINCT scenes.id) AS scene_count
+		FROM tag_categories
+		LEFT JOIN tags ON tags.category_id = tag_categories.id AND tags.deleted = FALSE
+		LEFT JOIN scene_tags ON scene_tags.tag_id = tags.id
+		LEFT JOIN scenes ON scenes.id = scene_tags.scene_id AND scenes.deleted = FALSE`
+	var args []interface{}
+	if group != nil {
+		query += `
+		WHERE tag_categories.group = ?`
+		args = append(args, group.String())
+	}
+	query += `
+		GROUP BY tag_categories.id
+		ORDER BY scene_count DESC, tag_categories.name`
+
+	var output []*TagCategoryUsage
+	query = database.DB.Rebind(query)
+	if err := database.DB.Select(&output, query, args...); err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	return output, nil
+}
@@ -0,0 +1,100 @@
+package models
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/stashapp/stash-box/pkg/database"
+)
+
+// FindByURL returns the non-deleted entities with a URL matching the
+// provided URL once both are normalized.
+func FindByURL(tx *sqlx.Tx, url string) (*URLMatches, error) {
+	pqb := NewPerformerQueryBuilder(tx)
+	performers, err := pqb.FindByURL(url)
+	if err != nil {
+		return nil, err
+	}
+
+	sqb := NewSceneQueryBuilder(tx)
+	scenes, err := sqb.FindByURL(url)
+	if err != nil {
+		return nil, err
+	}
+
+	stqb := NewStudioQueryBuilder(tx)
+	studios, err := stqb.FindByURL(url)
+	if err != nil {
+		return nil, err
+	}
+
+	gqb := NewGroupQueryBuilder(tx)
+	groups, err := gqb.FindByURL(url)
+	if err != nil {
+		return nil, err
+	}
+
+	return &URLMatches{
+		Performers: performers,
+		Scenes:     scenes,
+		Studios:    studios,
+		Groups:     groups,
+	}, nil
+}
+
+var matchedURLTable = database.NewTable("matched_urls", func() interface{} {
+	return &MatchedURL{}
+})
+
+// MatchedURL is a non-deleted entity with a URL matching one of the URLs
+// searched for.
+type MatchedURL struct {
+	URL        string    `db:"url"`
+	TargetType string    `db:"target_type"`
+	ID         uuid.UUID `db:"id"`
+	Name       string    `db:"name"`
+}
+
+type MatchedURLs []*MatchedURL
+
+func (p *MatchedURLs) Add(o interface{}) {
+	*p = append(*p, o.(*MatchedURL))
+}
+
+// findMatchedURLs returns the non-deleted entities with a URL matching one
+// of the provided URLs once both are normalized, in a single query. The URL of
+// each match is the provided URL it matched.
+func findMatchedURLs(dbi database.DBI, urls []string) (MatchedURLs, error) {
+	if len(urls) == 0 {
+		return nil, nil
+	}
+
+	query := `
+		WITH input (url) AS (SELECT DISTINCT unnest(CAST(? AS TEXT[])))
+		SELECT input.url, 'performer' AS target_type, performers.id, performers.name AS name FROM input
+		JOIN performer_urls ON normalize_url(performer_urls.url) = normalize_url(input.url)
+		JOIN performers ON performers.id = performer_urls.performer_id
+		WHERE performers.deleted = FALSE
+		UNION
+		SELECT input.url, 'scene', scenes.id, COALESCE(NULLIF(scenes.title, ''), CAST(scenes.id AS TEXT)) FROM input
+		JOIN scene_urls ON normalize_url(scene_urls.url) = normalize_url(input.url)
+		JOIN scenes ON scenes.id = scene_urls.scene_id
+		WHERE scenes.deleted = FALSE
+		UNION
+		SELECT input.url, 'studio', studios.id, studios.name FROM input
+		JOIN studio_urls ON normalize_url(studio_urls.url) = normalize_url(input.url)
+		JOIN studios ON studios.id = studio_urls.studio_id
+		WHERE studios.deleted = FALSE
+		UNION
+		SELECT input.url, 'group', groups.id, groups.title FROM input
+		JOIN group_urls ON normalize_url(group_urls.url) = normalize_url(input.url)
+		JOIN groups ON groups.id = group_urls.group_id
+		WHERE groups.deleted = FALSE
+		ORDER BY name`
+	args := []interface{}{pq.Array(urls)}
+
+	var output MatchedURLs
+	err := dbi.RawQuery(matchedURLTable, query, args, &output)
+	return output, err
+}
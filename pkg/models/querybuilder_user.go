@@ -1,6 +1,9 @@
 package models
 
 import (
+	"database/sql"
+	"time"
+
 	"github.com/gofrs/uuid"
 	"github.com/jmoiron/sqlx"
 	"github.com/stashapp/stash-box/pkg/database"
@@ -110,6 +113,12 @@ func (qb *UserQueryBuilder) Query(userFilter *UserFilterType, findFilter *QueryS
 		query.AddArg(thisArgs...)
 	}
 
+	handleIntCriterion("users.successful_edits", userFilter.SuccessfulEdits, query)
+	handleIntCriterion("users.unsuccessful_edits", userFilter.UnsuccessfulEdits, query)
+	handleIntCriterion("users.successful_votes", userFilter.SuccessfulVotes, query)
+	handleIntCriterion("users.unsuccessful_votes", userFilter.UnsuccessfulVotes, query)
+	handleIntCriterion("users.api_calls", userFilter.APICalls, query)
+
 	query.SortAndPagination = qb.getUserSort(findFilter) + getPagination(findFilter)
 	var studios Users
 	countResult, err := qb.dbi.Query(*query, &studios)
@@ -122,6 +131,96 @@ func (qb *UserQueryBuilder) Query(userFilter *UserFilterType, findFilter *QueryS
 	return studios, countResult
 }
 
+// UpdateClosedEditCounts increments the edit statistics of the author of the
+// closed edit and of the users who voted to accept or reject it.
+func (qb *UserQueryBuilder) UpdateClosedEditCounts(edit Edit) error {
+	var editColumn, voteColumn string
+	if edit.IsAccepted() {
+		editColumn = "successful_edits"
+		voteColumn = "successful_votes"
+	} else if edit.IsRejected() {
+		editColumn = "unsuccessful_edits"
+		voteColumn = "unsuccessful_votes"
+	} else {
+		return nil
+	}
+
+	query := "UPDATE users SET " + editColumn + " = " + editColumn + " + 1 WHERE id = ?"
+	args := []interface{}{edit.UserID}
+	if err := qb.dbi.RawQuery(userDBTable, query, args, nil); err != nil {
+		return err
+	}
+
+	query = `UPDATE users SET ` + voteColumn + ` = ` + voteColumn + ` + 1
+		WHERE id IN (SELECT user_id FROM edit_votes WHERE edit_id = ? AND type IN (?, ?))`
+	args = []interface{}{edit.ID, VoteTypeEnumAccept.String(), VoteTypeEnumReject.String()}
+	return qb.dbi.RawQuery(userDBTable, query, args, nil)
+}
+
+// AddAPICalls adds the API call counts to the stored counts of their user
+// and bucket, and sets the time of the last API call of the users. Counts of
+// users that no longer exist are dropped.
+func (qb *UserQueryBuilder) AddAPICalls(calls []*UserAPICalls, lastCall time.Time) error {
+	var userIDs []uuid.UUID
+	for _, c := range calls {
+		query := `INSERT INTO user_api_calls (user_id, bucket, calls)
+			SELECT ?, ?, ? WHERE EXISTS (SELECT 1 FROM users WHERE users.id = ?)
+			ON CONFLICT (user_id, bucket) DO UPDATE SET calls = user_api_calls.calls + EXCLUDED.calls`
+		args := []interface{}{c.UserID, c.Bucket, c.Calls, c.UserID}
+		if err := qb.dbi.RawQuery(userDBTable, query, args, nil); err != nil {
+			return err
+		}
+		userIDs = append(userIDs, c.UserID)
+	}
+
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	query, args, err := sqlx.In("UPDATE users SET last_api_call = ? WHERE id IN (?)", lastCall, userIDs)
+	if err != nil {
+		return err
+	}
+	return qb.dbi.RawQuery(userDBTable, query, args, nil)
+}
+
+// RefreshAPICalls removes the API call counts from before the time and sets
+// the API call total of each user to the sum of their remaining counts.
+func (qb *UserQueryBuilder) RefreshAPICalls(since time.Time) error {
+	query := "DELETE FROM user_api_calls WHERE bucket < ?"
+	args := []interface{}{since}
+	if err := qb.dbi.RawQuery(userDBTable, query, args, nil); err != nil {
+		return err
+	}
+
+	query = `UPDATE users SET api_calls = COALESCE(T.calls, 0)
+		FROM users U
+		LEFT JOIN (
+			SELECT user_id, SUM(calls) AS calls FROM user_api_calls GROUP BY user_id
+		) T ON T.user_id = U.id
+		WHERE users.id = U.id AND users.api_calls IS DISTINCT FROM COALESCE(T.calls, 0)`
+	return qb.dbi.RawQuery(userDBTable, query, nil, nil)
+}
+
+// FindTopAPIConsumers returns the users with the most API calls since the
+// time, most calls first.
+func (qb *UserQueryBuilder) FindTopAPIConsumers(since time.Time, limit int) ([]*UserAPICallCount, error) {
+	query := `
+		SELECT user_id, SUM(calls) AS api_calls
+		FROM user_api_calls
+		WHERE bucket >= ?
+		GROUP BY user_id
+		ORDER BY api_calls DESC, user_id
+		LIMIT ?`
+
+	var output []*UserAPICallCount
+	query = database.DB.Rebind(query)
+	if err := database.DB.Select(&output, query, since, limit); err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	return output, nil
+}
+
 func (qb *UserQueryBuilder) getUserSort(findFilter *QuerySpec) string {
 	var sort string
 	var direction string
@@ -141,6 +240,71 @@ func (qb *UserQueryBuilder) queryUsers(query string, args []interface{}) (Users,
 	return output, err
 }
 
+// UpdateTOTP sets the two-factor authentication secret of the user, and
+// whether it is enabled.
+func (qb *UserQueryBuilder) UpdateTOTP(id uuid.UUID, secret sql.NullString, enabled bool) error {
+	query := "UPDATE users SET totp_secret = ?, totp_enabled = ? WHERE id = ?"
+	args := []interface{}{secret, enabled, id}
+	return qb.dbi.RawQuery(userDBTable, query, args, nil)
+}
+
+// UpdateTOTPLastStep records the time step of the last two-factor
+// authentication code used by the user. Returns false if a code of the same
+// or a later step was already used.
+func (qb *UserQueryBuilder) UpdateTOTPLastStep(id uuid.UUID, step int64) (bool, error) {
+	query := "UPDATE users SET totp_last_step = ? WHERE id = ? AND totp_last_step < ? RETURNING users.*"
+	args := []interface{}{step, id, step}
+	output := Users{}
+	if err := qb.dbi.RawQuery(userDBTable, query, args, &output); err != nil {
+		return false, err
+	}
+	return len(output) > 0, nil
+}
+
+// RecordTOTPFailure counts an invalid two-factor authentication code entered
+// for the user. Once maxAttempts codes are counted, the user is locked out
+// until lockedUntil and the count restarts.
+func (qb *UserQueryBuilder) RecordTOTPFailure(id uuid.UUID, maxAttempts int, lockedUntil time.Time) error {
+	query := `UPDATE users SET
+		totp_locked_until = CASE WHEN totp_failed_attempts + 1 >= ? THEN ? ELSE totp_locked_until END,
+		totp_failed_attempts = CASE WHEN totp_failed_attempts + 1 >= ? THEN 0 ELSE totp_failed_attempts + 1 END
+		WHERE id = ?`
+	args := []interface{}{maxAttempts, SQLiteTimestamp{Timestamp: lockedUntil}, maxAttempts, id}
+	return qb.dbi.RawQuery(userDBTable, query, args, nil)
+}
+
+// ResetTOTPFailures clears the count of invalid two-factor authentication
+// codes entered for the user.
+func (qb *UserQueryBuilder) ResetTOTPFailures(id uuid.UUID) error {
+	query := "UPDATE users SET totp_failed_attempts = 0 WHERE id = ?"
+	args := []interface{}{id}
+	return qb.dbi.RawQuery(userDBTable, query, args, nil)
+}
+
+// UpdateSuspension sets the suspension of the user. An invalid id lifts the
+// user's suspension.
+func (qb *UserQueryBuilder) UpdateSuspension(id uuid.UUID, suspensionID uuid.NullUUID) error {
+	query := "UPDATE users SET suspension_id = ? WHERE id = ?"
+	args := []interface{}{suspensionID, id}
+	return qb.dbi.RawQuery(userDBTable, query, args, nil)
+}
+
+func (qb *UserQueryBuilder) UpdateRecoveryCodes(id uuid.UUID, codes UserRecoveryCodes) error {
+	return qb.dbi.ReplaceJoins(userRecoveryCodesTable, id, &codes)
+}
+
+// DestroyRecoveryCode deletes the recovery code of the user. Returns false if
+// the user has no such code.
+func (qb *UserQueryBuilder) DestroyRecoveryCode(id uuid.UUID, codeHash string) (bool, error) {
+	query := "DELETE FROM user_recovery_codes WHERE user_id = ? AND code_hash = ? RETURNING *"
+	args := []interface{}{id, codeHash}
+	output := UserRecoveryCodes{}
+	if err := qb.dbi.RawQuery(userRecoveryCodesTable.Table, query, args, &output); err != nil {
+		return false, err
+	}
+	return len(output) > 0, nil
+}
+
 func (qb *UserQueryBuilder) GetRoles(id uuid.UUID) (UserRoles, error) {
 	joins := UserRoles{}
 	err := qb.dbi.FindJoins(userRolesTable, id, &joins)
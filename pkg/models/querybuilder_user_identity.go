@@ -0,0 +1,33 @@
+package models
+
+import (
+	"github.com/jmoiron/sqlx"
+
+	"github.com/stashapp/stash-box/pkg/database"
+)
+
+type UserIdentityQueryBuilder struct {
+	dbi database.DBI
+}
+
+func NewUserIdentityQueryBuilder(tx *sqlx.Tx) UserIdentityQueryBuilder {
+	return UserIdentityQueryBuilder{
+		dbi: database.DBIWithTxn(tx),
+	}
+}
+
+func (qb *UserIdentityQueryBuilder) Create(identity UserIdentity) error {
+	query := "INSERT INTO " + userIdentityTable + " (issuer, subject, user_id, created_at) VALUES (?, ?, ?, ?)"
+	args := []interface{}{identity.Issuer, identity.Subject, identity.UserID, identity.CreatedAt}
+	return qb.dbi.RawQuery(userIdentityDBTable, query, args, nil)
+}
+
+func (qb *UserIdentityQueryBuilder) Find(issuer string, subject string) (*UserIdentity, error) {
+	query := "SELECT * FROM " + userIdentityTable + " WHERE issuer = ? AND subject = ?"
+	args := []interface{}{issuer, subject}
+	output := UserIdentities{}
+	if err := qb.dbi.RawQuery(userIdentityDBTable, query, args, &output); err != nil || len(output) < 1 {
+		return nil, err
+	}
+	return output[0], nil
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/stashapp/stash-box/pkg/database"
+)
+
+type UserModerationActionQueryBuilder struct {
+	dbi database.DBI
+}
+
+func NewUserModerationActionQueryBuilder(tx *sqlx.Tx) UserModerationActionQueryBuilder {
+	return UserModerationActionQueryBuilder{
+		dbi: database.DBIWithTxn(tx),
+	}
+}
+
+func (qb *UserModerationActionQueryBuilder) toModel(ro interface{}) *UserModerationAction {
+	if ro != nil {
+		return ro.(*UserModerationAction)
+	}
+
+	return nil
+}
+
+func (qb *UserModerationActionQueryBuilder) Create(newAction UserModerationAction) (*UserModerationAction, error) {
+	ret, err := qb.dbi.Insert(newAction)
+	return qb.toModel(ret), err
+}
+
+func (qb *UserModerationActionQueryBuilder) Find(id uuid.UUID) (*UserModerationAction, error) {
+	ret, err := qb.dbi.Find(id, userModerationActionDBTable)
+	return qb.toModel(ret), err
+}
+
+// FindByUserID returns the moderation history of the user, most recent first.
+func (qb *UserModerationActionQueryBuilder) FindByUserID(userID uuid.UUID) (UserModerationActions, error) {
+	query := "SELECT * FROM " + userModerationActionTable + " WHERE user_id = ? ORDER BY created_at DESC"
+	args := []interface{}{userID}
+	output := UserModerationActions{}
+	if err := qb.dbi.RawQuery(userModerationActionDBTable, query, args, &output); err != nil {
+		return nil, err
+	}
+	return output, nil
+}
@@ -0,0 +1,91 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/stashapp/stash-box/pkg/database"
+)
+
+type UserSessionQueryBuilder struct {
+	dbi database.DBI
+}
+
+func NewUserSessionQueryBuilder(tx *sqlx.Tx) UserSessionQueryBuilder {
+	return UserSessionQueryBuilder{
+		dbi: database.DBIWithTxn(tx),
+	}
+}
+
+func (qb *UserSessionQueryBuilder) toModel(ro interface{}) *UserSession {
+	if ro != nil {
+		return ro.(*UserSession)
+	}
+
+	return nil
+}
+
+func (qb *UserSessionQueryBuilder) Create(newSession UserSession) (*UserSession, error) {
+	ret, err := qb.dbi.Insert(newSession)
+	return qb.toModel(ret), err
+}
+
+func (qb *UserSessionQueryBuilder) Destroy(id uuid.UUID) error {
+	return qb.dbi.Delete(id, userSessionDBTable)
+}
+
+func (qb *UserSessionQueryBuilder) Find(id uuid.UUID) (*UserSession, error) {
+	ret, err := qb.dbi.Find(id, userSessionDBTable)
+	return qb.toModel(ret), err
+}
+
+func (qb *UserSessionQueryBuilder) FindByHash(hash string) (*UserSession, error) {
+	query := "SELECT * FROM " + userSessionTable + " WHERE token_hash = ?"
+	args := []interface{}{hash}
+	output := UserSessions{}
+	if err := qb.dbi.RawQuery(userSessionDBTable, query, args, &output); err != nil || len(output) < 1 {
+		return nil, err
+	}
+	return output[0], nil
+}
+
+// FindByUserID returns the unexpired sessions of the user, most recently
+// active first.
+func (qb *UserSessionQueryBuilder) FindByUserID(userID uuid.UUID, now time.Time) (UserSessions, error) {
+	query := "SELECT * FROM " + userSessionTable + " WHERE user_id = ? AND expires_at > ? ORDER BY last_active_at DESC"
+	args := []interface{}{userID, SQLiteTimestamp{Timestamp: now}}
+	output := UserSessions{}
+	if err := qb.dbi.RawQuery(userSessionDBTable, query, args, &output); err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// UpdateActivity sets the last active time, expiry time and IP address of
+// the session.
+func (qb *UserSessionQueryBuilder) UpdateActivity(id uuid.UUID, lastActive time.Time, expires time.Time, ip string) error {
+	query := "UPDATE " + userSessionTable + " SET last_active_at = ?, expires_at = ?, ip = ? WHERE id = ?"
+	args := []interface{}{SQLiteTimestamp{Timestamp: lastActive}, SQLiteTimestamp{Timestamp: expires}, ip, id}
+	return qb.dbi.RawQuery(userSessionDBTable, query, args, nil)
+}
+
+// DestroyByUserID deletes the sessions of the user, except the session with
+// the id except, if set.
+func (qb *UserSessionQueryBuilder) DestroyByUserID(userID uuid.UUID, except *uuid.UUID) error {
+	query := "DELETE FROM " + userSessionTable + " WHERE user_id = ?"
+	args := []interface{}{userID}
+	if except != nil {
+		query += " AND id <> ?"
+		args = append(args, *except)
+	}
+	return qb.dbi.RawQuery(userSessionDBTable, query, args, nil)
+}
+
+// DestroyExpired deletes the sessions that expired before now.
+func (qb *UserSessionQueryBuilder) DestroyExpired(now time.Time) error {
+	query := "DELETE FROM " + userSessionTable + " WHERE expires_at <= ?"
+	args := []interface{}{SQLiteTimestamp{Timestamp: now}}
+	return qb.dbi.RawQuery(userSessionDBTable, query, args, nil)
+}
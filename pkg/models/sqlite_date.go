@@ -2,6 +2,7 @@ package models
 
 import (
 	"database/sql/driver"
+	"strings"
 	"time"
 
 	"github.com/stashapp/stash-box/pkg/logger"
@@ -46,3 +47,34 @@ func (t SQLiteDate) Value() (driver.Value, error) {
 func (t SQLiteDate) IsValid() bool {
 	return t.Valid
 }
+
+// TruncateFuzzyDate returns the first day of the period containing date for
+// the given accuracy, in yyyy-mm-dd format. Year and month accurate dates may
+// omit the trailing components. The date is returned unchanged if it cannot
+// be parsed.
+func TruncateFuzzyDate(date string, accuracy DateAccuracyEnum) string {
+	t, err := parseFuzzyDate(date)
+	if err != nil {
+		return date
+	}
+
+	switch accuracy {
+	case DateAccuracyEnumYear:
+		t = time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	case DateAccuracyEnumMonth:
+		t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	return t.Format("2006-01-02")
+}
+
+func parseFuzzyDate(date string) (time.Time, error) {
+	switch strings.Count(date, "-") {
+	case 0:
+		return time.Parse("2006", date)
+	case 1:
+		return time.Parse("2006-01", date)
+	default:
+		return utils.ParseDateStringAsTime(date)
+	}
+}
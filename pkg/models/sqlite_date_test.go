@@ -0,0 +1,24 @@
+package models
+
+import "testing"
+
+func TestTruncateFuzzyDate(t *testing.T) {
+	tests := []struct {
+		date     string
+		accuracy DateAccuracyEnum
+		expected string
+	}{
+		{"2003-02-15", DateAccuracyEnumDay, "2003-02-15"},
+		{"2003-02-15", DateAccuracyEnumMonth, "2003-02-01"},
+		{"2003-02-15", DateAccuracyEnumYear, "2003-01-01"},
+		{"2003-02", DateAccuracyEnumMonth, "2003-02-01"},
+		{"2003", DateAccuracyEnumYear, "2003-01-01"},
+		{"invalid", DateAccuracyEnumYear, "invalid"},
+	}
+
+	for _, tt := range tests {
+		if got := TruncateFuzzyDate(tt.date, tt.accuracy); got != tt.expected {
+			t.Errorf("TruncateFuzzyDate(%s, %s): expected '%s' got '%s'", tt.date, tt.accuracy, tt.expected, got)
+		}
+	}
+}
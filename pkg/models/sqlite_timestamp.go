@@ -23,3 +23,28 @@ func (t SQLiteTimestamp) Value() (driver.Value, error) {
 func (t SQLiteTimestamp) IsValid() bool {
 	return !t.Timestamp.IsZero()
 }
+
+// NullSQLiteTimestamp is a SQLiteTimestamp that may be null.
+type NullSQLiteTimestamp struct {
+	Timestamp time.Time
+	Valid     bool
+}
+
+// Scan implements the Scanner interface.
+func (t *NullSQLiteTimestamp) Scan(value interface{}) error {
+	if value == nil {
+		t.Timestamp, t.Valid = time.Time{}, false
+		return nil
+	}
+
+	t.Timestamp, t.Valid = value.(time.Time), true
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (t NullSQLiteTimestamp) Value() (driver.Value, error) {
+	if !t.Valid {
+		return nil, nil
+	}
+	return t.Timestamp.Format(time.RFC3339), nil
+}
@@ -0,0 +1,83 @@
+package oidc
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// audience is the aud claim, which may be a string or an array of strings.
+type audience []string
+
+func (a *audience) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		*a = audience{s}
+		return nil
+	}
+
+	var l []string
+	if err := json.Unmarshal(b, &l); err != nil {
+		return err
+	}
+	*a = l
+	return nil
+}
+
+func (a audience) contains(s string) bool {
+	for _, v := range a {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// boolClaim is a boolean claim. Some providers send booleans as strings.
+type boolClaim bool
+
+func (b *boolClaim) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		v, err := strconv.ParseBool(s)
+		*b = boolClaim(v)
+		return err
+	}
+
+	var v bool
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*b = boolClaim(v)
+	return nil
+}
+
+// Claims are the claims of an id token used to identify the user.
+type Claims struct {
+	Issuer    string   `json:"iss"`
+	Subject   string   `json:"sub"`
+	Audience  audience `json:"aud"`
+	ExpiresAt int64    `json:"exp"`
+	IssuedAt  int64    `json:"iat"`
+	Nonce     string   `json:"nonce"`
+
+	Email             string    `json:"email"`
+	EmailVerified     boolClaim `json:"email_verified"`
+	PreferredUsername string    `json:"preferred_username"`
+	Name              string    `json:"name"`
+}
+
+// Valid checks that the token has not expired. It is called when the token
+// is parsed.
+func (c Claims) Valid() error {
+	if time.Now().Add(-clockSkew).Unix() > c.ExpiresAt {
+		return ErrTokenExpired
+	}
+	return nil
+}
+
+// IsEmailVerified returns true if the provider has verified that the user
+// owns their email address.
+func (c Claims) IsEmailVerified() bool {
+	return c.Email != "" && bool(c.EmailVerified)
+}
@@ -0,0 +1,276 @@
+// Package oidc implements login with an OpenID Connect identity provider,
+// using the authorization code flow with PKCE.
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+var (
+	ErrIssuerMismatch  = errors.New("issuer does not match the configured issuer")
+	ErrAudienceInvalid = errors.New("id token was not issued for this client")
+	ErrNonceInvalid    = errors.New("id token nonce does not match the request")
+	ErrTokenExpired    = errors.New("id token has expired")
+	ErrNoIDToken       = errors.New("token response does not contain an id token")
+	ErrUnknownKey      = errors.New("id token is signed with an unknown key")
+)
+
+// allowed difference between the clocks of the server and the issuer
+const clockSkew = time.Minute
+
+// Config is the client configuration of an identity provider.
+type Config struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	// Defaults to openid, email and profile
+	Scopes []string
+}
+
+type providerMetadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Provider is an OpenID Connect identity provider, configured by discovery.
+type Provider struct {
+	config   Config
+	client   *http.Client
+	metadata providerMetadata
+
+	mutex sync.Mutex
+	keys  map[string]interface{}
+}
+
+// NewProvider reads the configuration of the issuer from its discovery
+// document. The default HTTP client is used if client is nil.
+func NewProvider(ctx context.Context, config Config, client *http.Client) (*Provider, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	if len(config.Scopes) == 0 {
+		config.Scopes = []string{"openid", "email", "profile"}
+	}
+
+	p := &Provider{
+		config: config,
+		client: client,
+	}
+
+	discoveryURL := strings.TrimSuffix(config.Issuer, "/") + "/.well-known/openid-configuration"
+	if err := p.getJSON(ctx, discoveryURL, &p.metadata); err != nil {
+		return nil, fmt.Errorf("error reading discovery document: %s", err.Error())
+	}
+
+	if p.metadata.Issuer != config.Issuer {
+		return nil, ErrIssuerMismatch
+	}
+
+	return p, nil
+}
+
+// Issuer returns the issuer identifier of the provider.
+func (p *Provider) Issuer() string {
+	return p.metadata.Issuer
+}
+
+func (p *Provider) getJSON(ctx context.Context, u string, out interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+
+	return p.doJSON(req.WithContext(ctx), out)
+}
+
+func (p *Provider) doJSON(req *http.Request, out interface{}) error {
+	req.Header.Set("Accept", "application/json")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %s: %s", req.URL.String(), resp.Status, string(body))
+	}
+
+	return json.Unmarshal(body, out)
+}
+
+func randomString() string {
+	b := make([]byte, 32)
+	rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// AuthRequest is the state of an authorization request, which must be kept
+// by the client until the provider redirects back to it.
+type AuthRequest struct {
+	State        string
+	Nonce        string
+	CodeVerifier string
+}
+
+// NewAuthRequest returns an authorization request with random state, nonce
+// and PKCE code verifier.
+func NewAuthRequest() AuthRequest {
+	return AuthRequest{
+		State:        randomString(),
+		Nonce:        randomString(),
+		CodeVerifier: randomString(),
+	}
+}
+
+// CodeChallenge returns the S256 PKCE code challenge of the code verifier.
+func CodeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// AuthURL returns the URL of the provider to redirect the user to, to log
+// in.
+func (p *Provider) AuthURL(req AuthRequest) string {
+	v := url.Values{}
+	v.Set("response_type", "code")
+	v.Set("client_id", p.config.ClientID)
+	v.Set("redirect_uri", p.config.RedirectURL)
+	v.Set("scope", strings.Join(p.config.Scopes, " "))
+	v.Set("state", req.State)
+	v.Set("nonce", req.Nonce)
+	v.Set("code_challenge", CodeChallenge(req.CodeVerifier))
+	v.Set("code_challenge_method", "S256")
+
+	sep := "?"
+	if strings.Contains(p.metadata.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return p.metadata.AuthorizationEndpoint + sep + v.Encode()
+}
+
+type tokenResponse struct {
+	IDToken string `json:"id_token"`
+}
+
+// Exchange redeems the authorization code returned to the redirect URL, and
+// returns the verified claims of the user's id token.
+func (p *Provider) Exchange(ctx context.Context, code string, req AuthRequest) (*Claims, error) {
+	v := url.Values{}
+	v.Set("grant_type", "authorization_code")
+	v.Set("code", code)
+	v.Set("redirect_uri", p.config.RedirectURL)
+	v.Set("code_verifier", req.CodeVerifier)
+
+	httpReq, err := http.NewRequest(http.MethodPost, p.metadata.TokenEndpoint, strings.NewReader(v.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	httpReq.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+
+	var resp tokenResponse
+	if err := p.doJSON(httpReq.WithContext(ctx), &resp); err != nil {
+		return nil, fmt.Errorf("error redeeming authorization code: %s", err.Error())
+	}
+
+	if resp.IDToken == "" {
+		return nil, ErrNoIDToken
+	}
+
+	return p.VerifyIDToken(ctx, resp.IDToken, req.Nonce)
+}
+
+// VerifyIDToken checks the signature, issuer, audience, expiry and nonce of
+// an id token, and returns its claims.
+func (p *Provider) VerifyIDToken(ctx context.Context, rawToken string, nonce string) (*Claims, error) {
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(rawToken, claims, func(t *jwt.Token) (interface{}, error) {
+		switch t.Method.(type) {
+		case *jwt.SigningMethodRSA, *jwt.SigningMethodECDSA:
+		default:
+			return nil, fmt.Errorf("unsupported signing algorithm %s", t.Method.Alg())
+		}
+
+		kid, _ := t.Header["kid"].(string)
+		return p.getKey(ctx, kid)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.Issuer != p.metadata.Issuer {
+		return nil, ErrIssuerMismatch
+	}
+
+	if !claims.Audience.contains(p.config.ClientID) {
+		return nil, ErrAudienceInvalid
+	}
+
+	if claims.Nonce != nonce {
+		return nil, ErrNonceInvalid
+	}
+
+	return claims, nil
+}
+
+// getKey returns the signing key with the id, reloading the keys of the
+// provider if it is not known.
+func (p *Provider) getKey(ctx context.Context, kid string) (interface{}, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if key := p.findKey(kid); key != nil {
+		return key, nil
+	}
+
+	// keys may have been rotated
+	var set jsonWebKeySet
+	if err := p.getJSON(ctx, p.metadata.JWKSURI, &set); err != nil {
+		return nil, fmt.Errorf("error reading signing keys: %s", err.Error())
+	}
+
+	keys, err := set.publicKeys()
+	if err != nil {
+		return nil, err
+	}
+	p.keys = keys
+
+	if key := p.findKey(kid); key != nil {
+		return key, nil
+	}
+
+	return nil, ErrUnknownKey
+}
+
+func (p *Provider) findKey(kid string) interface{} {
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key
+		}
+	}
+
+	return p.keys[kid]
+}
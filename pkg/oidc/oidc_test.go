@@ -0,0 +1,132 @@
+package oidc_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stashapp/stash-box/pkg/oidc"
+	"github.com/stashapp/stash-box/pkg/oidc/oidctest"
+)
+
+const redirectURL = "https://stash-box.example.com/oidc/callback"
+
+// authorize follows the authorization request to the mock issuer, and
+// returns the code passed back to the redirect URL.
+func authorize(t *testing.T, p *oidc.Provider, req oidc.AuthRequest) string {
+	t.Helper()
+
+	client := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	resp, err := client.Get(p.AuthURL(req))
+	if err != nil {
+		t.Fatalf("authorization request failed: %s", err.Error())
+	}
+	resp.Body.Close()
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatalf("invalid redirect: %s", err.Error())
+	}
+
+	if got := location.Scheme + "://" + location.Host + location.Path; got != redirectURL {
+		t.Errorf("redirected to %s; want %s", got, redirectURL)
+	}
+
+	if got := location.Query().Get("state"); got != req.State {
+		t.Errorf("state = %s; want %s", got, req.State)
+	}
+
+	return location.Query().Get("code")
+}
+
+func newProvider(t *testing.T) (*oidctest.Issuer, *oidc.Provider) {
+	t.Helper()
+
+	issuer, err := oidctest.NewIssuer()
+	if err != nil {
+		t.Fatalf("error starting issuer: %s", err.Error())
+	}
+
+	p, err := oidc.NewProvider(context.Background(), issuer.Config(redirectURL), nil)
+	if err != nil {
+		issuer.Close()
+		t.Fatalf("error discovering issuer: %s", err.Error())
+	}
+
+	return issuer, p
+}
+
+func TestExchange(t *testing.T) {
+	issuer, p := newProvider(t)
+	defer issuer.Close()
+
+	issuer.SetIdentity(oidctest.Identity{
+		Subject:           "user-1",
+		Email:             "user@example.com",
+		EmailVerified:     true,
+		PreferredUsername: "user",
+	})
+
+	req := oidc.NewAuthRequest()
+	code := authorize(t, p, req)
+
+	claims, err := p.Exchange(context.Background(), code, req)
+	if err != nil {
+		t.Fatalf("exchange failed: %s", err.Error())
+	}
+
+	if claims.Subject != "user-1" || claims.PreferredUsername != "user" || !claims.IsEmailVerified() {
+		t.Errorf("unexpected claims: %+v", claims)
+	}
+
+	// codes may only be redeemed once
+	if _, err := p.Exchange(context.Background(), code, req); err == nil {
+		t.Error("expected error redeeming code twice")
+	}
+}
+
+func TestExchangeWrongVerifier(t *testing.T) {
+	issuer, p := newProvider(t)
+	defer issuer.Close()
+
+	req := oidc.NewAuthRequest()
+	code := authorize(t, p, req)
+
+	req.CodeVerifier = oidc.NewAuthRequest().CodeVerifier
+	if _, err := p.Exchange(context.Background(), code, req); err == nil {
+		t.Error("expected error for wrong code verifier")
+	}
+}
+
+func TestExchangeWrongNonce(t *testing.T) {
+	issuer, p := newProvider(t)
+	defer issuer.Close()
+
+	req := oidc.NewAuthRequest()
+	code := authorize(t, p, req)
+
+	req.Nonce = "other"
+	if _, err := p.Exchange(context.Background(), code, req); err != oidc.ErrNonceInvalid {
+		t.Errorf("got %v; want ErrNonceInvalid", err)
+	}
+}
+
+func TestIssuerMismatch(t *testing.T) {
+	issuer, err := oidctest.NewIssuer()
+	if err != nil {
+		t.Fatalf("error starting issuer: %s", err.Error())
+	}
+	defer issuer.Close()
+
+	config := issuer.Config(redirectURL)
+	config.Issuer += "/"
+	if _, err := oidc.NewProvider(context.Background(), config, nil); err != oidc.ErrIssuerMismatch {
+		t.Errorf("got %v; want ErrIssuerMismatch", err)
+	}
+}
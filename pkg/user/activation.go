@@ -356,6 +356,11 @@ func ActivateResetPassword(tx *sqlx.Tx, activationKey string, newPassword string
 		return err
 	}
 
+	// end all login sessions of the user
+	if err := DestroyUserSessions(tx, user.ID, nil); err != nil {
+		return err
+	}
+
 	// delete the activation
 	if err := aqb.Destroy(id); err != nil {
 		return err
@@ -0,0 +1,96 @@
+package user
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/database"
+	"github.com/stashapp/stash-box/pkg/manager/config"
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+// apiCallBucketSize is the period covered by each stored API call count.
+const apiCallBucketSize = time.Hour
+
+type apiCallKey struct {
+	userID uuid.UUID
+	bucket time.Time
+}
+
+// apiCallCounter counts API calls in memory, so that they can be written to
+// the database in batches.
+type apiCallCounter struct {
+	mutex  sync.Mutex
+	counts map[apiCallKey]int
+}
+
+func newAPICallCounter() *apiCallCounter {
+	return &apiCallCounter{
+		counts: make(map[apiCallKey]int),
+	}
+}
+
+func (c *apiCallCounter) add(userID uuid.UUID, t time.Time, calls int) {
+	key := apiCallKey{
+		userID: userID,
+		bucket: t.Truncate(apiCallBucketSize),
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.counts[key] += calls
+}
+
+// drain returns the counted calls and resets the counter.
+func (c *apiCallCounter) drain() []*models.UserAPICalls {
+	c.mutex.Lock()
+	counts := c.counts
+	c.counts = make(map[apiCallKey]int)
+	c.mutex.Unlock()
+
+	var ret []*models.UserAPICalls
+	for key, calls := range counts {
+		ret = append(ret, &models.UserAPICalls{
+			UserID: key.userID,
+			Bucket: models.SQLiteTimestamp{Timestamp: key.bucket},
+			Calls:  calls,
+		})
+	}
+	return ret
+}
+
+var apiCalls = newAPICallCounter()
+
+// CountAPICall records a call to the API by the user. The call is written to
+// the database by the next FlushAPICalls.
+func CountAPICall(userID uuid.UUID) {
+	apiCalls.add(userID, time.Now(), 1)
+}
+
+// FlushAPICalls writes the API calls counted since the last flush, removes
+// the counts older than the configured window and updates the API call totals
+// of the users. The counted calls are kept if writing fails.
+func FlushAPICalls(ctx context.Context) error {
+	calls := apiCalls.drain()
+	now := time.Now()
+
+	err := database.WithTransaction(ctx, func(txn database.Transaction) error {
+		qb := models.NewUserQueryBuilder(txn.GetTx())
+		if err := qb.AddAPICalls(calls, now); err != nil {
+			return err
+		}
+
+		return qb.RefreshAPICalls(now.Add(-config.GetAPICallWindow()))
+	})
+
+	if err != nil {
+		for _, c := range calls {
+			apiCalls.add(c.UserID, c.Bucket.Timestamp, c.Calls)
+		}
+	}
+
+	return err
+}
@@ -2,14 +2,32 @@ package user
 
 import (
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/dgrijalva/jwt-go"
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
 
 	"github.com/stashapp/stash-box/pkg/manager/config"
+	"github.com/stashapp/stash-box/pkg/models"
+	"github.com/stashapp/stash-box/pkg/utils"
 )
 
-var ErrInvalidToken = errors.New("invalid apikey")
+var (
+	ErrInvalidToken       = errors.New("invalid apikey")
+	ErrEmptyAPIKeyName    = errors.New("empty api key name")
+	ErrAPIKeyExpiry       = errors.New("api key expiry must be in the future")
+	ErrAPIKeyRoles        = errors.New("api key roles must be roles of the user")
+	ErrAPIKeyNotExist     = errors.New("api key not found")
+	ErrAPIKeyAccessDenied = errors.New("api key belongs to another user")
+)
+
+// NamedAPIKeyPrefix distinguishes named API keys from the user API key.
+const NamedAPIKeyPrefix = "sbk_"
+
+// named keys are only marked as used once per apiKeyLastUsedInterval
+const apiKeyLastUsedInterval = time.Minute
 
 const APIKeySubject = "APIKey"
 
@@ -54,3 +72,148 @@ func GetUserIDFromAPIKey(apiKey string) (string, error) {
 
 	return claims.UserID, nil
 }
+
+// IsNamedAPIKey returns true if the api key is a named API key, rather than
+// the user API key.
+func IsNamedAPIKey(apiKey string) bool {
+	return strings.HasPrefix(apiKey, NamedAPIKeyPrefix)
+}
+
+func hashAPIKey(apiKey string) string {
+	return utils.SHA256FromString(apiKey)
+}
+
+// CreateAPIKey creates a named API key for the user. The key is granted the
+// input roles, which must be implied by the roles of the creating request, or
+// all roles of the request if none are given. It returns the created key and
+// the key string, which is not stored.
+func CreateAPIKey(tx *sqlx.Tx, userID uuid.UUID, requestRoles []models.RoleEnum, input models.APIKeyCreateInput) (*models.APIKey, string, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, "", ErrEmptyAPIKeyName
+	}
+
+	now := time.Now()
+	var expiresAt models.NullSQLiteTimestamp
+	if input.ExpiresAt != nil {
+		if !input.ExpiresAt.After(now) {
+			return nil, "", ErrAPIKeyExpiry
+		}
+		expiresAt = models.NullSQLiteTimestamp{Timestamp: *input.ExpiresAt, Valid: true}
+	}
+
+	roles := requestRoles
+	if input.Roles != nil {
+		if len(RestrictRoles(roles, input.Roles)) != len(input.Roles) {
+			return nil, "", ErrAPIKeyRoles
+		}
+		roles = nil
+		for _, role := range input.Roles {
+			if !role.IsValid() {
+				return nil, "", ErrAPIKeyRoles
+			}
+			if !containsRole(roles, role) {
+				roles = append(roles, role)
+			}
+		}
+	}
+
+	id, err := uuid.NewV4()
+	if err != nil {
+		return nil, "", err
+	}
+
+	key := NamedAPIKeyPrefix + utils.GenerateRandomKey(32)
+
+	qb := models.NewAPIKeyQueryBuilder(tx)
+	created, err := qb.Create(models.APIKey{
+		ID:        id,
+		UserID:    userID,
+		Name:      name,
+		KeyHash:   hashAPIKey(key),
+		ExpiresAt: expiresAt,
+		CreatedAt: models.SQLiteTimestamp{Timestamp: now},
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := qb.CreateRoles(models.CreateAPIKeyRoles(id, roles)); err != nil {
+		return nil, "", err
+	}
+
+	return created, key, nil
+}
+
+// RevokeAPIKey deletes the named API key. If userID is set, the key must
+// belong to that user.
+func RevokeAPIKey(tx *sqlx.Tx, id uuid.UUID, userID *uuid.UUID) error {
+	qb := models.NewAPIKeyQueryBuilder(tx)
+	key, err := qb.Find(id)
+	if err != nil {
+		return err
+	}
+
+	if key == nil {
+		return ErrAPIKeyNotExist
+	}
+
+	if userID != nil && key.UserID != *userID {
+		return ErrAPIKeyAccessDenied
+	}
+
+	return qb.Destroy(id)
+}
+
+// GetNamedAPIKeyUser validates the provided named API key and returns the
+// user ID and the roles granted by the key. The last used time of the key is
+// updated.
+func GetNamedAPIKeyUser(apiKey string) (string, []models.RoleEnum, error) {
+	qb := models.NewAPIKeyQueryBuilder(nil)
+	key, err := qb.FindByHash(hashAPIKey(apiKey))
+	if err != nil {
+		return "", nil, err
+	}
+
+	now := time.Now()
+	if key == nil || key.IsExpired(now) {
+		return "", nil, ErrInvalidToken
+	}
+
+	roles, err := qb.GetRoles(key.ID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if !key.LastUsedAt.Valid || now.Sub(key.LastUsedAt.Timestamp) >= apiKeyLastUsedInterval {
+		if err := qb.UpdateLastUsed(key.ID, now); err != nil {
+			return "", nil, err
+		}
+	}
+
+	return key.UserID.String(), roles.ToRoles(), nil
+}
+
+func containsRole(roles []models.RoleEnum, role models.RoleEnum) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// RestrictRoles returns the roles that are implied by one of the user roles.
+func RestrictRoles(userRoles []models.RoleEnum, roles []models.RoleEnum) []models.RoleEnum {
+	var ret []models.RoleEnum
+	for _, role := range roles {
+		for _, userRole := range userRoles {
+			if userRole.Implies(role) {
+				ret = append(ret, role)
+				break
+			}
+		}
+	}
+
+	return ret
+}
@@ -0,0 +1,258 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/stashapp/stash-box/pkg/database"
+	"github.com/stashapp/stash-box/pkg/logger"
+	"github.com/stashapp/stash-box/pkg/manager/config"
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+var ErrInvalidNotificationType = errors.New("invalid notification type")
+
+// NotificationSender sends notification emails. It is implemented by
+// email.Manager.
+type NotificationSender interface {
+	SendNotification(email, subject, body string) error
+}
+
+// defaultNotificationPreference returns the preference used when the user
+// has not set one for the type: notifications are shown but not emailed.
+func defaultNotificationPreference(userID uuid.UUID, t models.NotificationTypeEnum) *models.UserNotificationPreference {
+	return &models.UserNotificationPreference{
+		UserID:  userID,
+		Type:    t.String(),
+		Enabled: true,
+		Email:   false,
+	}
+}
+
+// GetNotificationPreferences returns the preference of the user for every
+// notification type.
+func GetNotificationPreferences(tx *sqlx.Tx, userID uuid.UUID) (models.UserNotificationPreferences, error) {
+	nqb := models.NewNotificationQueryBuilder(tx)
+	stored, err := nqb.GetPreferences(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	byType := make(map[string]*models.UserNotificationPreference)
+	for _, p := range stored {
+		byType[p.Type] = p
+	}
+
+	var ret models.UserNotificationPreferences
+	for _, t := range models.AllNotificationTypeEnum {
+		p, found := byType[t.String()]
+		if !found {
+			p = defaultNotificationPreference(userID, t)
+		}
+		ret = append(ret, p)
+	}
+
+	return ret, nil
+}
+
+// UpdateNotificationPreferences sets the preferences of the user for the
+// types in the input. Preferences for other types are unchanged.
+func UpdateNotificationPreferences(tx *sqlx.Tx, userID uuid.UUID, input []*models.NotificationPreferenceInput) (models.UserNotificationPreferences, error) {
+	prefs, err := GetNotificationPreferences(tx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, i := range input {
+		if !i.Type.IsValid() {
+			return nil, ErrInvalidNotificationType
+		}
+
+		for _, p := range prefs {
+			if p.Type == i.Type.String() {
+				p.Enabled = i.Enabled
+				p.Email = i.Email
+			}
+		}
+	}
+
+	nqb := models.NewNotificationQueryBuilder(tx)
+	if err := nqb.UpdatePreferences(userID, prefs); err != nil {
+		return nil, err
+	}
+
+	return prefs, nil
+}
+
+// Notify creates a notification of the edit for the user, unless the user
+// caused it or has disabled notifications of the type.
+func Notify(tx *sqlx.Tx, userID uuid.UUID, t models.NotificationTypeEnum, editID uuid.UUID, actorID uuid.UUID) error {
+	if userID == actorID {
+		return nil
+	}
+
+	nqb := models.NewNotificationQueryBuilder(tx)
+	prefs, err := nqb.GetPreferences(userID)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range prefs {
+		if p.Type == t.String() && !p.Enabled {
+			return nil
+		}
+	}
+
+	_, err = nqb.Create(models.Notification{
+		ID:        uuid.Must(uuid.NewV4()),
+		UserID:    userID,
+		Type:      t.String(),
+		EditID:    uuid.NullUUID{UUID: editID, Valid: true},
+		ActorID:   uuid.NullUUID{UUID: actorID, Valid: true},
+		CreatedAt: models.SQLiteTimestamp{Timestamp: time.Now()},
+	})
+	return err
+}
+
+// NotifyEditComment notifies the author of the edit that the actor commented
+// on it.
+func NotifyEditComment(tx *sqlx.Tx, edit *models.Edit, actorID uuid.UUID) error {
+	return Notify(tx, edit.UserID, models.NotificationTypeEnumEditComment, edit.ID, actorID)
+}
+
+// NotifyEditVote notifies the author of the edit that the actor voted on it.
+func NotifyEditVote(tx *sqlx.Tx, edit *models.Edit, actorID uuid.UUID) error {
+	return Notify(tx, edit.UserID, models.NotificationTypeEnumEditVote, edit.ID, actorID)
+}
+
+// NotifyEditApplied notifies the author of the edit that it was applied. If
+// the edit targets a performer, the authors of earlier applied edits to the
+// performer are notified that it changed.
+func NotifyEditApplied(tx *sqlx.Tx, edit *models.Edit, actorID uuid.UUID) error {
+	if err := Notify(tx, edit.UserID, models.NotificationTypeEnumEditApplied, edit.ID, actorID); err != nil {
+		return err
+	}
+
+	if edit.TargetType != models.TargetTypeEnumPerformer.String() {
+		return nil
+	}
+
+	eqb := models.NewEditQueryBuilder(tx)
+	performerID, err := eqb.FindPerformerID(edit.ID)
+	if err != nil || performerID == nil {
+		return err
+	}
+
+	edits, err := eqb.FindAppliedByPerformerID(*performerID)
+	if err != nil {
+		return err
+	}
+
+	notified := map[uuid.UUID]bool{
+		edit.UserID: true,
+	}
+	for _, e := range edits {
+		if notified[e.UserID] {
+			continue
+		}
+		notified[e.UserID] = true
+
+		if err := Notify(tx, e.UserID, models.NotificationTypeEnumPerformerEditApplied, edit.ID, actorID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SendNotificationDigests emails each user their unread notifications that
+// have not been emailed, for the types they have enabled emails for.
+// Notifications are marked as emailed once sent, so each is only included in
+// one digest. A digest that cannot be sent is logged and retried on the next
+// call, without preventing the digests of other users.
+func SendNotificationDigests(ctx context.Context, sender NotificationSender) error {
+	var notifications models.Notifications
+	if err := database.WithTransaction(ctx, func(txn database.Transaction) error {
+		nqb := models.NewNotificationQueryBuilder(txn.GetTx())
+
+		var err error
+		notifications, err = nqb.FindForDigest()
+		return err
+	}); err != nil {
+		return err
+	}
+
+	byUser := make(map[uuid.UUID]models.Notifications)
+	var userIDs []uuid.UUID
+	for _, n := range notifications {
+		if _, found := byUser[n.UserID]; !found {
+			userIDs = append(userIDs, n.UserID)
+		}
+		byUser[n.UserID] = append(byUser[n.UserID], n)
+	}
+
+	for _, userID := range userIDs {
+		if err := sendNotificationDigest(ctx, sender, userID, byUser[userID]); err != nil {
+			logger.Errorf("Error sending notification digest to user %s: %s", userID.String(), err.Error())
+		}
+	}
+
+	return nil
+}
+
+func sendNotificationDigest(ctx context.Context, sender NotificationSender, userID uuid.UUID, notifications models.Notifications) error {
+	return database.WithTransaction(ctx, func(txn database.Transaction) error {
+		uqb := models.NewUserQueryBuilder(txn.GetTx())
+		u, err := uqb.Find(userID)
+		if err != nil || u == nil {
+			return err
+		}
+
+		var ids []uuid.UUID
+		for _, n := range notifications {
+			ids = append(ids, n.ID)
+		}
+
+		nqb := models.NewNotificationQueryBuilder(txn.GetTx())
+		if err := nqb.MarkEmailed(ids, models.SQLiteTimestamp{Timestamp: time.Now()}); err != nil {
+			return err
+		}
+
+		subject := "Subject: stash-box notifications"
+		body := notificationDigestBody(config.GetHostURL(), notifications)
+		return sender.SendNotification(u.Email, subject, body)
+	})
+}
+
+func notificationDigestBody(hostURL string, notifications models.Notifications) string {
+	var lines []string
+	lines = append(lines, "You have new notifications:", "")
+
+	for _, n := range notifications {
+		var line string
+		switch n.Type {
+		case models.NotificationTypeEnumEditComment.String():
+			line = "A comment was posted on your edit"
+		case models.NotificationTypeEnumEditVote.String():
+			line = "A vote was cast on your edit"
+		case models.NotificationTypeEnumEditApplied.String():
+			line = "Your edit was applied"
+		case models.NotificationTypeEnumPerformerEditApplied.String():
+			line = "An edit was applied to a performer you edited"
+		default:
+			continue
+		}
+
+		if n.EditID.Valid {
+			line += ": " + hostURL + "/edits/" + n.EditID.UUID.String()
+		}
+		lines = append(lines, "- "+line)
+	}
+
+	return strings.Join(lines, "\r\n")
+}
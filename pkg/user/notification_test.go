@@ -0,0 +1,45 @@
+package user
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+func TestNotificationDigestBody(t *testing.T) {
+	editID := uuid.Must(uuid.NewV4())
+	notifications := models.Notifications{
+		{
+			Type:   models.NotificationTypeEnumEditComment.String(),
+			EditID: uuid.NullUUID{UUID: editID, Valid: true},
+		},
+		{
+			Type: models.NotificationTypeEnumEditApplied.String(),
+		},
+		{
+			Type: "UNKNOWN",
+		},
+	}
+
+	body := notificationDigestBody("https://example.com", notifications)
+	lines := strings.Split(body, "\r\n")
+
+	want := []string{
+		"You have new notifications:",
+		"",
+		"- A comment was posted on your edit: https://example.com/edits/" + editID.String(),
+		"- Your edit was applied",
+	}
+
+	if len(lines) != len(want) {
+		t.Fatalf("notificationDigestBody() returned %d lines, want %d:\n%s", len(lines), len(want), body)
+	}
+
+	for i := range want {
+		if lines[i] != want[i] {
+			t.Errorf("notificationDigestBody() line %d = %q, want %q", i, lines[i], want[i])
+		}
+	}
+}
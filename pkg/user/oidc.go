@@ -0,0 +1,207 @@
+package user
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/stashapp/stash-box/pkg/models"
+	"github.com/stashapp/stash-box/pkg/oidc"
+	"github.com/stashapp/stash-box/pkg/utils"
+)
+
+var (
+	ErrOIDCNoAccount       = errors.New("no account is linked to this identity")
+	ErrOIDCEmailInUse      = errors.New("an account with this email exists, but the identity provider has not verified the email")
+	ErrOIDCEmailUnverified = errors.New("an account cannot be created because the identity provider has not verified the email")
+
+	ErrOIDCLinkRequired  = errors.New("the account with this email must link the identity after logging in")
+	ErrOIDCIdentityInUse = errors.New("identity is linked to another account")
+)
+
+// oidcLinkRoles are the roles whose users must link an identity themselves,
+// rather than have it linked by email.
+var oidcLinkRoles = []models.RoleEnum{
+	models.RoleEnumModify,
+	models.RoleEnumManageInvites,
+}
+
+// maximum number of suffixes tried to make a provisioned user name unique
+const maxNameSuffix = 100
+
+// LoginOIDC returns the user linked to the identity of the id token claims.
+// Identities without a linked user are linked to the user with the same
+// email, if the issuer has verified it and the user has neither two-factor
+// authentication nor privileged roles. Otherwise, a user is created if
+// autoProvision is true and the issuer has verified the email.
+func LoginOIDC(tx *sqlx.Tx, claims *oidc.Claims, autoProvision bool) (*models.User, error) {
+	iqb := models.NewUserIdentityQueryBuilder(tx)
+	uqb := models.NewUserQueryBuilder(tx)
+
+	identity, err := iqb.Find(claims.Issuer, claims.Subject)
+	if err != nil {
+		return nil, err
+	}
+
+	if identity != nil {
+		u, err := uqb.Find(identity.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if u == nil {
+			return nil, ErrOIDCNoAccount
+		}
+		return u, nil
+	}
+
+	var u *models.User
+	if claims.Email != "" {
+		u, err = uqb.FindByEmail(claims.Email)
+		if err != nil {
+			return nil, err
+		}
+
+		if u != nil && !claims.IsEmailVerified() {
+			return nil, ErrOIDCEmailInUse
+		}
+
+		if u != nil {
+			if err := validateOIDCAutoLink(&uqb, u); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if u == nil {
+		if !autoProvision {
+			return nil, ErrOIDCNoAccount
+		}
+
+		u, err = provisionOIDCUser(tx, claims)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = iqb.Create(models.UserIdentity{
+		Issuer:    claims.Issuer,
+		Subject:   claims.Subject,
+		UserID:    u.ID,
+		CreatedAt: models.SQLiteTimestamp{Timestamp: time.Now()},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return u, nil
+}
+
+// validateOIDCAutoLink returns ErrOIDCLinkRequired if an identity may not be
+// linked to the user by email alone. Logging in with a linked identity skips
+// two-factor authentication, so these users must link the identity from a
+// session that passed it.
+func validateOIDCAutoLink(uqb *models.UserQueryBuilder, u *models.User) error {
+	if u.TOTPEnabled {
+		return ErrOIDCLinkRequired
+	}
+
+	roles, err := uqb.GetRoles(u.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, role := range roles.ToRoles() {
+		for _, linkRole := range oidcLinkRoles {
+			if role.Implies(linkRole) {
+				return ErrOIDCLinkRequired
+			}
+		}
+	}
+
+	return nil
+}
+
+// LinkOIDCIdentity links the identity of the id token claims to the user.
+func LinkOIDCIdentity(tx *sqlx.Tx, userID uuid.UUID, claims *oidc.Claims) error {
+	iqb := models.NewUserIdentityQueryBuilder(tx)
+
+	identity, err := iqb.Find(claims.Issuer, claims.Subject)
+	if err != nil {
+		return err
+	}
+
+	if identity != nil {
+		if identity.UserID != userID {
+			return ErrOIDCIdentityInUse
+		}
+		return nil
+	}
+
+	return iqb.Create(models.UserIdentity{
+		Issuer:    claims.Issuer,
+		Subject:   claims.Subject,
+		UserID:    userID,
+		CreatedAt: models.SQLiteTimestamp{Timestamp: time.Now()},
+	})
+}
+
+// provisionOIDCUser creates a user with the default roles for an identity.
+// The user has a random password, so can only log in with the identity until
+// they reset it.
+func provisionOIDCUser(tx *sqlx.Tx, claims *oidc.Claims) (*models.User, error) {
+	if !claims.IsEmailVerified() {
+		return nil, ErrOIDCEmailUnverified
+	}
+
+	if err := validateUserEmail(claims.Email); err != nil {
+		return nil, err
+	}
+
+	name, err := getOIDCUserName(models.NewUserQueryBuilder(tx), claims)
+	if err != nil {
+		return nil, err
+	}
+
+	return Create(tx, models.UserCreateInput{
+		Name:     name,
+		Password: utils.GenerateRandomPassword(32),
+		Roles:    getDefaultUserRoles(),
+		Email:    claims.Email,
+	})
+}
+
+// getOIDCUserName returns an unused user name based on the identity's
+// preferred user name, name or email.
+func getOIDCUserName(qb models.UserQueryBuilder, claims *oidc.Claims) (string, error) {
+	base := strings.TrimSpace(claims.PreferredUsername)
+	if base == "" {
+		base = strings.TrimSpace(claims.Name)
+	}
+	if base == "" {
+		base = strings.Split(claims.Email, "@")[0]
+	}
+
+	if err := validateUserName(base); err != nil {
+		return "", err
+	}
+
+	name := base
+	for i := 2; i <= maxNameSuffix; i++ {
+		existing, err := qb.FindByName(name)
+		if err != nil {
+			return "", err
+		}
+
+		if existing == nil {
+			return name, nil
+		}
+
+		name = base + strconv.Itoa(i)
+	}
+
+	return "", errors.New("no unused user name for " + base)
+}
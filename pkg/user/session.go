@@ -0,0 +1,144 @@
+package user
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/stashapp/stash-box/pkg/manager/config"
+	"github.com/stashapp/stash-box/pkg/models"
+	"github.com/stashapp/stash-box/pkg/utils"
+)
+
+var (
+	ErrSessionNotExist     = errors.New("session not found")
+	ErrSessionAccessDenied = errors.New("session belongs to another user")
+)
+
+// the activity of a session is only recorded once per sessionActivityInterval
+const sessionActivityInterval = time.Minute
+
+const maxDeviceLength = 255
+
+// SessionInfo describes the client creating a login session.
+type SessionInfo struct {
+	// Name of the device. Described from the user agent if empty.
+	Device    string
+	IP        string
+	UserAgent string
+}
+
+func hashSessionToken(token string) string {
+	return utils.SHA256FromString(token)
+}
+
+// CreateSession creates a login session for the user. It returns the session
+// token to be stored by the client, which is not stored.
+func CreateSession(tx *sqlx.Tx, userID uuid.UUID, info SessionInfo) (string, error) {
+	qb := models.NewUserSessionQueryBuilder(tx)
+
+	now := time.Now()
+	if err := qb.DestroyExpired(now); err != nil {
+		return "", err
+	}
+
+	id, err := uuid.NewV4()
+	if err != nil {
+		return "", err
+	}
+
+	device := info.Device
+	if device == "" {
+		device = utils.DescribeUserAgent(info.UserAgent)
+	}
+	if len(device) > maxDeviceLength {
+		device = device[:maxDeviceLength]
+	}
+
+	token := utils.GenerateRandomKey(32)
+	_, err = qb.Create(models.UserSession{
+		ID:           id,
+		UserID:       userID,
+		TokenHash:    hashSessionToken(token),
+		Device:       device,
+		IP:           info.IP,
+		UserAgent:    info.UserAgent,
+		CreatedAt:    models.SQLiteTimestamp{Timestamp: now},
+		LastActiveAt: models.SQLiteTimestamp{Timestamp: now},
+		ExpiresAt:    models.SQLiteTimestamp{Timestamp: now.Add(config.GetSessionExpiry())},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// GetSession returns the unexpired session with the token, or nil if there
+// is none. The expiry of the session is extended, and the IP address it was
+// last used from is recorded.
+func GetSession(token string, ip string) (*models.UserSession, error) {
+	qb := models.NewUserSessionQueryBuilder(nil)
+	session, err := qb.FindByHash(hashSessionToken(token))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if session == nil || !session.ExpiresAt.Timestamp.After(now) {
+		return nil, nil
+	}
+
+	if now.Sub(session.LastActiveAt.Timestamp) >= sessionActivityInterval || session.IP != ip {
+		expires := now.Add(config.GetSessionExpiry())
+		if err := qb.UpdateActivity(session.ID, now, expires, ip); err != nil {
+			return nil, err
+		}
+
+		session.LastActiveAt = models.SQLiteTimestamp{Timestamp: now}
+		session.ExpiresAt = models.SQLiteTimestamp{Timestamp: expires}
+		session.IP = ip
+	}
+
+	return session, nil
+}
+
+// DestroySession deletes the session with the token, if it exists.
+func DestroySession(tx *sqlx.Tx, token string) error {
+	qb := models.NewUserSessionQueryBuilder(tx)
+	session, err := qb.FindByHash(hashSessionToken(token))
+	if err != nil || session == nil {
+		return err
+	}
+
+	return qb.Destroy(session.ID)
+}
+
+// RevokeSession deletes the session. If userID is set, the session must
+// belong to that user.
+func RevokeSession(tx *sqlx.Tx, id uuid.UUID, userID *uuid.UUID) error {
+	qb := models.NewUserSessionQueryBuilder(tx)
+	session, err := qb.Find(id)
+	if err != nil {
+		return err
+	}
+
+	if session == nil {
+		return ErrSessionNotExist
+	}
+
+	if userID != nil && session.UserID != *userID {
+		return ErrSessionAccessDenied
+	}
+
+	return qb.Destroy(id)
+}
+
+// DestroyUserSessions deletes the sessions of the user, except the session
+// with the id except, if set.
+func DestroyUserSessions(tx *sqlx.Tx, userID uuid.UUID, except *uuid.UUID) error {
+	qb := models.NewUserSessionQueryBuilder(tx)
+	return qb.DestroyByUserID(userID, except)
+}
@@ -0,0 +1,203 @@
+package user
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+var (
+	ErrEmptySuspensionReason = errors.New("empty suspension reason")
+	ErrSuspensionExpiry      = errors.New("suspension end must be in the future")
+	ErrSuspendSelf           = errors.New("cannot suspend yourself")
+	ErrSuspendRoot           = errors.New("root user cannot be suspended")
+	ErrNotSuspended          = errors.New("user is not suspended")
+)
+
+// SuspendedError is returned when a suspended user attempts to log in, use an
+// API key or make changes.
+type SuspendedError struct {
+	// Until is the time the suspension ends, or nil if it does not end.
+	Until  *time.Time
+	Reason string
+}
+
+func (e *SuspendedError) Error() string {
+	if e.Until == nil {
+		return "account suspended: " + e.Reason
+	}
+
+	return fmt.Sprintf("account suspended until %s: %s", e.Until.UTC().Format(time.RFC3339), e.Reason)
+}
+
+// IsSuspendedError returns true if err is a SuspendedError.
+func IsSuspendedError(err error) bool {
+	_, ok := err.(*SuspendedError)
+	return ok
+}
+
+// Suspend suspends the user until the given time, or indefinitely if until is
+// nil. The user's edits and votes are kept.
+func Suspend(tx *sqlx.Tx, userID uuid.UUID, moderatorID uuid.UUID, until *time.Time, reason string) (*models.UserModerationAction, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, ErrEmptySuspensionReason
+	}
+
+	now := time.Now()
+	if until != nil && !until.After(now) {
+		return nil, ErrSuspensionExpiry
+	}
+
+	if userID == moderatorID {
+		return nil, ErrSuspendSelf
+	}
+
+	uqb := models.NewUserQueryBuilder(tx)
+	u, err := uqb.Find(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if u == nil {
+		return nil, ErrUserNotExist
+	}
+
+	if u.Name == rootUserName {
+		return nil, ErrSuspendRoot
+	}
+
+	action := models.UserModerationAction{
+		ID:          uuid.Must(uuid.NewV4()),
+		UserID:      userID,
+		ModeratorID: uuid.NullUUID{UUID: moderatorID, Valid: true},
+		Action:      models.UserModerationActionEnumSuspend.String(),
+		Reason:      reason,
+		CreatedAt:   models.SQLiteTimestamp{Timestamp: now},
+	}
+	if until != nil {
+		action.Until = models.NullSQLiteTimestamp{Timestamp: *until, Valid: true}
+	}
+
+	mqb := models.NewUserModerationActionQueryBuilder(tx)
+	created, err := mqb.Create(action)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uqb.UpdateSuspension(userID, uuid.NullUUID{UUID: created.ID, Valid: true}); err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// Unsuspend lifts the active suspension of the user.
+func Unsuspend(tx *sqlx.Tx, userID uuid.UUID, moderatorID uuid.UUID, reason string) (*models.UserModerationAction, error) {
+	uqb := models.NewUserQueryBuilder(tx)
+	u, err := uqb.Find(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if u == nil {
+		return nil, ErrUserNotExist
+	}
+
+	suspension, err := GetSuspension(tx, u)
+	if err != nil {
+		return nil, err
+	}
+
+	if suspension == nil {
+		return nil, ErrNotSuspended
+	}
+
+	action := models.UserModerationAction{
+		ID:          uuid.Must(uuid.NewV4()),
+		UserID:      userID,
+		ModeratorID: uuid.NullUUID{UUID: moderatorID, Valid: true},
+		Action:      models.UserModerationActionEnumUnsuspend.String(),
+		Reason:      strings.TrimSpace(reason),
+		CreatedAt:   models.SQLiteTimestamp{Timestamp: time.Now()},
+	}
+
+	mqb := models.NewUserModerationActionQueryBuilder(tx)
+	created, err := mqb.Create(action)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uqb.UpdateSuspension(userID, uuid.NullUUID{}); err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// GetSuspension returns the active suspension of the user, or nil if the user
+// is not suspended. Suspensions lift once their end time has passed.
+func GetSuspension(tx *sqlx.Tx, u *models.User) (*models.UserModerationAction, error) {
+	if !u.SuspensionID.Valid {
+		return nil, nil
+	}
+
+	qb := models.NewUserModerationActionQueryBuilder(tx)
+	suspension, err := qb.Find(u.SuspensionID.UUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if suspension == nil || !suspension.IsActiveSuspension(time.Now()) {
+		return nil, nil
+	}
+
+	return suspension, nil
+}
+
+// ValidateNotSuspended returns a SuspendedError if the user is suspended.
+func ValidateNotSuspended(tx *sqlx.Tx, u *models.User) error {
+	suspension, err := GetSuspension(tx, u)
+	if err != nil || suspension == nil {
+		return err
+	}
+
+	ret := &SuspendedError{
+		Reason: suspension.Reason,
+	}
+	if suspension.Until.Valid {
+		until := suspension.Until.Timestamp
+		ret.Until = &until
+	}
+
+	return ret
+}
+
+// ValidateLoginSuspension returns a SuspendedError if the user logging in is
+// suspended.
+func ValidateLoginSuspension(tx *sqlx.Tx, userID uuid.UUID) error {
+	qb := models.NewUserQueryBuilder(tx)
+	u, err := qb.Find(userID)
+	if err != nil {
+		return err
+	}
+
+	if u == nil {
+		return ErrUserNotExist
+	}
+
+	return ValidateNotSuspended(tx, u)
+}
+
+// GetModerationHistory returns the moderation actions taken on the user, most
+// recent first.
+func GetModerationHistory(userID uuid.UUID) ([]*models.UserModerationAction, error) {
+	qb := models.NewUserModerationActionQueryBuilder(nil)
+	return qb.FindByUserID(userID)
+}
@@ -0,0 +1,289 @@
+package user
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/stashapp/stash-box/pkg/manager/config"
+	"github.com/stashapp/stash-box/pkg/models"
+	"github.com/stashapp/stash-box/pkg/utils"
+)
+
+var (
+	ErrTOTPRequired    = errors.New("two-factor authentication code required")
+	ErrInvalidTOTP     = errors.New("invalid two-factor authentication code")
+	ErrTOTPEnabled     = errors.New("two-factor authentication already enabled")
+	ErrTOTPNotEnabled  = errors.New("two-factor authentication not enabled")
+	ErrTOTPNotEnrolled = errors.New("two-factor authentication not enrolled")
+	ErrTOTPLocked      = errors.New("too many invalid two-factor authentication codes, try again later")
+)
+
+// number of time steps either side of the current step in which codes are
+// accepted, to allow for clock drift
+const totpSkew = 1
+
+const recoveryCodeCount = 10
+
+// number of invalid codes after which the user is locked out of two-factor
+// authentication, and the duration of the lockout
+const totpMaxFailedAttempts = 5
+const totpLockoutDuration = 15 * time.Minute
+
+func getTOTPUser(qb models.UserQueryBuilder, userID uuid.UUID) (*models.User, error) {
+	u, err := qb.Find(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if u == nil {
+		return nil, ErrUserNotExist
+	}
+
+	return u, nil
+}
+
+// normalizeRecoveryCode removes formatting from a recovery code, so that it
+// may be entered with any case or separators.
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
+
+func hashRecoveryCode(code string) string {
+	return utils.SHA256FromString(normalizeRecoveryCode(code))
+}
+
+func generateRecoveryCodes(qb models.UserQueryBuilder, userID uuid.UUID) ([]string, error) {
+	var codes []string
+	var joins models.UserRecoveryCodes
+	for i := 0; i < recoveryCodeCount; i++ {
+		key := utils.GenerateRandomKey(5)
+		code := key[:5] + "-" + key[5:]
+		codes = append(codes, code)
+		joins = append(joins, &models.UserRecoveryCode{
+			UserID:   userID,
+			CodeHash: hashRecoveryCode(code),
+		})
+	}
+
+	if err := qb.UpdateRecoveryCodes(userID, joins); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// verifyTOTP checks a two-factor authentication code, or recovery code, of
+// the user. Codes may only be used once. Users entering too many invalid
+// codes are locked out for a time.
+func verifyTOTP(qb models.UserQueryBuilder, u *models.User, code string) error {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return ErrTOTPRequired
+	}
+
+	now := time.Now()
+	if u.TOTPLockedUntil.Valid && u.TOTPLockedUntil.Timestamp.After(now) {
+		return ErrTOTPLocked
+	}
+
+	err := matchTOTP(qb, u, code, now)
+	if err == ErrInvalidTOTP {
+		// recorded outside the transaction, which is rolled back on failure
+		fqb := models.NewUserQueryBuilder(nil)
+		if recordErr := fqb.RecordTOTPFailure(u.ID, totpMaxFailedAttempts, now.Add(totpLockoutDuration)); recordErr != nil {
+			return recordErr
+		}
+	} else if err == nil && u.TOTPFailedAttempts > 0 {
+		err = qb.ResetTOTPFailures(u.ID)
+	}
+
+	return err
+}
+
+func matchTOTP(qb models.UserQueryBuilder, u *models.User, code string, now time.Time) error {
+	if len(code) == utils.TOTPDigits {
+		step, matched, err := utils.MatchTOTP(u.TOTPSecret.String, code, now, totpSkew)
+		if err != nil {
+			return err
+		}
+
+		if matched {
+			updated, err := qb.UpdateTOTPLastStep(u.ID, step)
+			if err != nil {
+				return err
+			}
+
+			if !updated {
+				// code has already been used
+				return ErrInvalidTOTP
+			}
+
+			return nil
+		}
+	}
+
+	if u.TOTPEnabled {
+		found, err := qb.DestroyRecoveryCode(u.ID, hashRecoveryCode(code))
+		if err != nil {
+			return err
+		}
+
+		if found {
+			return nil
+		}
+	}
+
+	return ErrInvalidTOTP
+}
+
+// EnrollTOTP generates a new two-factor authentication secret for the user.
+// It is not used until it is activated with ActivateTOTP.
+func EnrollTOTP(tx *sqlx.Tx, userID uuid.UUID) (*models.TOTPEnrollment, error) {
+	qb := models.NewUserQueryBuilder(tx)
+	u, err := getTOTPUser(qb, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.TOTPEnabled {
+		return nil, ErrTOTPEnabled
+	}
+
+	secret := utils.GenerateTOTPSecret()
+	if err := qb.UpdateTOTP(u.ID, sql.NullString{String: secret, Valid: true}, false); err != nil {
+		return nil, err
+	}
+
+	return &models.TOTPEnrollment{
+		Secret:          secret,
+		ProvisioningURI: utils.TOTPProvisioningURI(secret, config.GetTOTPIssuer(), u.Name),
+	}, nil
+}
+
+// ActivateTOTP enables two-factor authentication for the user, if the code
+// matches the enrolled secret. It returns the recovery codes of the user.
+func ActivateTOTP(tx *sqlx.Tx, userID uuid.UUID, code string) ([]string, error) {
+	qb := models.NewUserQueryBuilder(tx)
+	u, err := getTOTPUser(qb, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.TOTPEnabled {
+		return nil, ErrTOTPEnabled
+	}
+
+	if !u.TOTPSecret.Valid {
+		return nil, ErrTOTPNotEnrolled
+	}
+
+	if err := verifyTOTP(qb, u, code); err != nil {
+		return nil, err
+	}
+
+	if err := qb.UpdateTOTP(u.ID, u.TOTPSecret, true); err != nil {
+		return nil, err
+	}
+
+	return generateRecoveryCodes(qb, u.ID)
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes of the user, after
+// checking a two-factor authentication or recovery code.
+func RegenerateRecoveryCodes(tx *sqlx.Tx, userID uuid.UUID, code string) ([]string, error) {
+	qb := models.NewUserQueryBuilder(tx)
+	u, err := getTOTPUser(qb, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !u.TOTPEnabled {
+		return nil, ErrTOTPNotEnabled
+	}
+
+	if err := verifyTOTP(qb, u, code); err != nil {
+		return nil, err
+	}
+
+	return generateRecoveryCodes(qb, u.ID)
+}
+
+// DisableTOTP disables two-factor authentication for the user, after
+// checking a two-factor authentication or recovery code.
+func DisableTOTP(tx *sqlx.Tx, userID uuid.UUID, code string) error {
+	qb := models.NewUserQueryBuilder(tx)
+	u, err := getTOTPUser(qb, userID)
+	if err != nil {
+		return err
+	}
+
+	if !u.TOTPEnabled {
+		return ErrTOTPNotEnabled
+	}
+
+	if err := verifyTOTP(qb, u, code); err != nil {
+		return err
+	}
+
+	return ResetTOTP(tx, userID)
+}
+
+// ResetTOTP disables two-factor authentication for the user without
+// requiring a code.
+func ResetTOTP(tx *sqlx.Tx, userID uuid.UUID) error {
+	qb := models.NewUserQueryBuilder(tx)
+	if err := qb.UpdateTOTP(userID, sql.NullString{}, false); err != nil {
+		return err
+	}
+
+	return qb.UpdateRecoveryCodes(userID, nil)
+}
+
+// ValidateLoginTOTP checks the two-factor authentication or recovery code
+// provided when logging in, if the user has two-factor authentication
+// enabled.
+func ValidateLoginTOTP(tx *sqlx.Tx, userID uuid.UUID, code string) error {
+	qb := models.NewUserQueryBuilder(tx)
+	u, err := getTOTPUser(qb, userID)
+	if err != nil {
+		return err
+	}
+
+	if !u.TOTPEnabled {
+		return nil
+	}
+
+	return verifyTOTP(qb, u, code)
+}
+
+// FilterTOTPRoles removes the roles that imply a role requiring two-factor
+// authentication, if the user does not have it enabled.
+func FilterTOTPRoles(u *models.User, roles []models.RoleEnum) []models.RoleEnum {
+	required := config.GetTOTPRequiredRoles()
+	if u == nil || u.TOTPEnabled || len(required) == 0 {
+		return roles
+	}
+
+	var ret []models.RoleEnum
+	for _, role := range roles {
+		allowed := true
+		for _, r := range required {
+			if role.Implies(models.RoleEnum(strings.ToUpper(r))) {
+				allowed = false
+				break
+			}
+		}
+
+		if allowed {
+			ret = append(ret, role)
+		}
+	}
+
+	return ret
+}
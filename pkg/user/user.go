@@ -413,7 +413,9 @@ func RegenerateAPIKey(tx *sqlx.Tx, userID string) (string, error) {
 	return user.APIKey, nil
 }
 
-func ChangePassword(tx *sqlx.Tx, userID string, currentPassword string, newPassword string) error {
+// ChangePassword sets the password of the user, and ends their login
+// sessions other than currentSessionID, if set.
+func ChangePassword(tx *sqlx.Tx, userID string, currentPassword string, newPassword string, currentSessionID *uuid.UUID) error {
 	qb := models.NewUserQueryBuilder(tx)
 
 	userUUID, _ := uuid.FromString(userID)
@@ -443,7 +445,11 @@ func ChangePassword(tx *sqlx.Tx, userID string, currentPassword string, newPassw
 	user.UpdatedAt = models.SQLiteTimestamp{Timestamp: time.Now()}
 
 	user, err = qb.Update(*user)
-	return err
+	if err != nil {
+		return err
+	}
+
+	return DestroyUserSessions(tx, user.ID, currentSessionID)
 }
 
 func getDefaultUserRoles() []models.RoleEnum {
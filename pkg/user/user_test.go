@@ -3,6 +3,9 @@ package user_test
 import (
 	"testing"
 
+	"github.com/spf13/viper"
+
+	"github.com/stashapp/stash-box/pkg/manager/config"
 	"github.com/stashapp/stash-box/pkg/models"
 	. "github.com/stashapp/stash-box/pkg/user"
 )
@@ -205,3 +208,57 @@ func TestDestroyUser(t *testing.T) {
 		}
 	}
 }
+
+func TestRestrictRoles(t *testing.T) {
+	tests := []struct {
+		userRoles []models.RoleEnum
+		roles     []models.RoleEnum
+		want      []models.RoleEnum
+	}{
+		{[]models.RoleEnum{models.RoleEnumAdmin}, []models.RoleEnum{models.RoleEnumRead, models.RoleEnumEdit}, []models.RoleEnum{models.RoleEnumRead, models.RoleEnumEdit}},
+		{[]models.RoleEnum{models.RoleEnumEdit}, []models.RoleEnum{models.RoleEnumRead, models.RoleEnumModify}, []models.RoleEnum{models.RoleEnumRead}},
+		{[]models.RoleEnum{models.RoleEnumManageInvites}, []models.RoleEnum{models.RoleEnumInvite}, []models.RoleEnum{models.RoleEnumInvite}},
+		{nil, []models.RoleEnum{models.RoleEnumRead}, nil},
+	}
+
+	for _, v := range tests {
+		got := RestrictRoles(v.userRoles, v.roles)
+		if len(got) != len(v.want) {
+			t.Errorf("RestrictRoles(%v, %v) = %v; want %v", v.userRoles, v.roles, got, v.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != v.want[i] {
+				t.Errorf("RestrictRoles(%v, %v) = %v; want %v", v.userRoles, v.roles, got, v.want)
+				break
+			}
+		}
+	}
+}
+
+func TestIsNamedAPIKey(t *testing.T) {
+	if !IsNamedAPIKey(NamedAPIKeyPrefix + "abc") {
+		t.Error("expected prefixed key to be a named key")
+	}
+	if IsNamedAPIKey("eyJhbGciOiJIUzI1NiJ9.e30.abc") {
+		t.Error("expected JWT key not to be a named key")
+	}
+}
+
+func TestFilterTOTPRoles(t *testing.T) {
+	viper.Set(config.TOTPRequiredRoles, []string{"modify"})
+	defer viper.Set(config.TOTPRequiredRoles, nil)
+
+	roles := []models.RoleEnum{models.RoleEnumRead, models.RoleEnumEdit, models.RoleEnumAdmin}
+
+	u := &models.User{}
+	got := FilterTOTPRoles(u, roles)
+	if len(got) != 2 || got[0] != models.RoleEnumRead || got[1] != models.RoleEnumEdit {
+		t.Errorf("FilterTOTPRoles without two-factor = %v; want [READ EDIT]", got)
+	}
+
+	u.TOTPEnabled = true
+	if got := FilterTOTPRoles(u, roles); len(got) != len(roles) {
+		t.Errorf("FilterTOTPRoles with two-factor = %v; want %v", got, roles)
+	}
+}
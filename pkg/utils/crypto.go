@@ -3,6 +3,7 @@ package utils
 import (
 	"crypto/md5"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/ascii85"
 	"fmt"
 	"io"
@@ -19,6 +20,11 @@ func MD5FromString(str string) string {
 	return MD5FromBytes(data)
 }
 
+func SHA256FromString(str string) string {
+	result := sha256.Sum256([]byte(str))
+	return fmt.Sprintf("%x", result)
+}
+
 func MD5FromFilePath(filePath string) (string, error) {
 	f, err := os.Open(filePath)
 	if err != nil {
@@ -0,0 +1,117 @@
+package utils
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// idle buckets are removed at most once per rateLimiterSweepInterval
+const rateLimiterSweepInterval = 10 * time.Minute
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+	rate   float64
+	burst  int
+}
+
+// refill adds the tokens accrued since the bucket was last used.
+func (b *tokenBucket) refill(now time.Time) {
+	elapsed := now.Sub(b.last).Seconds()
+	if elapsed > 0 {
+		b.tokens = math.Min(float64(b.burst), b.tokens+elapsed*b.rate)
+		b.last = now
+	}
+}
+
+// RateLimitResult is the outcome of a call to RateLimiter.Allow.
+type RateLimitResult struct {
+	Allowed bool
+	// Maximum number of requests that can be made at once
+	Limit int
+	// Number of requests that may still be made
+	Remaining int
+	// Time until the next request is allowed. Zero if Allowed is true.
+	RetryAfter time.Duration
+	// Time until the bucket is full again
+	Reset time.Duration
+}
+
+// RateLimiter is a set of token buckets, keyed by string.
+type RateLimiter struct {
+	mutex     sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+func NewRateLimiter() *RateLimiter {
+	return &RateLimiter{
+		buckets: make(map[string]*tokenBucket),
+	}
+}
+
+// Allow takes a token from the bucket for key, refilled at rate tokens per
+// second up to burst tokens.
+func (l *RateLimiter) Allow(key string, rate float64, burst int) RateLimitResult {
+	return l.AllowAt(key, rate, burst, time.Now())
+}
+
+// AllowAt is Allow at the provided time.
+func (l *RateLimiter) AllowAt(key string, rate float64, burst int, now time.Time) RateLimitResult {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.sweep(now)
+
+	b := l.buckets[key]
+	if b == nil {
+		b = &tokenBucket{
+			tokens: float64(burst),
+			last:   now,
+		}
+		l.buckets[key] = b
+	}
+
+	// the limit may change if the roles of a user change
+	b.rate = rate
+	b.burst = burst
+	b.refill(now)
+
+	ret := RateLimitResult{
+		Limit: burst,
+	}
+
+	if b.tokens >= 1 {
+		b.tokens--
+		ret.Allowed = true
+	} else if rate > 0 {
+		ret.RetryAfter = secondsDuration((1 - b.tokens) / rate)
+	}
+
+	ret.Remaining = int(b.tokens)
+	if rate > 0 {
+		ret.Reset = secondsDuration((float64(burst) - b.tokens) / rate)
+	}
+
+	return ret
+}
+
+// sweep removes the buckets that have refilled completely.
+func (l *RateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < rateLimiterSweepInterval {
+		return
+	}
+	l.lastSweep = now
+
+	for k, b := range l.buckets {
+		b.refill(now)
+		if b.tokens >= float64(b.burst) {
+			delete(l.buckets, k)
+		}
+	}
+}
+
+func secondsDuration(s float64) time.Duration {
+	return time.Duration(math.Ceil(s * float64(time.Second)))
+}
@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	l := NewRateLimiter()
+	now := time.Now()
+
+	// burst of 2, one token per second
+	for i := 0; i < 2; i++ {
+		r := l.AllowAt("a", 1, 2, now)
+		if !r.Allowed {
+			t.Fatalf("request %d: expected to be allowed", i)
+		}
+		if r.Remaining != 1-i {
+			t.Errorf("request %d: remaining = %d, want %d", i, r.Remaining, 1-i)
+		}
+	}
+
+	r := l.AllowAt("a", 1, 2, now)
+	if r.Allowed {
+		t.Fatal("expected third request to be limited")
+	}
+	if r.RetryAfter != time.Second {
+		t.Errorf("retry after = %v, want %v", r.RetryAfter, time.Second)
+	}
+	if r.Reset != 2*time.Second {
+		t.Errorf("reset = %v, want %v", r.Reset, 2*time.Second)
+	}
+
+	// other keys have their own bucket
+	if r := l.AllowAt("b", 1, 2, now); !r.Allowed {
+		t.Error("expected request for other key to be allowed")
+	}
+
+	// a token is added after a second
+	if r := l.AllowAt("a", 1, 2, now.Add(time.Second)); !r.Allowed {
+		t.Error("expected request to be allowed after refill")
+	}
+	if r := l.AllowAt("a", 1, 2, now.Add(time.Second)); r.Allowed {
+		t.Error("expected request to be limited after using refilled token")
+	}
+
+	// idle buckets are removed
+	l.AllowAt("c", 1, 2, now.Add(rateLimiterSweepInterval+time.Second))
+	if _, found := l.buckets["a"]; found {
+		t.Error("expected idle bucket to be removed")
+	}
+}
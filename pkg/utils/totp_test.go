@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"encoding/base32"
+	"testing"
+	"time"
+)
+
+// test vectors from RFC 4226 appendix D
+func TestHOTP(t *testing.T) {
+	key := []byte("12345678901234567890")
+	expected := []string{
+		"755224", "287082", "359152", "969429", "338314",
+		"254676", "287922", "162583", "399871", "520489",
+	}
+
+	for i, want := range expected {
+		if got := HOTP(key, uint64(i), 6); got != want {
+			t.Errorf("HOTP(counter %d) = %s; want %s", i, got, want)
+		}
+	}
+}
+
+// SHA1 test vectors from RFC 6238 appendix B
+func TestTOTPCode(t *testing.T) {
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, test := range tests {
+		got, err := TOTPCode(secret, TOTPStep(time.Unix(test.unix, 0)))
+		if err != nil {
+			t.Fatalf("TOTPCode error: %s", err.Error())
+		}
+		if got != test.want {
+			t.Errorf("TOTPCode(%d) = %s; want %s", test.unix, got, test.want)
+		}
+	}
+}
+
+func TestMatchTOTP(t *testing.T) {
+	secret := GenerateTOTPSecret()
+	now := time.Unix(1600000000, 0)
+	step := TOTPStep(now)
+
+	previous, _ := TOTPCode(secret, step-1)
+	if matched, ok, _ := MatchTOTP(secret, previous, now, 1); !ok || matched != step-1 {
+		t.Errorf("expected code of previous step to match step %d, got %d, %v", step-1, matched, ok)
+	}
+
+	old, _ := TOTPCode(secret, step-2)
+	if _, ok, _ := MatchTOTP(secret, old, now, 1); ok {
+		t.Error("expected code outside skew not to match")
+	}
+
+	if _, _, err := MatchTOTP("not base32!", "123456", now, 1); err == nil {
+		t.Error("expected error for invalid secret")
+	}
+}
+
+func TestTOTPProvisioningURI(t *testing.T) {
+	got := TOTPProvisioningURI("JBSWY3DPEHPK3PXP", "stash-box", "some user")
+	want := "otpauth://totp/stash-box:some%20user?algorithm=SHA1&digits=6&issuer=stash-box&period=30&secret=JBSWY3DPEHPK3PXP"
+	if got != want {
+		t.Errorf("TOTPProvisioningURI = %s; want %s", got, want)
+	}
+}
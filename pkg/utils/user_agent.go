@@ -0,0 +1,57 @@
+package utils
+
+import "strings"
+
+type userAgentToken struct {
+	token string
+	name  string
+}
+
+// ordered so that more specific tokens are matched first, since most
+// browsers include the tokens of others in their user agent
+var userAgentBrowsers = []userAgentToken{
+	{"Edg/", "Edge"},
+	{"OPR/", "Opera"},
+	{"Firefox/", "Firefox"},
+	{"Chrome/", "Chrome"},
+	{"Safari/", "Safari"},
+	{"curl/", "curl"},
+	{"python-requests/", "Python"},
+	{"Go-http-client/", "Go"},
+}
+
+var userAgentSystems = []userAgentToken{
+	{"Android", "Android"},
+	{"iPhone", "iOS"},
+	{"iPad", "iOS"},
+	{"Windows", "Windows"},
+	{"Mac OS X", "macOS"},
+	{"CrOS", "Chrome OS"},
+	{"Linux", "Linux"},
+}
+
+func findUserAgentToken(userAgent string, tokens []userAgentToken) string {
+	for _, t := range tokens {
+		if strings.Contains(userAgent, t.token) {
+			return t.name
+		}
+	}
+	return ""
+}
+
+// DescribeUserAgent returns a short description of the browser and operating
+// system of a user agent string, such as "Firefox on Windows". Returns an
+// empty string if neither is recognised.
+func DescribeUserAgent(userAgent string) string {
+	browser := findUserAgentToken(userAgent, userAgentBrowsers)
+	system := findUserAgentToken(userAgent, userAgentSystems)
+
+	switch {
+	case browser != "" && system != "":
+		return browser + " on " + system
+	case browser != "":
+		return browser
+	default:
+		return system
+	}
+}